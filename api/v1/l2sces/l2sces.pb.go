@@ -209,10 +209,15 @@ func (x *Node) GetIpAddress() string {
 	return ""
 }
 
+// Credentials of a member cluster. Clusters registered in the management cluster need none; if given,
+// each field overrides the registered one.
 type RestConfig struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	BearerToken   string                 `protobuf:"bytes,1,opt,name=bearer_token,json=bearerToken,proto3" json:"bearer_token,omitempty"`
-	ApiKey        string                 `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	BearerToken string                 `protobuf:"bytes,1,opt,name=bearer_token,json=bearerToken,proto3" json:"bearer_token,omitempty"`
+	// URL of the API server, kept for compatibility. Prefer server.
+	ApiKey string `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// URL of the API server.
+	Server        string `protobuf:"bytes,3,opt,name=server,proto3" json:"server,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -261,6 +266,13 @@ func (x *RestConfig) GetApiKey() string {
 	return ""
 }
 
+func (x *RestConfig) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
 type Cluster struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -269,8 +281,10 @@ type Cluster struct {
 	GatewayNode    *Node                  `protobuf:"bytes,4,opt,name=gateway_node,json=gatewayNode,proto3" json:"gateway_node,omitempty"`
 	Namespace      string                 `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	PodAddressPool string                 `protobuf:"bytes,6,opt,name=pod_address_pool,json=podAddressPool,proto3" json:"pod_address_pool,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Region of the cluster. The RegionalMesh topology meshes the clusters of each region.
+	Region        string `protobuf:"bytes,7,opt,name=region,proto3" json:"region,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Cluster) Reset() {
@@ -345,6 +359,13 @@ func (x *Cluster) GetPodAddressPool() string {
 	return ""
 }
 
+func (x *Cluster) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
 type Overlay struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Provider      *Provider              `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
@@ -481,18 +502,168 @@ func (x *L2Network) GetClusters() []*Cluster {
 	return nil
 }
 
-type Slice struct {
+// Cost of a candidate link between two clusters, such as their round-trip time in milliseconds.
+type LinkCost struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Provider      *Provider              `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
-	Clusters      []*Cluster             `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
-	Links         []*Link                `protobuf:"bytes,3,rep,name=links,proto3" json:"links,omitempty"`
+	EndpointA     string                 `protobuf:"bytes,1,opt,name=endpointA,proto3" json:"endpointA,omitempty"`
+	EndpointB     string                 `protobuf:"bytes,2,opt,name=endpointB,proto3" json:"endpointB,omitempty"`
+	Cost          float64                `protobuf:"fixed64,3,opt,name=cost,proto3" json:"cost,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkCost) Reset() {
+	*x = LinkCost{}
+	mi := &file_l2sces_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkCost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkCost) ProtoMessage() {}
+
+func (x *LinkCost) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkCost.ProtoReflect.Descriptor instead.
+func (*LinkCost) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LinkCost) GetEndpointA() string {
+	if x != nil {
+		return x.EndpointA
+	}
+	return ""
+}
+
+func (x *LinkCost) GetEndpointB() string {
+	if x != nil {
+		return x.EndpointB
+	}
+	return ""
+}
+
+func (x *LinkCost) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+// Strategy generating the links of a slice that lists none. Clusters are taken in name order, so the
+// links don't depend on the order they are given in.
+type Topology struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// FullMesh, Star, Ring, Line, KRegular, RegionalMesh or MinimumCost. Defaults to FullMesh.
+	Strategy string `protobuf:"bytes,1,opt,name=strategy,proto3" json:"strategy,omitempty"`
+	// Hub of Star, or of its region with RegionalMesh. Defaults to the first cluster by name.
+	Hub string `protobuf:"bytes,2,opt,name=hub,proto3" json:"hub,omitempty"`
+	// Number of neighbors of every cluster with KRegular.
+	Degree int32 `protobuf:"varint,3,opt,name=degree,proto3" json:"degree,omitempty"`
+	// Candidate links of MinimumCost. Clusters without a cost between them are never linked directly.
+	Costs []*LinkCost `protobuf:"bytes,4,rep,name=costs,proto3" json:"costs,omitempty"`
+	// Number of link-disjoint paths MinimumCost keeps between every two clusters, at most one less than
+	// the number of clusters. Defaults to 2, so that the slice survives the failure of any link; 1 is the
+	// minimum spanning tree.
+	Redundancy    int32 `protobuf:"varint,5,opt,name=redundancy,proto3" json:"redundancy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Topology) Reset() {
+	*x = Topology{}
+	mi := &file_l2sces_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Topology) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Topology) ProtoMessage() {}
+
+func (x *Topology) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Topology.ProtoReflect.Descriptor instead.
+func (*Topology) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Topology) GetStrategy() string {
+	if x != nil {
+		return x.Strategy
+	}
+	return ""
+}
+
+func (x *Topology) GetHub() string {
+	if x != nil {
+		return x.Hub
+	}
+	return ""
+}
+
+func (x *Topology) GetDegree() int32 {
+	if x != nil {
+		return x.Degree
+	}
+	return 0
+}
+
+func (x *Topology) GetCosts() []*LinkCost {
+	if x != nil {
+		return x.Costs
+	}
+	return nil
+}
+
+func (x *Topology) GetRedundancy() int32 {
+	if x != nil {
+		return x.Redundancy
+	}
+	return 0
+}
+
+type Slice struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Provider *Provider              `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Clusters []*Cluster             `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	Links    []*Link                `protobuf:"bytes,3,rep,name=links,proto3" json:"links,omitempty"`
+	// Name the slice is registered with. Defaults to the provider name.
+	Name string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	// Generates the links when none are given. Defaults to a full mesh.
+	Topology      *Topology `protobuf:"bytes,5,opt,name=topology,proto3" json:"topology,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Slice) Reset() {
 	*x = Slice{}
-	mi := &file_l2sces_proto_msgTypes[7]
+	mi := &file_l2sces_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -504,7 +675,7 @@ func (x *Slice) String() string {
 func (*Slice) ProtoMessage() {}
 
 func (x *Slice) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[7]
+	mi := &file_l2sces_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -517,7 +688,7 @@ func (x *Slice) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Slice.ProtoReflect.Descriptor instead.
 func (*Slice) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{7}
+	return file_l2sces_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Slice) GetProvider() *Provider {
@@ -541,6 +712,101 @@ func (x *Slice) GetLinks() []*Link {
 	return nil
 }
 
+func (x *Slice) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Slice) GetTopology() *Topology {
+	if x != nil {
+		return x.Topology
+	}
+	return nil
+}
+
+// State a cluster was left in by a create or delete request.
+type ClusterResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Cluster string                 `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// One of Applied, NotApplied, Failed, RolledBack or Partial for creates, and Deleted or Failed for deletes.
+	State string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	// Objects left in the cluster by a create, or removed from it by a delete, as kind/name.
+	Objects []string `protobuf:"bytes,3,rep,name=objects,proto3" json:"objects,omitempty"`
+	Error   string   `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// Status reported back by the cluster for the applied objects, by kind/name.field. Only filled when the
+	// server reaches the clusters through Open Cluster Management.
+	Status        map[string]string `protobuf:"bytes,5,rep,name=status,proto3" json:"status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterResult) Reset() {
+	*x = ClusterResult{}
+	mi := &file_l2sces_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterResult) ProtoMessage() {}
+
+func (x *ClusterResult) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterResult.ProtoReflect.Descriptor instead.
+func (*ClusterResult) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ClusterResult) GetCluster() string {
+	if x != nil {
+		return x.Cluster
+	}
+	return ""
+}
+
+func (x *ClusterResult) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ClusterResult) GetObjects() []string {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *ClusterResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ClusterResult) GetStatus() map[string]string {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
 // Requests and Responses for Network
 type CreateNetworkRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -552,7 +818,7 @@ type CreateNetworkRequest struct {
 
 func (x *CreateNetworkRequest) Reset() {
 	*x = CreateNetworkRequest{}
-	mi := &file_l2sces_proto_msgTypes[8]
+	mi := &file_l2sces_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -564,7 +830,7 @@ func (x *CreateNetworkRequest) String() string {
 func (*CreateNetworkRequest) ProtoMessage() {}
 
 func (x *CreateNetworkRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[8]
+	mi := &file_l2sces_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -577,7 +843,7 @@ func (x *CreateNetworkRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateNetworkRequest.ProtoReflect.Descriptor instead.
 func (*CreateNetworkRequest) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{8}
+	return file_l2sces_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *CreateNetworkRequest) GetNetwork() *L2Network {
@@ -608,7 +874,7 @@ type FieldPatch struct {
 
 func (x *FieldPatch) Reset() {
 	*x = FieldPatch{}
-	mi := &file_l2sces_proto_msgTypes[9]
+	mi := &file_l2sces_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -620,7 +886,7 @@ func (x *FieldPatch) String() string {
 func (*FieldPatch) ProtoMessage() {}
 
 func (x *FieldPatch) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[9]
+	mi := &file_l2sces_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -633,7 +899,7 @@ func (x *FieldPatch) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FieldPatch.ProtoReflect.Descriptor instead.
 func (*FieldPatch) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{9}
+	return file_l2sces_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *FieldPatch) GetPath() string {
@@ -654,13 +920,14 @@ type CreateNetworkResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	Patches       []*FieldPatch          `protobuf:"bytes,2,rep,name=patches,proto3" json:"patches,omitempty"`
+	Clusters      []*ClusterResult       `protobuf:"bytes,3,rep,name=clusters,proto3" json:"clusters,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateNetworkResponse) Reset() {
 	*x = CreateNetworkResponse{}
-	mi := &file_l2sces_proto_msgTypes[10]
+	mi := &file_l2sces_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -672,7 +939,7 @@ func (x *CreateNetworkResponse) String() string {
 func (*CreateNetworkResponse) ProtoMessage() {}
 
 func (x *CreateNetworkResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[10]
+	mi := &file_l2sces_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -685,7 +952,7 @@ func (x *CreateNetworkResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateNetworkResponse.ProtoReflect.Descriptor instead.
 func (*CreateNetworkResponse) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{10}
+	return file_l2sces_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *CreateNetworkResponse) GetMessage() string {
@@ -702,6 +969,13 @@ func (x *CreateNetworkResponse) GetPatches() []*FieldPatch {
 	return nil
 }
 
+func (x *CreateNetworkResponse) GetClusters() []*ClusterResult {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
 type DeleteNetworkRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Network       *L2Network             `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
@@ -712,7 +986,7 @@ type DeleteNetworkRequest struct {
 
 func (x *DeleteNetworkRequest) Reset() {
 	*x = DeleteNetworkRequest{}
-	mi := &file_l2sces_proto_msgTypes[11]
+	mi := &file_l2sces_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -724,7 +998,7 @@ func (x *DeleteNetworkRequest) String() string {
 func (*DeleteNetworkRequest) ProtoMessage() {}
 
 func (x *DeleteNetworkRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[11]
+	mi := &file_l2sces_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -737,7 +1011,7 @@ func (x *DeleteNetworkRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteNetworkRequest.ProtoReflect.Descriptor instead.
 func (*DeleteNetworkRequest) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{11}
+	return file_l2sces_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *DeleteNetworkRequest) GetNetwork() *L2Network {
@@ -757,13 +1031,14 @@ func (x *DeleteNetworkRequest) GetNamespace() string {
 type DeleteNetworkResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Clusters      []*ClusterResult       `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteNetworkResponse) Reset() {
 	*x = DeleteNetworkResponse{}
-	mi := &file_l2sces_proto_msgTypes[12]
+	mi := &file_l2sces_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -775,7 +1050,7 @@ func (x *DeleteNetworkResponse) String() string {
 func (*DeleteNetworkResponse) ProtoMessage() {}
 
 func (x *DeleteNetworkResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[12]
+	mi := &file_l2sces_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -788,7 +1063,7 @@ func (x *DeleteNetworkResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteNetworkResponse.ProtoReflect.Descriptor instead.
 func (*DeleteNetworkResponse) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{12}
+	return file_l2sces_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *DeleteNetworkResponse) GetMessage() string {
@@ -798,6 +1073,13 @@ func (x *DeleteNetworkResponse) GetMessage() string {
 	return ""
 }
 
+func (x *DeleteNetworkResponse) GetClusters() []*ClusterResult {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
 // Requests and Responses for Slice
 type CreateSliceRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -809,7 +1091,7 @@ type CreateSliceRequest struct {
 
 func (x *CreateSliceRequest) Reset() {
 	*x = CreateSliceRequest{}
-	mi := &file_l2sces_proto_msgTypes[13]
+	mi := &file_l2sces_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -821,7 +1103,7 @@ func (x *CreateSliceRequest) String() string {
 func (*CreateSliceRequest) ProtoMessage() {}
 
 func (x *CreateSliceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[13]
+	mi := &file_l2sces_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -834,7 +1116,7 @@ func (x *CreateSliceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateSliceRequest.ProtoReflect.Descriptor instead.
 func (*CreateSliceRequest) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{13}
+	return file_l2sces_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *CreateSliceRequest) GetSlice() *Slice {
@@ -852,15 +1134,22 @@ func (x *CreateSliceRequest) GetNamespace() string {
 }
 
 type CreateSliceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Message  string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Clusters []*ClusterResult       `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	// Links between the clusters, as given or generated by the topology.
+	Links []*Link `protobuf:"bytes,3,rep,name=links,proto3" json:"links,omitempty"`
+	// Sum of the costs of the links, for the ones with a known cost.
+	Cost float64 `protobuf:"fixed64,4,opt,name=cost,proto3" json:"cost,omitempty"`
+	// Single points of failure of the slice: the clusters and links that split it if they fail.
+	Warnings      []string `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateSliceResponse) Reset() {
 	*x = CreateSliceResponse{}
-	mi := &file_l2sces_proto_msgTypes[14]
+	mi := &file_l2sces_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -872,7 +1161,7 @@ func (x *CreateSliceResponse) String() string {
 func (*CreateSliceResponse) ProtoMessage() {}
 
 func (x *CreateSliceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[14]
+	mi := &file_l2sces_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -885,7 +1174,7 @@ func (x *CreateSliceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateSliceResponse.ProtoReflect.Descriptor instead.
 func (*CreateSliceResponse) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{14}
+	return file_l2sces_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *CreateSliceResponse) GetMessage() string {
@@ -895,19 +1184,47 @@ func (x *CreateSliceResponse) GetMessage() string {
 	return ""
 }
 
-type DeleteSliceRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Slice         *Slice                 `protobuf:"bytes,1,opt,name=slice,proto3" json:"slice,omitempty"`
-	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *DeleteSliceRequest) Reset() {
-	*x = DeleteSliceRequest{}
-	mi := &file_l2sces_proto_msgTypes[15]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *CreateSliceResponse) GetClusters() []*ClusterResult {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+func (x *CreateSliceResponse) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *CreateSliceResponse) GetCost() float64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *CreateSliceResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+type DeleteSliceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slice         *Slice                 `protobuf:"bytes,1,opt,name=slice,proto3" json:"slice,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSliceRequest) Reset() {
+	*x = DeleteSliceRequest{}
+	mi := &file_l2sces_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *DeleteSliceRequest) String() string {
@@ -917,7 +1234,7 @@ func (x *DeleteSliceRequest) String() string {
 func (*DeleteSliceRequest) ProtoMessage() {}
 
 func (x *DeleteSliceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[15]
+	mi := &file_l2sces_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -930,7 +1247,7 @@ func (x *DeleteSliceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteSliceRequest.ProtoReflect.Descriptor instead.
 func (*DeleteSliceRequest) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{15}
+	return file_l2sces_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *DeleteSliceRequest) GetSlice() *Slice {
@@ -950,13 +1267,14 @@ func (x *DeleteSliceRequest) GetNamespace() string {
 type DeleteSliceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Clusters      []*ClusterResult       `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteSliceResponse) Reset() {
 	*x = DeleteSliceResponse{}
-	mi := &file_l2sces_proto_msgTypes[16]
+	mi := &file_l2sces_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -968,7 +1286,7 @@ func (x *DeleteSliceResponse) String() string {
 func (*DeleteSliceResponse) ProtoMessage() {}
 
 func (x *DeleteSliceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[16]
+	mi := &file_l2sces_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -981,7 +1299,7 @@ func (x *DeleteSliceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteSliceResponse.ProtoReflect.Descriptor instead.
 func (*DeleteSliceResponse) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{16}
+	return file_l2sces_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *DeleteSliceResponse) GetMessage() string {
@@ -991,17 +1309,28 @@ func (x *DeleteSliceResponse) GetMessage() string {
 	return ""
 }
 
+func (x *DeleteSliceResponse) GetClusters() []*ClusterResult {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
 // Requests and Responses for Overlays (existing)
 type CreateOverlayRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Overlay       *Overlay               `protobuf:"bytes,1,opt,name=overlay,proto3" json:"overlay,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Overlay between clusters: its links join cluster names. A full mesh is used if no links are given.
+	Overlay *Overlay `protobuf:"bytes,1,opt,name=overlay,proto3" json:"overlay,omitempty"`
+	// Clusters joined by the overlay.
+	Clusters      []*Cluster `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	Namespace     string     `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateOverlayRequest) Reset() {
 	*x = CreateOverlayRequest{}
-	mi := &file_l2sces_proto_msgTypes[17]
+	mi := &file_l2sces_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1013,7 +1342,7 @@ func (x *CreateOverlayRequest) String() string {
 func (*CreateOverlayRequest) ProtoMessage() {}
 
 func (x *CreateOverlayRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[17]
+	mi := &file_l2sces_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1026,7 +1355,7 @@ func (x *CreateOverlayRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOverlayRequest.ProtoReflect.Descriptor instead.
 func (*CreateOverlayRequest) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{17}
+	return file_l2sces_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *CreateOverlayRequest) GetOverlay() *Overlay {
@@ -1036,16 +1365,33 @@ func (x *CreateOverlayRequest) GetOverlay() *Overlay {
 	return nil
 }
 
+func (x *CreateOverlayRequest) GetClusters() []*Cluster {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+func (x *CreateOverlayRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
 type CreateOverlayResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Message  string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Clusters []*ClusterResult       `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	// Single points of failure of the overlay: the clusters and links that split it if they fail.
+	Warnings      []string `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateOverlayResponse) Reset() {
 	*x = CreateOverlayResponse{}
-	mi := &file_l2sces_proto_msgTypes[18]
+	mi := &file_l2sces_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1057,7 +1403,7 @@ func (x *CreateOverlayResponse) String() string {
 func (*CreateOverlayResponse) ProtoMessage() {}
 
 func (x *CreateOverlayResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[18]
+	mi := &file_l2sces_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1070,7 +1416,7 @@ func (x *CreateOverlayResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOverlayResponse.ProtoReflect.Descriptor instead.
 func (*CreateOverlayResponse) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{18}
+	return file_l2sces_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *CreateOverlayResponse) GetMessage() string {
@@ -1080,19 +1426,38 @@ func (x *CreateOverlayResponse) GetMessage() string {
 	return ""
 }
 
+func (x *CreateOverlayResponse) GetClusters() []*ClusterResult {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+func (x *CreateOverlayResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
 type AddClusterRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	ProviderName   string                 `protobuf:"bytes,1,opt,name=provider_name,json=providerName,proto3" json:"provider_name,omitempty"`
 	ProviderDomain string                 `protobuf:"bytes,2,opt,name=provider_domain,json=providerDomain,proto3" json:"provider_domain,omitempty"`
 	SliceName      string                 `protobuf:"bytes,3,opt,name=slice_name,json=sliceName,proto3" json:"slice_name,omitempty"`
 	Cluster        *Cluster               `protobuf:"bytes,4,opt,name=cluster,proto3" json:"cluster,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Current slice the cluster joins. Its provider defaults to provider_name and provider_domain.
+	Slice *Slice `protobuf:"bytes,5,opt,name=slice,proto3" json:"slice,omitempty"`
+	// Links of the new cluster. If empty, it is linked to every cluster of the slice.
+	Links         []*Link `protobuf:"bytes,6,rep,name=links,proto3" json:"links,omitempty"`
+	Namespace     string  `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *AddClusterRequest) Reset() {
 	*x = AddClusterRequest{}
-	mi := &file_l2sces_proto_msgTypes[19]
+	mi := &file_l2sces_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1104,7 +1469,7 @@ func (x *AddClusterRequest) String() string {
 func (*AddClusterRequest) ProtoMessage() {}
 
 func (x *AddClusterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[19]
+	mi := &file_l2sces_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1117,7 +1482,7 @@ func (x *AddClusterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddClusterRequest.ProtoReflect.Descriptor instead.
 func (*AddClusterRequest) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{19}
+	return file_l2sces_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *AddClusterRequest) GetProviderName() string {
@@ -1148,6 +1513,27 @@ func (x *AddClusterRequest) GetCluster() *Cluster {
 	return nil
 }
 
+func (x *AddClusterRequest) GetSlice() *Slice {
+	if x != nil {
+		return x.Slice
+	}
+	return nil
+}
+
+func (x *AddClusterRequest) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+func (x *AddClusterRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
 type AddClusterResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
@@ -1157,7 +1543,7 @@ type AddClusterResponse struct {
 
 func (x *AddClusterResponse) Reset() {
 	*x = AddClusterResponse{}
-	mi := &file_l2sces_proto_msgTypes[20]
+	mi := &file_l2sces_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1169,7 +1555,7 @@ func (x *AddClusterResponse) String() string {
 func (*AddClusterResponse) ProtoMessage() {}
 
 func (x *AddClusterResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[20]
+	mi := &file_l2sces_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1182,7 +1568,7 @@ func (x *AddClusterResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AddClusterResponse.ProtoReflect.Descriptor instead.
 func (*AddClusterResponse) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{20}
+	return file_l2sces_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *AddClusterResponse) GetMessage() string {
@@ -1198,13 +1584,16 @@ type RemoveClusterRequest struct {
 	ProviderDomain string                 `protobuf:"bytes,2,opt,name=provider_domain,json=providerDomain,proto3" json:"provider_domain,omitempty"`
 	OverlayName    string                 `protobuf:"bytes,3,opt,name=overlay_name,json=overlayName,proto3" json:"overlay_name,omitempty"`
 	ClusterName    string                 `protobuf:"bytes,4,opt,name=cluster_name,json=clusterName,proto3" json:"cluster_name,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Current slice the cluster leaves. Its provider defaults to provider_name and provider_domain.
+	Slice         *Slice `protobuf:"bytes,5,opt,name=slice,proto3" json:"slice,omitempty"`
+	Namespace     string `protobuf:"bytes,6,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RemoveClusterRequest) Reset() {
 	*x = RemoveClusterRequest{}
-	mi := &file_l2sces_proto_msgTypes[21]
+	mi := &file_l2sces_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1216,7 +1605,7 @@ func (x *RemoveClusterRequest) String() string {
 func (*RemoveClusterRequest) ProtoMessage() {}
 
 func (x *RemoveClusterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[21]
+	mi := &file_l2sces_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1229,7 +1618,7 @@ func (x *RemoveClusterRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveClusterRequest.ProtoReflect.Descriptor instead.
 func (*RemoveClusterRequest) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{21}
+	return file_l2sces_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *RemoveClusterRequest) GetProviderName() string {
@@ -1260,6 +1649,20 @@ func (x *RemoveClusterRequest) GetClusterName() string {
 	return ""
 }
 
+func (x *RemoveClusterRequest) GetSlice() *Slice {
+	if x != nil {
+		return x.Slice
+	}
+	return nil
+}
+
+func (x *RemoveClusterRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
 type RemoveClusterResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
@@ -1269,7 +1672,7 @@ type RemoveClusterResponse struct {
 
 func (x *RemoveClusterResponse) Reset() {
 	*x = RemoveClusterResponse{}
-	mi := &file_l2sces_proto_msgTypes[22]
+	mi := &file_l2sces_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1281,7 +1684,7 @@ func (x *RemoveClusterResponse) String() string {
 func (*RemoveClusterResponse) ProtoMessage() {}
 
 func (x *RemoveClusterResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[22]
+	mi := &file_l2sces_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1294,7 +1697,7 @@ func (x *RemoveClusterResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RemoveClusterResponse.ProtoReflect.Descriptor instead.
 func (*RemoveClusterResponse) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{22}
+	return file_l2sces_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *RemoveClusterResponse) GetMessage() string {
@@ -1309,13 +1712,16 @@ type DeleteOverlayRequest struct {
 	ProviderName   string                 `protobuf:"bytes,1,opt,name=provider_name,json=providerName,proto3" json:"provider_name,omitempty"`
 	ProviderDomain string                 `protobuf:"bytes,2,opt,name=provider_domain,json=providerDomain,proto3" json:"provider_domain,omitempty"`
 	OverlayName    string                 `protobuf:"bytes,3,opt,name=overlay_name,json=overlayName,proto3" json:"overlay_name,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Slice to delete. Its provider defaults to provider_name and provider_domain.
+	Slice         *Slice `protobuf:"bytes,4,opt,name=slice,proto3" json:"slice,omitempty"`
+	Namespace     string `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteOverlayRequest) Reset() {
 	*x = DeleteOverlayRequest{}
-	mi := &file_l2sces_proto_msgTypes[23]
+	mi := &file_l2sces_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1327,7 +1733,7 @@ func (x *DeleteOverlayRequest) String() string {
 func (*DeleteOverlayRequest) ProtoMessage() {}
 
 func (x *DeleteOverlayRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[23]
+	mi := &file_l2sces_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1340,7 +1746,7 @@ func (x *DeleteOverlayRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteOverlayRequest.ProtoReflect.Descriptor instead.
 func (*DeleteOverlayRequest) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{23}
+	return file_l2sces_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *DeleteOverlayRequest) GetProviderName() string {
@@ -1364,16 +1770,31 @@ func (x *DeleteOverlayRequest) GetOverlayName() string {
 	return ""
 }
 
+func (x *DeleteOverlayRequest) GetSlice() *Slice {
+	if x != nil {
+		return x.Slice
+	}
+	return nil
+}
+
+func (x *DeleteOverlayRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
 type DeleteOverlayResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Clusters      []*ClusterResult       `protobuf:"bytes,2,rep,name=clusters,proto3" json:"clusters,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteOverlayResponse) Reset() {
 	*x = DeleteOverlayResponse{}
-	mi := &file_l2sces_proto_msgTypes[24]
+	mi := &file_l2sces_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1385,7 +1806,7 @@ func (x *DeleteOverlayResponse) String() string {
 func (*DeleteOverlayResponse) ProtoMessage() {}
 
 func (x *DeleteOverlayResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_l2sces_proto_msgTypes[24]
+	mi := &file_l2sces_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1398,7 +1819,7 @@ func (x *DeleteOverlayResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteOverlayResponse.ProtoReflect.Descriptor instead.
 func (*DeleteOverlayResponse) Descriptor() ([]byte, []int) {
-	return file_l2sces_proto_rawDescGZIP(), []int{24}
+	return file_l2sces_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *DeleteOverlayResponse) GetMessage() string {
@@ -1408,111 +1829,932 @@ func (x *DeleteOverlayResponse) GetMessage() string {
 	return ""
 }
 
-var File_l2sces_proto protoreflect.FileDescriptor
+func (x *DeleteOverlayResponse) GetClusters() []*ClusterResult {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
 
-const file_l2sces_proto_rawDesc = "" +
-	"\n" +
-	"\fl2sces.proto\x12\x06l2sces\"\xa9\x01\n" +
-	"\bProvider\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
-	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x19\n" +
-	"\bdns_port\x18\x03 \x01(\tR\adnsPort\x12\x19\n" +
-	"\bsdn_port\x18\x04 \x01(\tR\asdnPort\x12\x17\n" +
-	"\aof_port\x18\x05 \x01(\tR\x06ofPort\x12\"\n" +
-	"\rdns_grpc_port\x18\x06 \x01(\tR\vdnsGrpcPort\"B\n" +
-	"\x04Link\x12\x1c\n" +
-	"\tendpointA\x18\x01 \x01(\tR\tendpointA\x12\x1c\n" +
-	"\tendpointB\x18\x02 \x01(\tR\tendpointB\"9\n" +
-	"\x04Node\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
-	"\n" +
-	"ip_address\x18\x02 \x01(\tR\tipAddress\"H\n" +
-	"\n" +
-	"RestConfig\x12!\n" +
-	"\fbearer_token\x18\x01 \x01(\tR\vbearerToken\x12\x17\n" +
-	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\"\xf6\x01\n" +
-	"\aCluster\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x123\n" +
-	"\vrest_config\x18\x02 \x01(\v2\x12.l2sces.RestConfigR\n" +
-	"restConfig\x12)\n" +
-	"\aoverlay\x18\x03 \x01(\v2\x0f.l2sces.OverlayR\aoverlay\x12/\n" +
-	"\fgateway_node\x18\x04 \x01(\v2\f.l2sces.NodeR\vgatewayNode\x12\x1c\n" +
-	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12(\n" +
-	"\x10pod_address_pool\x18\x06 \x01(\tR\x0epodAddressPool\"q\n" +
-	"\aOverlay\x12,\n" +
-	"\bprovider\x18\x01 \x01(\v2\x10.l2sces.ProviderR\bprovider\x12\x14\n" +
-	"\x05nodes\x18\x02 \x03(\tR\x05nodes\x12\"\n" +
-	"\x05links\x18\x03 \x03(\v2\f.l2sces.LinkR\x05links\"\xa9\x01\n" +
-	"\tL2Network\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12,\n" +
-	"\bprovider\x18\x02 \x01(\v2\x10.l2sces.ProviderR\bprovider\x12\x19\n" +
-	"\bpod_cidr\x18\x03 \x01(\tR\apodCidr\x12\x12\n" +
-	"\x04type\x18\x04 \x01(\tR\x04type\x12+\n" +
-	"\bclusters\x18\x05 \x03(\v2\x0f.l2sces.ClusterR\bclusters\"\x86\x01\n" +
-	"\x05Slice\x12,\n" +
-	"\bprovider\x18\x01 \x01(\v2\x10.l2sces.ProviderR\bprovider\x12+\n" +
-	"\bclusters\x18\x02 \x03(\v2\x0f.l2sces.ClusterR\bclusters\x12\"\n" +
-	"\x05links\x18\x03 \x03(\v2\f.l2sces.LinkR\x05links\"a\n" +
-	"\x14CreateNetworkRequest\x12+\n" +
-	"\anetwork\x18\x01 \x01(\v2\x11.l2sces.L2NetworkR\anetwork\x12\x1c\n" +
-	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"6\n" +
-	"\n" +
-	"FieldPatch\x12\x12\n" +
-	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"_\n" +
-	"\x15CreateNetworkResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\x12,\n" +
-	"\apatches\x18\x02 \x03(\v2\x12.l2sces.FieldPatchR\apatches\"a\n" +
-	"\x14DeleteNetworkRequest\x12+\n" +
-	"\anetwork\x18\x01 \x01(\v2\x11.l2sces.L2NetworkR\anetwork\x12\x1c\n" +
-	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"1\n" +
-	"\x15DeleteNetworkResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"W\n" +
-	"\x12CreateSliceRequest\x12#\n" +
-	"\x05slice\x18\x01 \x01(\v2\r.l2sces.SliceR\x05slice\x12\x1c\n" +
-	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"/\n" +
-	"\x13CreateSliceResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"W\n" +
-	"\x12DeleteSliceRequest\x12#\n" +
-	"\x05slice\x18\x01 \x01(\v2\r.l2sces.SliceR\x05slice\x12\x1c\n" +
-	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"/\n" +
-	"\x13DeleteSliceResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"A\n" +
-	"\x14CreateOverlayRequest\x12)\n" +
-	"\aoverlay\x18\x01 \x01(\v2\x0f.l2sces.OverlayR\aoverlay\"1\n" +
-	"\x15CreateOverlayResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"\xab\x01\n" +
-	"\x11AddClusterRequest\x12#\n" +
-	"\rprovider_name\x18\x01 \x01(\tR\fproviderName\x12'\n" +
-	"\x0fprovider_domain\x18\x02 \x01(\tR\x0eproviderDomain\x12\x1d\n" +
-	"\n" +
-	"slice_name\x18\x03 \x01(\tR\tsliceName\x12)\n" +
-	"\acluster\x18\x04 \x01(\v2\x0f.l2sces.ClusterR\acluster\".\n" +
-	"\x12AddClusterResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"\xaa\x01\n" +
-	"\x14RemoveClusterRequest\x12#\n" +
-	"\rprovider_name\x18\x01 \x01(\tR\fproviderName\x12'\n" +
-	"\x0fprovider_domain\x18\x02 \x01(\tR\x0eproviderDomain\x12!\n" +
-	"\foverlay_name\x18\x03 \x01(\tR\voverlayName\x12!\n" +
-	"\fcluster_name\x18\x04 \x01(\tR\vclusterName\"1\n" +
-	"\x15RemoveClusterResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"\x87\x01\n" +
-	"\x14DeleteOverlayRequest\x12#\n" +
-	"\rprovider_name\x18\x01 \x01(\tR\fproviderName\x12'\n" +
-	"\x0fprovider_domain\x18\x02 \x01(\tR\x0eproviderDomain\x12!\n" +
-	"\foverlay_name\x18\x03 \x01(\tR\voverlayName\"1\n" +
-	"\x15DeleteOverlayResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2\xf3\x04\n" +
-	"\x16L2SMMultiDomainService\x12L\n" +
-	"\rCreateNetwork\x12\x1c.l2sces.CreateNetworkRequest\x1a\x1d.l2sces.CreateNetworkResponse\x12L\n" +
-	"\rDeleteNetwork\x12\x1c.l2sces.DeleteNetworkRequest\x1a\x1d.l2sces.DeleteNetworkResponse\x12F\n" +
-	"\vCreateSlice\x12\x1a.l2sces.CreateSliceRequest\x1a\x1b.l2sces.CreateSliceResponse\x12F\n" +
-	"\vDeleteSlice\x12\x1a.l2sces.DeleteSliceRequest\x1a\x1b.l2sces.DeleteSliceResponse\x12L\n" +
-	"\rCreateOverlay\x12\x1c.l2sces.CreateOverlayRequest\x1a\x1d.l2sces.CreateOverlayResponse\x12C\n" +
-	"\n" +
-	"AddCluster\x12\x19.l2sces.AddClusterRequest\x1a\x1a.l2sces.AddClusterResponse\x12L\n" +
-	"\rRemoveCluster\x12\x1c.l2sces.RemoveClusterRequest\x1a\x1d.l2sces.RemoveClusterResponse\x12L\n" +
-	"\rDeleteOverlay\x12\x1c.l2sces.DeleteOverlayRequest\x1a\x1d.l2sces.DeleteOverlayResponseB3Z1github.com/Networks-it-uc3m/l2sc-es/api/v1/l2scesb\x06proto3"
+// Records kept by the server registry
+type NetworkRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Network as it was requested when it was created.
+	Network       *L2Network `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Namespace     string     `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkRecord) Reset() {
+	*x = NetworkRecord{}
+	mi := &file_l2sces_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkRecord) ProtoMessage() {}
+
+func (x *NetworkRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkRecord.ProtoReflect.Descriptor instead.
+func (*NetworkRecord) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *NetworkRecord) GetNetwork() *L2Network {
+	if x != nil {
+		return x.Network
+	}
+	return nil
+}
+
+func (x *NetworkRecord) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type SliceRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slice         *Slice                 `protobuf:"bytes,1,opt,name=slice,proto3" json:"slice,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SliceRecord) Reset() {
+	*x = SliceRecord{}
+	mi := &file_l2sces_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SliceRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SliceRecord) ProtoMessage() {}
+
+func (x *SliceRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SliceRecord.ProtoReflect.Descriptor instead.
+func (*SliceRecord) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SliceRecord) GetSlice() *Slice {
+	if x != nil {
+		return x.Slice
+	}
+	return nil
+}
+
+func (x *SliceRecord) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+// Requests and Responses for the registry
+type GetNetworkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNetworkRequest) Reset() {
+	*x = GetNetworkRequest{}
+	mi := &file_l2sces_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNetworkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNetworkRequest) ProtoMessage() {}
+
+func (x *GetNetworkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNetworkRequest.ProtoReflect.Descriptor instead.
+func (*GetNetworkRequest) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetNetworkRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetNetworkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Network       *NetworkRecord         `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNetworkResponse) Reset() {
+	*x = GetNetworkResponse{}
+	mi := &file_l2sces_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNetworkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNetworkResponse) ProtoMessage() {}
+
+func (x *GetNetworkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNetworkResponse.ProtoReflect.Descriptor instead.
+func (*GetNetworkResponse) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetNetworkResponse) GetNetwork() *NetworkRecord {
+	if x != nil {
+		return x.Network
+	}
+	return nil
+}
+
+type ListNetworksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNetworksRequest) Reset() {
+	*x = ListNetworksRequest{}
+	mi := &file_l2sces_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNetworksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNetworksRequest) ProtoMessage() {}
+
+func (x *ListNetworksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNetworksRequest.ProtoReflect.Descriptor instead.
+func (*ListNetworksRequest) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{32}
+}
+
+type ListNetworksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Networks      []*NetworkRecord       `protobuf:"bytes,1,rep,name=networks,proto3" json:"networks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNetworksResponse) Reset() {
+	*x = ListNetworksResponse{}
+	mi := &file_l2sces_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNetworksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNetworksResponse) ProtoMessage() {}
+
+func (x *ListNetworksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNetworksResponse.ProtoReflect.Descriptor instead.
+func (*ListNetworksResponse) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListNetworksResponse) GetNetworks() []*NetworkRecord {
+	if x != nil {
+		return x.Networks
+	}
+	return nil
+}
+
+type GetSliceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSliceRequest) Reset() {
+	*x = GetSliceRequest{}
+	mi := &file_l2sces_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSliceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSliceRequest) ProtoMessage() {}
+
+func (x *GetSliceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSliceRequest.ProtoReflect.Descriptor instead.
+func (*GetSliceRequest) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetSliceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetSliceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slice         *SliceRecord           `protobuf:"bytes,1,opt,name=slice,proto3" json:"slice,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSliceResponse) Reset() {
+	*x = GetSliceResponse{}
+	mi := &file_l2sces_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSliceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSliceResponse) ProtoMessage() {}
+
+func (x *GetSliceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSliceResponse.ProtoReflect.Descriptor instead.
+func (*GetSliceResponse) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetSliceResponse) GetSlice() *SliceRecord {
+	if x != nil {
+		return x.Slice
+	}
+	return nil
+}
+
+// Renders the topology of a registered slice.
+type GetSliceTopologyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// dot, mermaid or json. Defaults to json.
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	// Overlays the live status of the links, read from the NetworkEdgeDevices of the member clusters.
+	Live          bool `protobuf:"varint,3,opt,name=live,proto3" json:"live,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSliceTopologyRequest) Reset() {
+	*x = GetSliceTopologyRequest{}
+	mi := &file_l2sces_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSliceTopologyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSliceTopologyRequest) ProtoMessage() {}
+
+func (x *GetSliceTopologyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSliceTopologyRequest.ProtoReflect.Descriptor instead.
+func (*GetSliceTopologyRequest) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetSliceTopologyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GetSliceTopologyRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *GetSliceTopologyRequest) GetLive() bool {
+	if x != nil {
+		return x.Live
+	}
+	return false
+}
+
+type GetSliceTopologyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topology      string                 `protobuf:"bytes,1,opt,name=topology,proto3" json:"topology,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSliceTopologyResponse) Reset() {
+	*x = GetSliceTopologyResponse{}
+	mi := &file_l2sces_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSliceTopologyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSliceTopologyResponse) ProtoMessage() {}
+
+func (x *GetSliceTopologyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSliceTopologyResponse.ProtoReflect.Descriptor instead.
+func (*GetSliceTopologyResponse) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetSliceTopologyResponse) GetTopology() string {
+	if x != nil {
+		return x.Topology
+	}
+	return ""
+}
+
+type ListSlicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSlicesRequest) Reset() {
+	*x = ListSlicesRequest{}
+	mi := &file_l2sces_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSlicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSlicesRequest) ProtoMessage() {}
+
+func (x *ListSlicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSlicesRequest.ProtoReflect.Descriptor instead.
+func (*ListSlicesRequest) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{38}
+}
+
+type ListSlicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Slices        []*SliceRecord         `protobuf:"bytes,1,rep,name=slices,proto3" json:"slices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSlicesResponse) Reset() {
+	*x = ListSlicesResponse{}
+	mi := &file_l2sces_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSlicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSlicesResponse) ProtoMessage() {}
+
+func (x *ListSlicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSlicesResponse.ProtoReflect.Descriptor instead.
+func (*ListSlicesResponse) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListSlicesResponse) GetSlices() []*SliceRecord {
+	if x != nil {
+		return x.Slices
+	}
+	return nil
+}
+
+// Requests and Responses for member cluster registration
+type RegisterClusterRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ClusterName string                 `protobuf:"bytes,1,opt,name=cluster_name,json=clusterName,proto3" json:"cluster_name,omitempty"`
+	// Kubeconfig that reaches the cluster. Its server and CA are registered; a kubeconfig without CA has it
+	// discovered from the cluster. It must not reference files or use auth plugins.
+	Kubeconfig []byte `protobuf:"bytes,2,opt,name=kubeconfig,proto3" json:"kubeconfig,omitempty"`
+	// Context of the kubeconfig to use. Defaults to its current context.
+	Context string `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+	// Creates a ServiceAccount in the cluster, bound to the role l2sces needs, and registers a token minted for
+	// it. Otherwise the token or client certificate of the kubeconfig is registered.
+	CreateServiceAccount bool `protobuf:"varint,4,opt,name=create_service_account,json=createServiceAccount,proto3" json:"create_service_account,omitempty"`
+	// Defaults to l2sm-system.
+	ServiceAccountNamespace string `protobuf:"bytes,5,opt,name=service_account_namespace,json=serviceAccountNamespace,proto3" json:"service_account_namespace,omitempty"`
+	// Defaults to l2sces.
+	ServiceAccountName string `protobuf:"bytes,6,opt,name=service_account_name,json=serviceAccountName,proto3" json:"service_account_name,omitempty"`
+	// Defaults to a year. The cluster may mint a token valid for less.
+	TokenExpirationSeconds int64 `protobuf:"varint,7,opt,name=token_expiration_seconds,json=tokenExpirationSeconds,proto3" json:"token_expiration_seconds,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *RegisterClusterRequest) Reset() {
+	*x = RegisterClusterRequest{}
+	mi := &file_l2sces_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterClusterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterClusterRequest) ProtoMessage() {}
+
+func (x *RegisterClusterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterClusterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterClusterRequest) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RegisterClusterRequest) GetClusterName() string {
+	if x != nil {
+		return x.ClusterName
+	}
+	return ""
+}
+
+func (x *RegisterClusterRequest) GetKubeconfig() []byte {
+	if x != nil {
+		return x.Kubeconfig
+	}
+	return nil
+}
+
+func (x *RegisterClusterRequest) GetContext() string {
+	if x != nil {
+		return x.Context
+	}
+	return ""
+}
+
+func (x *RegisterClusterRequest) GetCreateServiceAccount() bool {
+	if x != nil {
+		return x.CreateServiceAccount
+	}
+	return false
+}
+
+func (x *RegisterClusterRequest) GetServiceAccountNamespace() string {
+	if x != nil {
+		return x.ServiceAccountNamespace
+	}
+	return ""
+}
+
+func (x *RegisterClusterRequest) GetServiceAccountName() string {
+	if x != nil {
+		return x.ServiceAccountName
+	}
+	return ""
+}
+
+func (x *RegisterClusterRequest) GetTokenExpirationSeconds() int64 {
+	if x != nil {
+		return x.TokenExpirationSeconds
+	}
+	return 0
+}
+
+type RegisterClusterResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Server  string                 `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
+	// ServiceAccount created in the cluster as namespace/name, if any.
+	ServiceAccount string `protobuf:"bytes,3,opt,name=service_account,json=serviceAccount,proto3" json:"service_account,omitempty"`
+	// Expiry of the token minted for the ServiceAccount, in RFC 3339.
+	TokenExpiry   string `protobuf:"bytes,4,opt,name=token_expiry,json=tokenExpiry,proto3" json:"token_expiry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterClusterResponse) Reset() {
+	*x = RegisterClusterResponse{}
+	mi := &file_l2sces_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterClusterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterClusterResponse) ProtoMessage() {}
+
+func (x *RegisterClusterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_l2sces_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterClusterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterClusterResponse) Descriptor() ([]byte, []int) {
+	return file_l2sces_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *RegisterClusterResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RegisterClusterResponse) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *RegisterClusterResponse) GetServiceAccount() string {
+	if x != nil {
+		return x.ServiceAccount
+	}
+	return ""
+}
+
+func (x *RegisterClusterResponse) GetTokenExpiry() string {
+	if x != nil {
+		return x.TokenExpiry
+	}
+	return ""
+}
+
+var File_l2sces_proto protoreflect.FileDescriptor
+
+const file_l2sces_proto_rawDesc = "" +
+	"\n" +
+	"\fl2sces.proto\x12\x06l2sces\"\xa9\x01\n" +
+	"\bProvider\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x19\n" +
+	"\bdns_port\x18\x03 \x01(\tR\adnsPort\x12\x19\n" +
+	"\bsdn_port\x18\x04 \x01(\tR\asdnPort\x12\x17\n" +
+	"\aof_port\x18\x05 \x01(\tR\x06ofPort\x12\"\n" +
+	"\rdns_grpc_port\x18\x06 \x01(\tR\vdnsGrpcPort\"B\n" +
+	"\x04Link\x12\x1c\n" +
+	"\tendpointA\x18\x01 \x01(\tR\tendpointA\x12\x1c\n" +
+	"\tendpointB\x18\x02 \x01(\tR\tendpointB\"9\n" +
+	"\x04Node\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x02 \x01(\tR\tipAddress\"`\n" +
+	"\n" +
+	"RestConfig\x12!\n" +
+	"\fbearer_token\x18\x01 \x01(\tR\vbearerToken\x12\x17\n" +
+	"\aapi_key\x18\x02 \x01(\tR\x06apiKey\x12\x16\n" +
+	"\x06server\x18\x03 \x01(\tR\x06server\"\x8e\x02\n" +
+	"\aCluster\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x123\n" +
+	"\vrest_config\x18\x02 \x01(\v2\x12.l2sces.RestConfigR\n" +
+	"restConfig\x12)\n" +
+	"\aoverlay\x18\x03 \x01(\v2\x0f.l2sces.OverlayR\aoverlay\x12/\n" +
+	"\fgateway_node\x18\x04 \x01(\v2\f.l2sces.NodeR\vgatewayNode\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12(\n" +
+	"\x10pod_address_pool\x18\x06 \x01(\tR\x0epodAddressPool\x12\x16\n" +
+	"\x06region\x18\a \x01(\tR\x06region\"q\n" +
+	"\aOverlay\x12,\n" +
+	"\bprovider\x18\x01 \x01(\v2\x10.l2sces.ProviderR\bprovider\x12\x14\n" +
+	"\x05nodes\x18\x02 \x03(\tR\x05nodes\x12\"\n" +
+	"\x05links\x18\x03 \x03(\v2\f.l2sces.LinkR\x05links\"\xa9\x01\n" +
+	"\tL2Network\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12,\n" +
+	"\bprovider\x18\x02 \x01(\v2\x10.l2sces.ProviderR\bprovider\x12\x19\n" +
+	"\bpod_cidr\x18\x03 \x01(\tR\apodCidr\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12+\n" +
+	"\bclusters\x18\x05 \x03(\v2\x0f.l2sces.ClusterR\bclusters\"Z\n" +
+	"\bLinkCost\x12\x1c\n" +
+	"\tendpointA\x18\x01 \x01(\tR\tendpointA\x12\x1c\n" +
+	"\tendpointB\x18\x02 \x01(\tR\tendpointB\x12\x12\n" +
+	"\x04cost\x18\x03 \x01(\x01R\x04cost\"\x98\x01\n" +
+	"\bTopology\x12\x1a\n" +
+	"\bstrategy\x18\x01 \x01(\tR\bstrategy\x12\x10\n" +
+	"\x03hub\x18\x02 \x01(\tR\x03hub\x12\x16\n" +
+	"\x06degree\x18\x03 \x01(\x05R\x06degree\x12&\n" +
+	"\x05costs\x18\x04 \x03(\v2\x10.l2sces.LinkCostR\x05costs\x12\x1e\n" +
+	"\n" +
+	"redundancy\x18\x05 \x01(\x05R\n" +
+	"redundancy\"\xc8\x01\n" +
+	"\x05Slice\x12,\n" +
+	"\bprovider\x18\x01 \x01(\v2\x10.l2sces.ProviderR\bprovider\x12+\n" +
+	"\bclusters\x18\x02 \x03(\v2\x0f.l2sces.ClusterR\bclusters\x12\"\n" +
+	"\x05links\x18\x03 \x03(\v2\f.l2sces.LinkR\x05links\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12,\n" +
+	"\btopology\x18\x05 \x01(\v2\x10.l2sces.TopologyR\btopology\"\xe5\x01\n" +
+	"\rClusterResult\x12\x18\n" +
+	"\acluster\x18\x01 \x01(\tR\acluster\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x18\n" +
+	"\aobjects\x18\x03 \x03(\tR\aobjects\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x129\n" +
+	"\x06status\x18\x05 \x03(\v2!.l2sces.ClusterResult.StatusEntryR\x06status\x1a9\n" +
+	"\vStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"a\n" +
+	"\x14CreateNetworkRequest\x12+\n" +
+	"\anetwork\x18\x01 \x01(\v2\x11.l2sces.L2NetworkR\anetwork\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"6\n" +
+	"\n" +
+	"FieldPatch\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"\x92\x01\n" +
+	"\x15CreateNetworkResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12,\n" +
+	"\apatches\x18\x02 \x03(\v2\x12.l2sces.FieldPatchR\apatches\x121\n" +
+	"\bclusters\x18\x03 \x03(\v2\x15.l2sces.ClusterResultR\bclusters\"a\n" +
+	"\x14DeleteNetworkRequest\x12+\n" +
+	"\anetwork\x18\x01 \x01(\v2\x11.l2sces.L2NetworkR\anetwork\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"d\n" +
+	"\x15DeleteNetworkResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x121\n" +
+	"\bclusters\x18\x02 \x03(\v2\x15.l2sces.ClusterResultR\bclusters\"W\n" +
+	"\x12CreateSliceRequest\x12#\n" +
+	"\x05slice\x18\x01 \x01(\v2\r.l2sces.SliceR\x05slice\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"\xb6\x01\n" +
+	"\x13CreateSliceResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x121\n" +
+	"\bclusters\x18\x02 \x03(\v2\x15.l2sces.ClusterResultR\bclusters\x12\"\n" +
+	"\x05links\x18\x03 \x03(\v2\f.l2sces.LinkR\x05links\x12\x12\n" +
+	"\x04cost\x18\x04 \x01(\x01R\x04cost\x12\x1a\n" +
+	"\bwarnings\x18\x05 \x03(\tR\bwarnings\"W\n" +
+	"\x12DeleteSliceRequest\x12#\n" +
+	"\x05slice\x18\x01 \x01(\v2\r.l2sces.SliceR\x05slice\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"b\n" +
+	"\x13DeleteSliceResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x121\n" +
+	"\bclusters\x18\x02 \x03(\v2\x15.l2sces.ClusterResultR\bclusters\"\x8c\x01\n" +
+	"\x14CreateOverlayRequest\x12)\n" +
+	"\aoverlay\x18\x01 \x01(\v2\x0f.l2sces.OverlayR\aoverlay\x12+\n" +
+	"\bclusters\x18\x02 \x03(\v2\x0f.l2sces.ClusterR\bclusters\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\"\x80\x01\n" +
+	"\x15CreateOverlayResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x121\n" +
+	"\bclusters\x18\x02 \x03(\v2\x15.l2sces.ClusterResultR\bclusters\x12\x1a\n" +
+	"\bwarnings\x18\x03 \x03(\tR\bwarnings\"\x92\x02\n" +
+	"\x11AddClusterRequest\x12#\n" +
+	"\rprovider_name\x18\x01 \x01(\tR\fproviderName\x12'\n" +
+	"\x0fprovider_domain\x18\x02 \x01(\tR\x0eproviderDomain\x12\x1d\n" +
+	"\n" +
+	"slice_name\x18\x03 \x01(\tR\tsliceName\x12)\n" +
+	"\acluster\x18\x04 \x01(\v2\x0f.l2sces.ClusterR\acluster\x12#\n" +
+	"\x05slice\x18\x05 \x01(\v2\r.l2sces.SliceR\x05slice\x12\"\n" +
+	"\x05links\x18\x06 \x03(\v2\f.l2sces.LinkR\x05links\x12\x1c\n" +
+	"\tnamespace\x18\a \x01(\tR\tnamespace\".\n" +
+	"\x12AddClusterResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xed\x01\n" +
+	"\x14RemoveClusterRequest\x12#\n" +
+	"\rprovider_name\x18\x01 \x01(\tR\fproviderName\x12'\n" +
+	"\x0fprovider_domain\x18\x02 \x01(\tR\x0eproviderDomain\x12!\n" +
+	"\foverlay_name\x18\x03 \x01(\tR\voverlayName\x12!\n" +
+	"\fcluster_name\x18\x04 \x01(\tR\vclusterName\x12#\n" +
+	"\x05slice\x18\x05 \x01(\v2\r.l2sces.SliceR\x05slice\x12\x1c\n" +
+	"\tnamespace\x18\x06 \x01(\tR\tnamespace\"1\n" +
+	"\x15RemoveClusterResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xca\x01\n" +
+	"\x14DeleteOverlayRequest\x12#\n" +
+	"\rprovider_name\x18\x01 \x01(\tR\fproviderName\x12'\n" +
+	"\x0fprovider_domain\x18\x02 \x01(\tR\x0eproviderDomain\x12!\n" +
+	"\foverlay_name\x18\x03 \x01(\tR\voverlayName\x12#\n" +
+	"\x05slice\x18\x04 \x01(\v2\r.l2sces.SliceR\x05slice\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\"d\n" +
+	"\x15DeleteOverlayResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x121\n" +
+	"\bclusters\x18\x02 \x03(\v2\x15.l2sces.ClusterResultR\bclusters\"Z\n" +
+	"\rNetworkRecord\x12+\n" +
+	"\anetwork\x18\x01 \x01(\v2\x11.l2sces.L2NetworkR\anetwork\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"P\n" +
+	"\vSliceRecord\x12#\n" +
+	"\x05slice\x18\x01 \x01(\v2\r.l2sces.SliceR\x05slice\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"'\n" +
+	"\x11GetNetworkRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"E\n" +
+	"\x12GetNetworkResponse\x12/\n" +
+	"\anetwork\x18\x01 \x01(\v2\x15.l2sces.NetworkRecordR\anetwork\"\x15\n" +
+	"\x13ListNetworksRequest\"I\n" +
+	"\x14ListNetworksResponse\x121\n" +
+	"\bnetworks\x18\x01 \x03(\v2\x15.l2sces.NetworkRecordR\bnetworks\"%\n" +
+	"\x0fGetSliceRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"=\n" +
+	"\x10GetSliceResponse\x12)\n" +
+	"\x05slice\x18\x01 \x01(\v2\x13.l2sces.SliceRecordR\x05slice\"Y\n" +
+	"\x17GetSliceTopologyRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x12\n" +
+	"\x04live\x18\x03 \x01(\bR\x04live\"6\n" +
+	"\x18GetSliceTopologyResponse\x12\x1a\n" +
+	"\btopology\x18\x01 \x01(\tR\btopology\"\x13\n" +
+	"\x11ListSlicesRequest\"A\n" +
+	"\x12ListSlicesResponse\x12+\n" +
+	"\x06slices\x18\x01 \x03(\v2\x13.l2sces.SliceRecordR\x06slices\"\xd3\x02\n" +
+	"\x16RegisterClusterRequest\x12!\n" +
+	"\fcluster_name\x18\x01 \x01(\tR\vclusterName\x12\x1e\n" +
+	"\n" +
+	"kubeconfig\x18\x02 \x01(\fR\n" +
+	"kubeconfig\x12\x18\n" +
+	"\acontext\x18\x03 \x01(\tR\acontext\x124\n" +
+	"\x16create_service_account\x18\x04 \x01(\bR\x14createServiceAccount\x12:\n" +
+	"\x19service_account_namespace\x18\x05 \x01(\tR\x17serviceAccountNamespace\x120\n" +
+	"\x14service_account_name\x18\x06 \x01(\tR\x12serviceAccountName\x128\n" +
+	"\x18token_expiration_seconds\x18\a \x01(\x03R\x16tokenExpirationSeconds\"\x97\x01\n" +
+	"\x17RegisterClusterResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x16\n" +
+	"\x06server\x18\x02 \x01(\tR\x06server\x12'\n" +
+	"\x0fservice_account\x18\x03 \x01(\tR\x0eserviceAccount\x12!\n" +
+	"\ftoken_expiry\x18\x04 \x01(\tR\vtokenExpiry2\xb2\b\n" +
+	"\x16L2SMMultiDomainService\x12L\n" +
+	"\rCreateNetwork\x12\x1c.l2sces.CreateNetworkRequest\x1a\x1d.l2sces.CreateNetworkResponse\x12L\n" +
+	"\rDeleteNetwork\x12\x1c.l2sces.DeleteNetworkRequest\x1a\x1d.l2sces.DeleteNetworkResponse\x12F\n" +
+	"\vCreateSlice\x12\x1a.l2sces.CreateSliceRequest\x1a\x1b.l2sces.CreateSliceResponse\x12F\n" +
+	"\vDeleteSlice\x12\x1a.l2sces.DeleteSliceRequest\x1a\x1b.l2sces.DeleteSliceResponse\x12L\n" +
+	"\rCreateOverlay\x12\x1c.l2sces.CreateOverlayRequest\x1a\x1d.l2sces.CreateOverlayResponse\x12C\n" +
+	"\n" +
+	"AddCluster\x12\x19.l2sces.AddClusterRequest\x1a\x1a.l2sces.AddClusterResponse\x12L\n" +
+	"\rRemoveCluster\x12\x1c.l2sces.RemoveClusterRequest\x1a\x1d.l2sces.RemoveClusterResponse\x12L\n" +
+	"\rDeleteOverlay\x12\x1c.l2sces.DeleteOverlayRequest\x1a\x1d.l2sces.DeleteOverlayResponse\x12C\n" +
+	"\n" +
+	"GetNetwork\x12\x19.l2sces.GetNetworkRequest\x1a\x1a.l2sces.GetNetworkResponse\x12I\n" +
+	"\fListNetworks\x12\x1b.l2sces.ListNetworksRequest\x1a\x1c.l2sces.ListNetworksResponse\x12=\n" +
+	"\bGetSlice\x12\x17.l2sces.GetSliceRequest\x1a\x18.l2sces.GetSliceResponse\x12U\n" +
+	"\x10GetSliceTopology\x12\x1f.l2sces.GetSliceTopologyRequest\x1a .l2sces.GetSliceTopologyResponse\x12C\n" +
+	"\n" +
+	"ListSlices\x12\x19.l2sces.ListSlicesRequest\x1a\x1a.l2sces.ListSlicesResponse\x12R\n" +
+	"\x0fRegisterCluster\x12\x1e.l2sces.RegisterClusterRequest\x1a\x1f.l2sces.RegisterClusterResponseB3Z1github.com/Networks-it-uc3m/l2sc-es/api/v1/l2scesb\x06proto3"
 
 var (
 	file_l2sces_proto_rawDescOnce sync.Once
@@ -1526,33 +2768,51 @@ func file_l2sces_proto_rawDescGZIP() []byte {
 	return file_l2sces_proto_rawDescData
 }
 
-var file_l2sces_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_l2sces_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_l2sces_proto_goTypes = []any{
-	(*Provider)(nil),              // 0: l2sces.Provider
-	(*Link)(nil),                  // 1: l2sces.Link
-	(*Node)(nil),                  // 2: l2sces.Node
-	(*RestConfig)(nil),            // 3: l2sces.RestConfig
-	(*Cluster)(nil),               // 4: l2sces.Cluster
-	(*Overlay)(nil),               // 5: l2sces.Overlay
-	(*L2Network)(nil),             // 6: l2sces.L2Network
-	(*Slice)(nil),                 // 7: l2sces.Slice
-	(*CreateNetworkRequest)(nil),  // 8: l2sces.CreateNetworkRequest
-	(*FieldPatch)(nil),            // 9: l2sces.FieldPatch
-	(*CreateNetworkResponse)(nil), // 10: l2sces.CreateNetworkResponse
-	(*DeleteNetworkRequest)(nil),  // 11: l2sces.DeleteNetworkRequest
-	(*DeleteNetworkResponse)(nil), // 12: l2sces.DeleteNetworkResponse
-	(*CreateSliceRequest)(nil),    // 13: l2sces.CreateSliceRequest
-	(*CreateSliceResponse)(nil),   // 14: l2sces.CreateSliceResponse
-	(*DeleteSliceRequest)(nil),    // 15: l2sces.DeleteSliceRequest
-	(*DeleteSliceResponse)(nil),   // 16: l2sces.DeleteSliceResponse
-	(*CreateOverlayRequest)(nil),  // 17: l2sces.CreateOverlayRequest
-	(*CreateOverlayResponse)(nil), // 18: l2sces.CreateOverlayResponse
-	(*AddClusterRequest)(nil),     // 19: l2sces.AddClusterRequest
-	(*AddClusterResponse)(nil),    // 20: l2sces.AddClusterResponse
-	(*RemoveClusterRequest)(nil),  // 21: l2sces.RemoveClusterRequest
-	(*RemoveClusterResponse)(nil), // 22: l2sces.RemoveClusterResponse
-	(*DeleteOverlayRequest)(nil),  // 23: l2sces.DeleteOverlayRequest
-	(*DeleteOverlayResponse)(nil), // 24: l2sces.DeleteOverlayResponse
+	(*Provider)(nil),                 // 0: l2sces.Provider
+	(*Link)(nil),                     // 1: l2sces.Link
+	(*Node)(nil),                     // 2: l2sces.Node
+	(*RestConfig)(nil),               // 3: l2sces.RestConfig
+	(*Cluster)(nil),                  // 4: l2sces.Cluster
+	(*Overlay)(nil),                  // 5: l2sces.Overlay
+	(*L2Network)(nil),                // 6: l2sces.L2Network
+	(*LinkCost)(nil),                 // 7: l2sces.LinkCost
+	(*Topology)(nil),                 // 8: l2sces.Topology
+	(*Slice)(nil),                    // 9: l2sces.Slice
+	(*ClusterResult)(nil),            // 10: l2sces.ClusterResult
+	(*CreateNetworkRequest)(nil),     // 11: l2sces.CreateNetworkRequest
+	(*FieldPatch)(nil),               // 12: l2sces.FieldPatch
+	(*CreateNetworkResponse)(nil),    // 13: l2sces.CreateNetworkResponse
+	(*DeleteNetworkRequest)(nil),     // 14: l2sces.DeleteNetworkRequest
+	(*DeleteNetworkResponse)(nil),    // 15: l2sces.DeleteNetworkResponse
+	(*CreateSliceRequest)(nil),       // 16: l2sces.CreateSliceRequest
+	(*CreateSliceResponse)(nil),      // 17: l2sces.CreateSliceResponse
+	(*DeleteSliceRequest)(nil),       // 18: l2sces.DeleteSliceRequest
+	(*DeleteSliceResponse)(nil),      // 19: l2sces.DeleteSliceResponse
+	(*CreateOverlayRequest)(nil),     // 20: l2sces.CreateOverlayRequest
+	(*CreateOverlayResponse)(nil),    // 21: l2sces.CreateOverlayResponse
+	(*AddClusterRequest)(nil),        // 22: l2sces.AddClusterRequest
+	(*AddClusterResponse)(nil),       // 23: l2sces.AddClusterResponse
+	(*RemoveClusterRequest)(nil),     // 24: l2sces.RemoveClusterRequest
+	(*RemoveClusterResponse)(nil),    // 25: l2sces.RemoveClusterResponse
+	(*DeleteOverlayRequest)(nil),     // 26: l2sces.DeleteOverlayRequest
+	(*DeleteOverlayResponse)(nil),    // 27: l2sces.DeleteOverlayResponse
+	(*NetworkRecord)(nil),            // 28: l2sces.NetworkRecord
+	(*SliceRecord)(nil),              // 29: l2sces.SliceRecord
+	(*GetNetworkRequest)(nil),        // 30: l2sces.GetNetworkRequest
+	(*GetNetworkResponse)(nil),       // 31: l2sces.GetNetworkResponse
+	(*ListNetworksRequest)(nil),      // 32: l2sces.ListNetworksRequest
+	(*ListNetworksResponse)(nil),     // 33: l2sces.ListNetworksResponse
+	(*GetSliceRequest)(nil),          // 34: l2sces.GetSliceRequest
+	(*GetSliceResponse)(nil),         // 35: l2sces.GetSliceResponse
+	(*GetSliceTopologyRequest)(nil),  // 36: l2sces.GetSliceTopologyRequest
+	(*GetSliceTopologyResponse)(nil), // 37: l2sces.GetSliceTopologyResponse
+	(*ListSlicesRequest)(nil),        // 38: l2sces.ListSlicesRequest
+	(*ListSlicesResponse)(nil),       // 39: l2sces.ListSlicesResponse
+	(*RegisterClusterRequest)(nil),   // 40: l2sces.RegisterClusterRequest
+	(*RegisterClusterResponse)(nil),  // 41: l2sces.RegisterClusterResponse
+	nil,                              // 42: l2sces.ClusterResult.StatusEntry
 }
 var file_l2sces_proto_depIdxs = []int32{
 	3,  // 0: l2sces.Cluster.rest_config:type_name -> l2sces.RestConfig
@@ -1562,37 +2822,70 @@ var file_l2sces_proto_depIdxs = []int32{
 	1,  // 4: l2sces.Overlay.links:type_name -> l2sces.Link
 	0,  // 5: l2sces.L2Network.provider:type_name -> l2sces.Provider
 	4,  // 6: l2sces.L2Network.clusters:type_name -> l2sces.Cluster
-	0,  // 7: l2sces.Slice.provider:type_name -> l2sces.Provider
-	4,  // 8: l2sces.Slice.clusters:type_name -> l2sces.Cluster
-	1,  // 9: l2sces.Slice.links:type_name -> l2sces.Link
-	6,  // 10: l2sces.CreateNetworkRequest.network:type_name -> l2sces.L2Network
-	9,  // 11: l2sces.CreateNetworkResponse.patches:type_name -> l2sces.FieldPatch
-	6,  // 12: l2sces.DeleteNetworkRequest.network:type_name -> l2sces.L2Network
-	7,  // 13: l2sces.CreateSliceRequest.slice:type_name -> l2sces.Slice
-	7,  // 14: l2sces.DeleteSliceRequest.slice:type_name -> l2sces.Slice
-	5,  // 15: l2sces.CreateOverlayRequest.overlay:type_name -> l2sces.Overlay
-	4,  // 16: l2sces.AddClusterRequest.cluster:type_name -> l2sces.Cluster
-	8,  // 17: l2sces.L2SMMultiDomainService.CreateNetwork:input_type -> l2sces.CreateNetworkRequest
-	11, // 18: l2sces.L2SMMultiDomainService.DeleteNetwork:input_type -> l2sces.DeleteNetworkRequest
-	13, // 19: l2sces.L2SMMultiDomainService.CreateSlice:input_type -> l2sces.CreateSliceRequest
-	15, // 20: l2sces.L2SMMultiDomainService.DeleteSlice:input_type -> l2sces.DeleteSliceRequest
-	17, // 21: l2sces.L2SMMultiDomainService.CreateOverlay:input_type -> l2sces.CreateOverlayRequest
-	19, // 22: l2sces.L2SMMultiDomainService.AddCluster:input_type -> l2sces.AddClusterRequest
-	21, // 23: l2sces.L2SMMultiDomainService.RemoveCluster:input_type -> l2sces.RemoveClusterRequest
-	23, // 24: l2sces.L2SMMultiDomainService.DeleteOverlay:input_type -> l2sces.DeleteOverlayRequest
-	10, // 25: l2sces.L2SMMultiDomainService.CreateNetwork:output_type -> l2sces.CreateNetworkResponse
-	12, // 26: l2sces.L2SMMultiDomainService.DeleteNetwork:output_type -> l2sces.DeleteNetworkResponse
-	14, // 27: l2sces.L2SMMultiDomainService.CreateSlice:output_type -> l2sces.CreateSliceResponse
-	16, // 28: l2sces.L2SMMultiDomainService.DeleteSlice:output_type -> l2sces.DeleteSliceResponse
-	18, // 29: l2sces.L2SMMultiDomainService.CreateOverlay:output_type -> l2sces.CreateOverlayResponse
-	20, // 30: l2sces.L2SMMultiDomainService.AddCluster:output_type -> l2sces.AddClusterResponse
-	22, // 31: l2sces.L2SMMultiDomainService.RemoveCluster:output_type -> l2sces.RemoveClusterResponse
-	24, // 32: l2sces.L2SMMultiDomainService.DeleteOverlay:output_type -> l2sces.DeleteOverlayResponse
-	25, // [25:33] is the sub-list for method output_type
-	17, // [17:25] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	7,  // 7: l2sces.Topology.costs:type_name -> l2sces.LinkCost
+	0,  // 8: l2sces.Slice.provider:type_name -> l2sces.Provider
+	4,  // 9: l2sces.Slice.clusters:type_name -> l2sces.Cluster
+	1,  // 10: l2sces.Slice.links:type_name -> l2sces.Link
+	8,  // 11: l2sces.Slice.topology:type_name -> l2sces.Topology
+	42, // 12: l2sces.ClusterResult.status:type_name -> l2sces.ClusterResult.StatusEntry
+	6,  // 13: l2sces.CreateNetworkRequest.network:type_name -> l2sces.L2Network
+	12, // 14: l2sces.CreateNetworkResponse.patches:type_name -> l2sces.FieldPatch
+	10, // 15: l2sces.CreateNetworkResponse.clusters:type_name -> l2sces.ClusterResult
+	6,  // 16: l2sces.DeleteNetworkRequest.network:type_name -> l2sces.L2Network
+	10, // 17: l2sces.DeleteNetworkResponse.clusters:type_name -> l2sces.ClusterResult
+	9,  // 18: l2sces.CreateSliceRequest.slice:type_name -> l2sces.Slice
+	10, // 19: l2sces.CreateSliceResponse.clusters:type_name -> l2sces.ClusterResult
+	1,  // 20: l2sces.CreateSliceResponse.links:type_name -> l2sces.Link
+	9,  // 21: l2sces.DeleteSliceRequest.slice:type_name -> l2sces.Slice
+	10, // 22: l2sces.DeleteSliceResponse.clusters:type_name -> l2sces.ClusterResult
+	5,  // 23: l2sces.CreateOverlayRequest.overlay:type_name -> l2sces.Overlay
+	4,  // 24: l2sces.CreateOverlayRequest.clusters:type_name -> l2sces.Cluster
+	10, // 25: l2sces.CreateOverlayResponse.clusters:type_name -> l2sces.ClusterResult
+	4,  // 26: l2sces.AddClusterRequest.cluster:type_name -> l2sces.Cluster
+	9,  // 27: l2sces.AddClusterRequest.slice:type_name -> l2sces.Slice
+	1,  // 28: l2sces.AddClusterRequest.links:type_name -> l2sces.Link
+	9,  // 29: l2sces.RemoveClusterRequest.slice:type_name -> l2sces.Slice
+	9,  // 30: l2sces.DeleteOverlayRequest.slice:type_name -> l2sces.Slice
+	10, // 31: l2sces.DeleteOverlayResponse.clusters:type_name -> l2sces.ClusterResult
+	6,  // 32: l2sces.NetworkRecord.network:type_name -> l2sces.L2Network
+	9,  // 33: l2sces.SliceRecord.slice:type_name -> l2sces.Slice
+	28, // 34: l2sces.GetNetworkResponse.network:type_name -> l2sces.NetworkRecord
+	28, // 35: l2sces.ListNetworksResponse.networks:type_name -> l2sces.NetworkRecord
+	29, // 36: l2sces.GetSliceResponse.slice:type_name -> l2sces.SliceRecord
+	29, // 37: l2sces.ListSlicesResponse.slices:type_name -> l2sces.SliceRecord
+	11, // 38: l2sces.L2SMMultiDomainService.CreateNetwork:input_type -> l2sces.CreateNetworkRequest
+	14, // 39: l2sces.L2SMMultiDomainService.DeleteNetwork:input_type -> l2sces.DeleteNetworkRequest
+	16, // 40: l2sces.L2SMMultiDomainService.CreateSlice:input_type -> l2sces.CreateSliceRequest
+	18, // 41: l2sces.L2SMMultiDomainService.DeleteSlice:input_type -> l2sces.DeleteSliceRequest
+	20, // 42: l2sces.L2SMMultiDomainService.CreateOverlay:input_type -> l2sces.CreateOverlayRequest
+	22, // 43: l2sces.L2SMMultiDomainService.AddCluster:input_type -> l2sces.AddClusterRequest
+	24, // 44: l2sces.L2SMMultiDomainService.RemoveCluster:input_type -> l2sces.RemoveClusterRequest
+	26, // 45: l2sces.L2SMMultiDomainService.DeleteOverlay:input_type -> l2sces.DeleteOverlayRequest
+	30, // 46: l2sces.L2SMMultiDomainService.GetNetwork:input_type -> l2sces.GetNetworkRequest
+	32, // 47: l2sces.L2SMMultiDomainService.ListNetworks:input_type -> l2sces.ListNetworksRequest
+	34, // 48: l2sces.L2SMMultiDomainService.GetSlice:input_type -> l2sces.GetSliceRequest
+	36, // 49: l2sces.L2SMMultiDomainService.GetSliceTopology:input_type -> l2sces.GetSliceTopologyRequest
+	38, // 50: l2sces.L2SMMultiDomainService.ListSlices:input_type -> l2sces.ListSlicesRequest
+	40, // 51: l2sces.L2SMMultiDomainService.RegisterCluster:input_type -> l2sces.RegisterClusterRequest
+	13, // 52: l2sces.L2SMMultiDomainService.CreateNetwork:output_type -> l2sces.CreateNetworkResponse
+	15, // 53: l2sces.L2SMMultiDomainService.DeleteNetwork:output_type -> l2sces.DeleteNetworkResponse
+	17, // 54: l2sces.L2SMMultiDomainService.CreateSlice:output_type -> l2sces.CreateSliceResponse
+	19, // 55: l2sces.L2SMMultiDomainService.DeleteSlice:output_type -> l2sces.DeleteSliceResponse
+	21, // 56: l2sces.L2SMMultiDomainService.CreateOverlay:output_type -> l2sces.CreateOverlayResponse
+	23, // 57: l2sces.L2SMMultiDomainService.AddCluster:output_type -> l2sces.AddClusterResponse
+	25, // 58: l2sces.L2SMMultiDomainService.RemoveCluster:output_type -> l2sces.RemoveClusterResponse
+	27, // 59: l2sces.L2SMMultiDomainService.DeleteOverlay:output_type -> l2sces.DeleteOverlayResponse
+	31, // 60: l2sces.L2SMMultiDomainService.GetNetwork:output_type -> l2sces.GetNetworkResponse
+	33, // 61: l2sces.L2SMMultiDomainService.ListNetworks:output_type -> l2sces.ListNetworksResponse
+	35, // 62: l2sces.L2SMMultiDomainService.GetSlice:output_type -> l2sces.GetSliceResponse
+	37, // 63: l2sces.L2SMMultiDomainService.GetSliceTopology:output_type -> l2sces.GetSliceTopologyResponse
+	39, // 64: l2sces.L2SMMultiDomainService.ListSlices:output_type -> l2sces.ListSlicesResponse
+	41, // 65: l2sces.L2SMMultiDomainService.RegisterCluster:output_type -> l2sces.RegisterClusterResponse
+	52, // [52:66] is the sub-list for method output_type
+	38, // [38:52] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_l2sces_proto_init() }
@@ -1606,7 +2899,7 @@ func file_l2sces_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_l2sces_proto_rawDesc), len(file_l2sces_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   25,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
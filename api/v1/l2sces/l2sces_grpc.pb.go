@@ -19,14 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	L2SMMultiDomainService_CreateNetwork_FullMethodName = "/l2sces.L2SMMultiDomainService/CreateNetwork"
-	L2SMMultiDomainService_DeleteNetwork_FullMethodName = "/l2sces.L2SMMultiDomainService/DeleteNetwork"
-	L2SMMultiDomainService_CreateSlice_FullMethodName   = "/l2sces.L2SMMultiDomainService/CreateSlice"
-	L2SMMultiDomainService_DeleteSlice_FullMethodName   = "/l2sces.L2SMMultiDomainService/DeleteSlice"
-	L2SMMultiDomainService_CreateOverlay_FullMethodName = "/l2sces.L2SMMultiDomainService/CreateOverlay"
-	L2SMMultiDomainService_AddCluster_FullMethodName    = "/l2sces.L2SMMultiDomainService/AddCluster"
-	L2SMMultiDomainService_RemoveCluster_FullMethodName = "/l2sces.L2SMMultiDomainService/RemoveCluster"
-	L2SMMultiDomainService_DeleteOverlay_FullMethodName = "/l2sces.L2SMMultiDomainService/DeleteOverlay"
+	L2SMMultiDomainService_CreateNetwork_FullMethodName    = "/l2sces.L2SMMultiDomainService/CreateNetwork"
+	L2SMMultiDomainService_DeleteNetwork_FullMethodName    = "/l2sces.L2SMMultiDomainService/DeleteNetwork"
+	L2SMMultiDomainService_CreateSlice_FullMethodName      = "/l2sces.L2SMMultiDomainService/CreateSlice"
+	L2SMMultiDomainService_DeleteSlice_FullMethodName      = "/l2sces.L2SMMultiDomainService/DeleteSlice"
+	L2SMMultiDomainService_CreateOverlay_FullMethodName    = "/l2sces.L2SMMultiDomainService/CreateOverlay"
+	L2SMMultiDomainService_AddCluster_FullMethodName       = "/l2sces.L2SMMultiDomainService/AddCluster"
+	L2SMMultiDomainService_RemoveCluster_FullMethodName    = "/l2sces.L2SMMultiDomainService/RemoveCluster"
+	L2SMMultiDomainService_DeleteOverlay_FullMethodName    = "/l2sces.L2SMMultiDomainService/DeleteOverlay"
+	L2SMMultiDomainService_GetNetwork_FullMethodName       = "/l2sces.L2SMMultiDomainService/GetNetwork"
+	L2SMMultiDomainService_ListNetworks_FullMethodName     = "/l2sces.L2SMMultiDomainService/ListNetworks"
+	L2SMMultiDomainService_GetSlice_FullMethodName         = "/l2sces.L2SMMultiDomainService/GetSlice"
+	L2SMMultiDomainService_GetSliceTopology_FullMethodName = "/l2sces.L2SMMultiDomainService/GetSliceTopology"
+	L2SMMultiDomainService_ListSlices_FullMethodName       = "/l2sces.L2SMMultiDomainService/ListSlices"
+	L2SMMultiDomainService_RegisterCluster_FullMethodName  = "/l2sces.L2SMMultiDomainService/RegisterCluster"
 )
 
 // L2SMMultiDomainServiceClient is the client API for L2SMMultiDomainService service.
@@ -46,6 +52,14 @@ type L2SMMultiDomainServiceClient interface {
 	AddCluster(ctx context.Context, in *AddClusterRequest, opts ...grpc.CallOption) (*AddClusterResponse, error)
 	RemoveCluster(ctx context.Context, in *RemoveClusterRequest, opts ...grpc.CallOption) (*RemoveClusterResponse, error)
 	DeleteOverlay(ctx context.Context, in *DeleteOverlayRequest, opts ...grpc.CallOption) (*DeleteOverlayResponse, error)
+	// Registry of the created networks and slices. Bearer tokens are never returned.
+	GetNetwork(ctx context.Context, in *GetNetworkRequest, opts ...grpc.CallOption) (*GetNetworkResponse, error)
+	ListNetworks(ctx context.Context, in *ListNetworksRequest, opts ...grpc.CallOption) (*ListNetworksResponse, error)
+	GetSlice(ctx context.Context, in *GetSliceRequest, opts ...grpc.CallOption) (*GetSliceResponse, error)
+	GetSliceTopology(ctx context.Context, in *GetSliceTopologyRequest, opts ...grpc.CallOption) (*GetSliceTopologyResponse, error)
+	ListSlices(ctx context.Context, in *ListSlicesRequest, opts ...grpc.CallOption) (*ListSlicesResponse, error)
+	// Member cluster registration
+	RegisterCluster(ctx context.Context, in *RegisterClusterRequest, opts ...grpc.CallOption) (*RegisterClusterResponse, error)
 }
 
 type l2SMMultiDomainServiceClient struct {
@@ -136,6 +150,66 @@ func (c *l2SMMultiDomainServiceClient) DeleteOverlay(ctx context.Context, in *De
 	return out, nil
 }
 
+func (c *l2SMMultiDomainServiceClient) GetNetwork(ctx context.Context, in *GetNetworkRequest, opts ...grpc.CallOption) (*GetNetworkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNetworkResponse)
+	err := c.cc.Invoke(ctx, L2SMMultiDomainService_GetNetwork_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *l2SMMultiDomainServiceClient) ListNetworks(ctx context.Context, in *ListNetworksRequest, opts ...grpc.CallOption) (*ListNetworksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNetworksResponse)
+	err := c.cc.Invoke(ctx, L2SMMultiDomainService_ListNetworks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *l2SMMultiDomainServiceClient) GetSlice(ctx context.Context, in *GetSliceRequest, opts ...grpc.CallOption) (*GetSliceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSliceResponse)
+	err := c.cc.Invoke(ctx, L2SMMultiDomainService_GetSlice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *l2SMMultiDomainServiceClient) GetSliceTopology(ctx context.Context, in *GetSliceTopologyRequest, opts ...grpc.CallOption) (*GetSliceTopologyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSliceTopologyResponse)
+	err := c.cc.Invoke(ctx, L2SMMultiDomainService_GetSliceTopology_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *l2SMMultiDomainServiceClient) ListSlices(ctx context.Context, in *ListSlicesRequest, opts ...grpc.CallOption) (*ListSlicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSlicesResponse)
+	err := c.cc.Invoke(ctx, L2SMMultiDomainService_ListSlices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *l2SMMultiDomainServiceClient) RegisterCluster(ctx context.Context, in *RegisterClusterRequest, opts ...grpc.CallOption) (*RegisterClusterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterClusterResponse)
+	err := c.cc.Invoke(ctx, L2SMMultiDomainService_RegisterCluster_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // L2SMMultiDomainServiceServer is the server API for L2SMMultiDomainService service.
 // All implementations must embed UnimplementedL2SMMultiDomainServiceServer
 // for forward compatibility.
@@ -153,6 +227,14 @@ type L2SMMultiDomainServiceServer interface {
 	AddCluster(context.Context, *AddClusterRequest) (*AddClusterResponse, error)
 	RemoveCluster(context.Context, *RemoveClusterRequest) (*RemoveClusterResponse, error)
 	DeleteOverlay(context.Context, *DeleteOverlayRequest) (*DeleteOverlayResponse, error)
+	// Registry of the created networks and slices. Bearer tokens are never returned.
+	GetNetwork(context.Context, *GetNetworkRequest) (*GetNetworkResponse, error)
+	ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error)
+	GetSlice(context.Context, *GetSliceRequest) (*GetSliceResponse, error)
+	GetSliceTopology(context.Context, *GetSliceTopologyRequest) (*GetSliceTopologyResponse, error)
+	ListSlices(context.Context, *ListSlicesRequest) (*ListSlicesResponse, error)
+	// Member cluster registration
+	RegisterCluster(context.Context, *RegisterClusterRequest) (*RegisterClusterResponse, error)
 	mustEmbedUnimplementedL2SMMultiDomainServiceServer()
 }
 
@@ -187,6 +269,24 @@ func (UnimplementedL2SMMultiDomainServiceServer) RemoveCluster(context.Context,
 func (UnimplementedL2SMMultiDomainServiceServer) DeleteOverlay(context.Context, *DeleteOverlayRequest) (*DeleteOverlayResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteOverlay not implemented")
 }
+func (UnimplementedL2SMMultiDomainServiceServer) GetNetwork(context.Context, *GetNetworkRequest) (*GetNetworkResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetNetwork not implemented")
+}
+func (UnimplementedL2SMMultiDomainServiceServer) ListNetworks(context.Context, *ListNetworksRequest) (*ListNetworksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListNetworks not implemented")
+}
+func (UnimplementedL2SMMultiDomainServiceServer) GetSlice(context.Context, *GetSliceRequest) (*GetSliceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSlice not implemented")
+}
+func (UnimplementedL2SMMultiDomainServiceServer) GetSliceTopology(context.Context, *GetSliceTopologyRequest) (*GetSliceTopologyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSliceTopology not implemented")
+}
+func (UnimplementedL2SMMultiDomainServiceServer) ListSlices(context.Context, *ListSlicesRequest) (*ListSlicesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSlices not implemented")
+}
+func (UnimplementedL2SMMultiDomainServiceServer) RegisterCluster(context.Context, *RegisterClusterRequest) (*RegisterClusterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegisterCluster not implemented")
+}
 func (UnimplementedL2SMMultiDomainServiceServer) mustEmbedUnimplementedL2SMMultiDomainServiceServer() {
 }
 func (UnimplementedL2SMMultiDomainServiceServer) testEmbeddedByValue() {}
@@ -353,6 +453,114 @@ func _L2SMMultiDomainService_DeleteOverlay_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _L2SMMultiDomainService_GetNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(L2SMMultiDomainServiceServer).GetNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: L2SMMultiDomainService_GetNetwork_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(L2SMMultiDomainServiceServer).GetNetwork(ctx, req.(*GetNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _L2SMMultiDomainService_ListNetworks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNetworksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(L2SMMultiDomainServiceServer).ListNetworks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: L2SMMultiDomainService_ListNetworks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(L2SMMultiDomainServiceServer).ListNetworks(ctx, req.(*ListNetworksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _L2SMMultiDomainService_GetSlice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSliceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(L2SMMultiDomainServiceServer).GetSlice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: L2SMMultiDomainService_GetSlice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(L2SMMultiDomainServiceServer).GetSlice(ctx, req.(*GetSliceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _L2SMMultiDomainService_GetSliceTopology_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSliceTopologyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(L2SMMultiDomainServiceServer).GetSliceTopology(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: L2SMMultiDomainService_GetSliceTopology_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(L2SMMultiDomainServiceServer).GetSliceTopology(ctx, req.(*GetSliceTopologyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _L2SMMultiDomainService_ListSlices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSlicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(L2SMMultiDomainServiceServer).ListSlices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: L2SMMultiDomainService_ListSlices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(L2SMMultiDomainServiceServer).ListSlices(ctx, req.(*ListSlicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _L2SMMultiDomainService_RegisterCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(L2SMMultiDomainServiceServer).RegisterCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: L2SMMultiDomainService_RegisterCluster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(L2SMMultiDomainServiceServer).RegisterCluster(ctx, req.(*RegisterClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // L2SMMultiDomainService_ServiceDesc is the grpc.ServiceDesc for L2SMMultiDomainService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -392,6 +600,30 @@ var L2SMMultiDomainService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteOverlay",
 			Handler:    _L2SMMultiDomainService_DeleteOverlay_Handler,
 		},
+		{
+			MethodName: "GetNetwork",
+			Handler:    _L2SMMultiDomainService_GetNetwork_Handler,
+		},
+		{
+			MethodName: "ListNetworks",
+			Handler:    _L2SMMultiDomainService_ListNetworks_Handler,
+		},
+		{
+			MethodName: "GetSlice",
+			Handler:    _L2SMMultiDomainService_GetSlice_Handler,
+		},
+		{
+			MethodName: "GetSliceTopology",
+			Handler:    _L2SMMultiDomainService_GetSliceTopology_Handler,
+		},
+		{
+			MethodName: "ListSlices",
+			Handler:    _L2SMMultiDomainService_ListSlices_Handler,
+		},
+		{
+			MethodName: "RegisterCluster",
+			Handler:    _L2SMMultiDomainService_RegisterCluster_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "l2sces.proto",
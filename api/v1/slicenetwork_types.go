@@ -33,7 +33,29 @@ type SliceClusterConfig struct {
 	// but strictly following your prompt, we are only adding Name, Type, and Provider.
 }
 
+// ClusterSelector selects member clusters dynamically. Exactly one of its fields must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.labelSelector) != has(self.placement)",message="exactly one of labelSelector and placement must be set"
+type ClusterSelector struct {
+	// LabelSelector selects the member clusters registered in the management cluster whose l2sm-cert
+	// Secret carries matching labels.
+	// +optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Placement selects the clusters decided by an Open Cluster Management Placement in the namespace of
+	// the SliceNetwork.
+	// +optional
+	Placement *PlacementReference `json:"placement,omitempty"`
+}
+
+// PlacementReference refers to an Open Cluster Management Placement.
+type PlacementReference struct {
+	// Name is the name of the Placement.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
 // SliceNetworkSpec defines the desired state of SliceNetwork
+// +kubebuilder:validation:XValidation:rule="has(self.clusters) != has(self.clusterSelector)",message="exactly one of clusters and clusterSelector must be set"
 type SliceNetworkSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -41,7 +63,13 @@ type SliceNetworkSpec struct {
 	// Clusters is an array of configurations. The controller will create an L2Network
 	// in each of these clusters based on the provided Name, Type, and Provider.
 	// +kubebuilder:validation:MinItems=1
-	Clusters []string `json:"clusters"`
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// ClusterSelector selects the clusters dynamically instead of listing them. The SliceNetwork extends to
+	// the clusters that start matching, and is removed from the ones that stop matching.
+	// +optional
+	ClusterSelector *ClusterSelector `json:"clusterSelector,omitempty"`
 
 	// Type specifies the type of L2Network to create in this cluster (e.g., ext-vnet, vnet, vlink).
 	Type l2smv1.NetworkType `json:"type"`
@@ -62,6 +90,13 @@ type SliceClusterStatus struct {
 	Message string `json:"message,omitempty"`
 }
 
+// Values of SliceClusterStatus.Status
+const (
+	ClusterStatusReady   = "Ready"
+	ClusterStatusPending = "Pending"
+	ClusterStatusError   = "Error"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
@@ -87,6 +122,16 @@ type SliceNetworkStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Clusters are the clusters the SliceNetwork spans, as listed in its spec or resolved from its
+	// cluster selector.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// ProvisionedClusters are the clusters the L2Network was applied in, so that it is removed from them when
+	// they leave the SliceNetwork, and from all of them when the SliceNetwork is deleted.
+	// +optional
+	ProvisionedClusters []string `json:"provisionedClusters,omitempty"`
+
 	// ClusterStatuses tracks the status of the L2Network provisioning in each defined cluster.
 	// +optional
 	ClusterStatuses []SliceClusterStatus `json:"clusterStatuses,omitempty"`
@@ -41,15 +41,46 @@ type OverlayCluster struct {
 	// Gateway is the public IP or Domain where this node's NED can be reached.
 	// This maps to 'NeighborSpec.Domain' for other nodes and 'NodeConfigSpec.IPAddress' for itself.
 	Gateway *l2smv1.NodeConfigSpec `json:"gateway,omitempty"`
+
+	// Nodes of the cluster that are connected to the slice through an intra-cluster Overlay.
+	// If empty, only the NetworkEdgeDevice is deployed in the cluster.
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+
+	// Region of the cluster. The RegionalMesh strategy meshes the clusters of each region together.
+	// +optional
+	Region string `json:"region,omitempty"`
+}
+
+// TopologyStrategySpec generates the links between the clusters.
+type TopologyStrategySpec struct {
+	// Type of the topology. Clusters are taken in name order.
+	// +kubebuilder:validation:Enum=FullMesh;Star;Ring;Line;KRegular;RegionalMesh
+	Type string `json:"type"`
+
+	// Hub of a Star, or of its region with RegionalMesh. Defaults to the first cluster by name.
+	// +optional
+	Hub string `json:"hub,omitempty"`
+
+	// Degree of every cluster in a KRegular topology.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Degree int32 `json:"degree,omitempty"`
 }
 
-// OverlayTopology defines the graph of the network.
+// OverlayTopology defines the graph of the network. Clusters are fully meshed if neither links nor a
+// strategy are given.
+// +kubebuilder:validation:XValidation:rule="!(has(self.links) && has(self.strategy))",message="links and strategy can't both be set"
 type OverlayTopology struct {
 	// List of clusters participating in this overlay
 	Nodes []OverlayCluster `json:"nodes"`
 
 	// List of connections between the clusters
 	Links []OverlayLink `json:"links,omitempty"`
+
+	// Strategy that generates the connections between the clusters, instead of listing them
+	// +optional
+	Strategy *TopologyStrategySpec `json:"strategy,omitempty"`
 }
 
 // SliceOverlaySpec defines the desired state of SliceOverlay
@@ -82,10 +113,31 @@ type SliceOverlayStatus struct {
 	// Number of NetworkEdgeDevices successfully deployed
 	DeployedSwitches int32 `json:"deployedSwitches,omitempty"`
 
+	// Clusters the NetworkEdgeDevice was deployed in, so that it is removed from the clusters that leave the
+	// topology, and from all of them when the SliceOverlay is deleted.
+	// +optional
+	Clusters []string `json:"clusters,omitempty"`
+
+	// OverlayClusters are the clusters the Overlay was deployed in, as they listed their nodes.
+	// +optional
+	OverlayClusters []string `json:"overlayClusters,omitempty"`
+
+	// NEDName is the name of the deployed NetworkEdgeDevices, which follows the name of the provider.
+	// +optional
+	NEDName string `json:"nedName,omitempty"`
+
 	// Overall health of the slice connectivity
 	Phase string `json:"phase,omitempty"`
 }
 
+// Values of SliceOverlayStatus.Phase
+const (
+	SliceOverlayPhasePending  = "Pending"
+	SliceOverlayPhaseReady    = "Ready"
+	SliceOverlayPhaseFailed   = "Failed"
+	SliceOverlayPhaseDeleting = "Deleting"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase"
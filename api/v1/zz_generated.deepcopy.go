@@ -26,6 +26,31 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSelector) DeepCopyInto(out *ClusterSelector) {
+	*out = *in
+	if in.LabelSelector != nil {
+		in, out := &in.LabelSelector, &out.LabelSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PlacementReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSelector.
+func (in *ClusterSelector) DeepCopy() *ClusterSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OverlayCluster) DeepCopyInto(out *OverlayCluster) {
 	*out = *in
@@ -34,6 +59,11 @@ func (in *OverlayCluster) DeepCopyInto(out *OverlayCluster) {
 		*out = new(apiv1.NodeConfigSpec)
 		**out = **in
 	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverlayCluster.
@@ -76,6 +106,11 @@ func (in *OverlayTopology) DeepCopyInto(out *OverlayTopology) {
 		*out = make([]OverlayLink, len(*in))
 		copy(*out, *in)
 	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(TopologyStrategySpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OverlayTopology.
@@ -88,6 +123,21 @@ func (in *OverlayTopology) DeepCopy() *OverlayTopology {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementReference) DeepCopyInto(out *PlacementReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementReference.
+func (in *PlacementReference) DeepCopy() *PlacementReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SliceClusterConfig) DeepCopyInto(out *SliceClusterConfig) {
 	*out = *in
@@ -185,6 +235,11 @@ func (in *SliceNetworkSpec) DeepCopyInto(out *SliceNetworkSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ClusterSelector != nil {
+		in, out := &in.ClusterSelector, &out.ClusterSelector
+		*out = new(ClusterSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Provider != nil {
 		in, out := &in.Provider, &out.Provider
 		*out = new(apiv1.ProviderSpec)
@@ -205,6 +260,16 @@ func (in *SliceNetworkSpec) DeepCopy() *SliceNetworkSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SliceNetworkStatus) DeepCopyInto(out *SliceNetworkStatus) {
 	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProvisionedClusters != nil {
+		in, out := &in.ProvisionedClusters, &out.ProvisionedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.ClusterStatuses != nil {
 		in, out := &in.ClusterStatuses, &out.ClusterStatuses
 		*out = make([]SliceClusterStatus, len(*in))
@@ -328,6 +393,16 @@ func (in *SliceOverlayStatus) DeepCopyInto(out *SliceOverlayStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OverlayClusters != nil {
+		in, out := &in.OverlayClusters, &out.OverlayClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SliceOverlayStatus.
@@ -339,3 +414,18 @@ func (in *SliceOverlayStatus) DeepCopy() *SliceOverlayStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopologyStrategySpec) DeepCopyInto(out *TopologyStrategySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopologyStrategySpec.
+func (in *TopologyStrategySpec) DeepCopy() *TopologyStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TopologyStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
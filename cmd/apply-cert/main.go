@@ -15,61 +15,276 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 )
 
-func main() {
+const usage = `Usage: apply-cert [create|register|update|list|delete] [options] [certificate_file_path]
+
+  create   stores the credentials of a member cluster, replacing the ones it had (default)
+  register stores the server, CA and credentials of a member cluster taken from its kubeconfig,
+           optionally creating a service account for l2sces in it
+  update   changes the given credentials of a registered member cluster, keeping the rest
+  list     lists the registered member clusters and when their certificates expire
+  delete   removes the credentials of a member cluster
 
+Run apply-cert <command> -h for the options of a command.`
+
+func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: program [options] <certificate_file_path>")
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	// Without a command, the credentials are created as in earlier versions
+	command, args := "create", os.Args[1:]
+	if !strings.HasPrefix(args[0], "-") && (len(args) > 1 || isCommand(args[0])) {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "create", "update":
+		err = apply(command, args)
+	case "register":
+		err = register(args)
+	case "list":
+		err = list(args)
+	case "delete":
+		err = remove(args)
+	default:
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
+}
+
+func isCommand(arg string) bool {
+	switch arg {
+	case "create", "register", "update", "list", "delete":
+		return true
+	}
+	return false
+}
 
-	// Parse command-line flags
+// newFlagSet returns the flags of a command, with the ones to reach the management cluster.
+func newFlagSet(command string, defaultNamespace string) (*flag.FlagSet, *string, *string) {
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
 	var kubeconfig *string
-	var namespace *string
-	var clusterName *string
 	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
+		kubeconfig = flags.String("kubeconfig", filepath.Join(home, ".kube", "config"), "(optional) absolute path to the kubeconfig file")
 	} else {
-		kubeconfig = flag.String("kubeconfig", "", "absolute path to the kubeconfig file")
+		kubeconfig = flags.String("kubeconfig", "", "absolute path to the kubeconfig file")
 	}
-	namespace = flag.String("namespace", "default", "Kubernetes namespace")
-	clusterName = flag.String("clustername", "test", "Cluster name")
-	flag.Parse()
+	namespace := flags.String("namespace", defaultNamespace, "Kubernetes namespace")
+	return flags, kubeconfig, namespace
+}
 
-	// Get the certificate file path from the last argument
-	certificateFilePath := flag.Arg(0)
-	if certificateFilePath == "" {
-		fmt.Println("Certificate file path is required as the last argument.")
-		os.Exit(1)
+func newCertStore(kubeconfig string, namespace string) (*operator.CertStore, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+	return operator.NewCertStore(config, namespace)
+}
+
+func apply(command string, args []string) error {
+	flags, kubeconfig, namespace := newFlagSet(command, "default")
+	clusterName := flags.String("clustername", "test", "Cluster name")
+	server := flags.String("server", "", "(optional) API server URL of the cluster, needed by the slice controllers")
+	token := flags.String("token", "", "(optional) bearer token for the cluster, so that requests can reference it by name")
+	memberKubeconfig := flags.String("member-kubeconfig", "", "(optional) kubeconfig of the cluster, instead of the server, token and certificate")
+	clientCert := flags.String("client-cert", "", "(optional) client certificate file for the cluster, instead of the token")
+	clientKey := flags.String("client-key", "", "(optional) client key file for the cluster, instead of the token")
+	flags.Parse(args)
+
+	certificateFile := flags.Arg(0)
+	credentials := operator.ClusterCredentials{Server: *server, Token: *token}
+	files := []struct {
+		path *string
+		data *[]byte
+		name string
+	}{
+		{&certificateFile, &credentials.CAData, "certificate"},
+		{memberKubeconfig, &credentials.Kubeconfig, "member kubeconfig"},
+		{clientCert, &credentials.CertData, "client certificate"},
+		{clientKey, &credentials.KeyData, "client key"},
+	}
+	for _, file := range files {
+		if *file.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(*file.path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s file: %v", file.name, err)
+		}
+		*file.data = data
+	}
+	if (len(credentials.CertData) > 0) != (len(credentials.KeyData) > 0) {
+		return fmt.Errorf("the client certificate and key must be given together")
 	}
 
-	// Read the certificate data from the file
-	certificate, err := os.ReadFile(certificateFilePath)
+	store, err := newCertStore(*kubeconfig, *namespace)
 	if err != nil {
-		fmt.Printf("Failed to read certificate file: %v\n", err)
-		os.Exit(1)
+		return err
+	}
+	ctx := context.Background()
+	if command == "update" {
+		registered, err := store.Get(ctx, *clusterName)
+		if err != nil {
+			return err
+		}
+		if registered == nil {
+			return fmt.Errorf("cluster %s is not registered in namespace %s", *clusterName, *namespace)
+		}
+		credentials = overrideCredentials(*registered, credentials)
+	} else if credentials.CAData == nil && credentials.Kubeconfig == nil {
+		return fmt.Errorf("certificate file path is required as the last argument")
+	}
+
+	created, err := store.Apply(ctx, *clusterName, credentials)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Println("Secret created successfully.")
+	} else {
+		fmt.Println("Secret updated successfully.")
+	}
+	return nil
+}
+
+func register(args []string) error {
+	flags, kubeconfig, namespace := newFlagSet("register", "default")
+	clusterName := flags.String("clustername", "", "Cluster name")
+	memberKubeconfig := flags.String("member-kubeconfig", "", "kubeconfig of the cluster")
+	contextName := flags.String("context", "", "(optional) context of the member kubeconfig, its current one by default")
+	serviceAccount := flags.Bool("service-account", false, "Create a service account for l2sces in the cluster and register a token minted for it")
+	serviceAccountNamespace := flags.String("service-account-namespace", operator.DefaultServiceAccountNamespace, "Namespace of the service account")
+	serviceAccountName := flags.String("service-account-name", operator.DefaultServiceAccountName, "Name of the service account")
+	tokenDuration := flags.Duration("token-duration", operator.DefaultTokenDuration, "How long the token of the service account is valid")
+	flags.Parse(args)
+	if *clusterName == "" || *memberKubeconfig == "" {
+		return fmt.Errorf("the cluster name and member kubeconfig are required")
 	}
 
-	// Build Kubernetes client configuration
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	kubeconfigData, err := os.ReadFile(*memberKubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to read member kubeconfig: %v", err)
+	}
+	store, err := newCertStore(*kubeconfig, *namespace)
+	if err != nil {
+		return err
+	}
+	registration, err := operator.RegisterCluster(context.Background(), store, *clusterName, kubeconfigData, operator.RegistrationOptions{
+		Context:                 *contextName,
+		CreateServiceAccount:    *serviceAccount,
+		ServiceAccountNamespace: *serviceAccountNamespace,
+		ServiceAccountName:      *serviceAccountName,
+		TokenDuration:           *tokenDuration,
+	})
 	if err != nil {
-		panic(err)
+		return err
+	}
+
+	fmt.Printf("Cluster %s registered with server %s.\n", *clusterName, registration.Server)
+	if registration.ServiceAccount != "" {
+		fmt.Printf("Service account %s created, its token expires at %s.\n", registration.ServiceAccount, registration.TokenExpiry.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// overrideCredentials returns the registered credentials with the fields given in changes replaced.
+func overrideCredentials(registered operator.ClusterCredentials, changes operator.ClusterCredentials) operator.ClusterCredentials {
+	if changes.Server != "" {
+		registered.Server = changes.Server
+	}
+	if changes.Token != "" {
+		registered.Token = changes.Token
+	}
+	if len(changes.CAData) > 0 {
+		registered.CAData = changes.CAData
+	}
+	if len(changes.Kubeconfig) > 0 {
+		registered.Kubeconfig = changes.Kubeconfig
+	}
+	if len(changes.CertData) > 0 {
+		registered.CertData, registered.KeyData = changes.CertData, changes.KeyData
 	}
+	return registered
+}
+
+func list(args []string) error {
+	flags, kubeconfig, namespace := newFlagSet("list", "")
+	warnBefore := flags.Duration("warn-before", operator.DefaultExpiryWarning, "How long before a certificate expires it is marked")
+	flags.Parse(args)
 
-	// Create the secret
-	err = operator.CreateCertificateSecrets(config, *namespace, *clusterName, certificate)
+	store, err := newCertStore(*kubeconfig, *namespace)
+	if err != nil {
+		return err
+	}
+	clusters, err := store.List(context.Background())
 	if err != nil {
-		panic(err)
+		return err
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "CLUSTER\tSECRET\tCA EXPIRY\tCLIENT EXPIRY")
+	for _, cluster := range clusters {
+		expiries, err := cluster.Credentials.CertificateExpiries()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cluster %s: %v\n", cluster.Name, err)
+		}
+		fmt.Fprintf(writer, "%s\t%s/%s\t%s\t%s\n", cluster.Name, cluster.Namespace, cluster.SecretName,
+			formatExpiry(expiries, operator.CACertificate, *warnBefore), formatExpiry(expiries, operator.ClientCertificate, *warnBefore))
+	}
+	return writer.Flush()
+}
+
+func formatExpiry(expiries map[string]time.Time, certificate string, warnBefore time.Duration) string {
+	expiry, exists := expiries[certificate]
+	if !exists {
+		return "-"
+	}
+	switch remaining := time.Until(expiry); {
+	case remaining <= 0:
+		return expiry.Format(time.RFC3339) + " (EXPIRED)"
+	case remaining < warnBefore:
+		return expiry.Format(time.RFC3339) + " (EXPIRES SOON)"
+	}
+	return expiry.Format(time.RFC3339)
+}
+
+func remove(args []string) error {
+	flags, kubeconfig, namespace := newFlagSet("delete", "default")
+	clusterName := flags.String("clustername", "", "Cluster name")
+	flags.Parse(args)
+	if *clusterName == "" {
+		return fmt.Errorf("the cluster name is required")
 	}
 
-	fmt.Println("Secret created successfully.")
+	store, err := newCertStore(*kubeconfig, *namespace)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(context.Background(), *clusterName); err != nil {
+		return err
+	}
+	fmt.Println("Secret deleted successfully.")
+	return nil
 }
@@ -20,6 +20,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -37,6 +38,7 @@ import (
 
 	l2scesv1 "github.com/Networks-it-uc3m/l2sc-es/api/v1"
 	"github.com/Networks-it-uc3m/l2sc-es/internal/controller"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -61,6 +63,8 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var certNamespace string
+	var certExpiryWarning time.Duration
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -79,6 +83,10 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&certNamespace, "cert-namespace", "",
+		"The namespace of the Secrets holding the credentials of the member clusters. Every namespace if empty.")
+	flag.DurationVar(&certExpiryWarning, "cert-expiry-warning", operator.DefaultExpiryWarning,
+		"How long before a certificate of a member cluster expires it is warned about.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -178,22 +186,36 @@ func main() {
 		os.Exit(1)
 	}
 
+	memberClusters := &controller.SecretMemberClusters{Reader: mgr.GetAPIReader(), Namespace: certNamespace}
 	if err := (&controller.SliceOverlayReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		MemberClusters: memberClusters,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SliceOverlay")
 		os.Exit(1)
 	}
 	if err := (&controller.SliceNetworkReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		MemberClusters: memberClusters,
+		CertNamespace:  certNamespace,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SliceNetwork")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
+	certStore, err := operator.NewCertStore(mgr.GetConfig(), certNamespace)
+	if err != nil {
+		setupLog.Error(err, "unable to create the certificate store")
+		os.Exit(1)
+	}
+	if err := mgr.Add(&operator.ExpiryMonitor{Store: certStore, WarnBefore: certExpiryWarning}); err != nil {
+		setupLog.Error(err, "unable to set up the certificate expiry monitor")
+		os.Exit(1)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -0,0 +1,125 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/mdclient"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/registry"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologygenerator"
+)
+
+// render-topology renders the clusters and links of a slice, given as in a CreateSlice request, as DOT,
+// Mermaid or JSON. With the kubeconfigs of the member clusters, the live status of the links is overlaid.
+func main() {
+	args, err := takeArguments()
+	if err != nil {
+		fmt.Printf("Invalid arguments: %v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	values, err := os.ReadFile(args.input)
+	if err != nil {
+		fmt.Printf("Failed to read input file: %v\n", err)
+		os.Exit(1)
+	}
+	slice, err := parseSlice(values)
+	if err != nil {
+		fmt.Printf("Failed to parse the slice: %v\n", err)
+		os.Exit(1)
+	}
+
+	name := registry.SliceName(slice)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(args.input), filepath.Ext(args.input))
+	}
+	graph, err := topologygenerator.NewGraph(name, slice)
+	if err != nil {
+		fmt.Printf("Failed to compute the slice topology: %v\n", err)
+		os.Exit(1)
+	}
+
+	if args.kubeconfigDir != "" {
+		client, err := mdclient.NewClient(mdclient.KubeconfigType, args.kubeconfigDir)
+		if err != nil {
+			fmt.Printf("Failed to reach the member clusters: %v\n", err)
+			os.Exit(1)
+		}
+		if reader, ok := client.(mdclient.LinkStatusReader); ok {
+			graph.SetLinkStatus(reader.ConnectedNeighbors(context.Background(), slice, args.namespace))
+		}
+	}
+
+	rendered, err := topologygenerator.Render(graph, args.format)
+	if err != nil {
+		fmt.Printf("Failed to render the topology: %v\n", err)
+		os.Exit(1)
+	}
+	if args.output != "" {
+		if err := os.WriteFile(args.output, rendered, 0644); err != nil {
+			fmt.Printf("Failed to write the output file: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		fmt.Print(string(rendered))
+	}
+}
+
+type arguments struct {
+	input         string
+	output        string
+	format        string
+	kubeconfigDir string
+	namespace     string
+}
+
+func takeArguments() (arguments, error) {
+	input := flag.String("input", "", "YAML or JSON file with the slice, as in a CreateSlice request. Required")
+	output := flag.String("output", "", "file the topology is written to. Printed if empty")
+	format := flag.String("format", topologygenerator.JSONFormat, "format of the topology: "+strings.Join(topologygenerator.Formats, ", "))
+	kubeconfigDir := flag.String("kubeconfig-dir", "", "directory of kubeconfigs of the member clusters, to overlay the live status of the links")
+	namespace := flag.String("namespace", "default", "namespace of the NetworkEdgeDevices of the slice")
+	flag.Parse()
+
+	if *input == "" {
+		return arguments{}, errors.New("input file is not defined")
+	}
+	return arguments{input: *input, output: *output, format: *format, kubeconfigDir: *kubeconfigDir, namespace: *namespace}, nil
+}
+
+// parseSlice reads a slice in YAML or JSON. Fields that are not part of the slice are ignored.
+func parseSlice(values []byte) (*l2sces.Slice, error) {
+	data, err := yaml.YAMLToJSON(values)
+	if err != nil {
+		return nil, err
+	}
+	slice := &l2sces.Slice{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, slice); err != nil {
+		return nil, err
+	}
+	return slice, nil
+}
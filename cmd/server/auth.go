@@ -0,0 +1,384 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v2"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/registry"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
+)
+
+// AuthorizationType selects how the operations of authenticated callers are authorized.
+type AuthorizationType string
+
+const (
+	// KubernetesAuthorization authorizes operations through SubjectAccessReviews, so that they are granted
+	// with RBAC roles on the networks and slices resources of the l2sces.l2sm.io group.
+	KubernetesAuthorization AuthorizationType = "kubernetes"
+	// PolicyAuthorization authorizes operations with the rules of a policy file.
+	PolicyAuthorization AuthorizationType = "policy"
+)
+
+const (
+	// authGroup is the API group of the resources operations are authorized on.
+	authGroup        = "l2sces.l2sm.io"
+	networksResource = "networks"
+	slicesResource   = "slices"
+	clustersResource = "clusters"
+)
+
+// identity is an authenticated caller.
+type identity struct {
+	User   string
+	UID    string
+	Groups []string
+	Extra  map[string][]string
+}
+
+// attributes describe an operation to authorize: a verb on a named network or slice in a namespace.
+type attributes struct {
+	Verb      string
+	Resource  string
+	Name      string
+	Namespace string
+}
+
+func (attrs attributes) String() string {
+	return fmt.Sprintf("%s %s/%s in namespace %s", attrs.Verb, attrs.Resource, attrs.Name, attrs.Namespace)
+}
+
+// authenticator identifies the caller of a request.
+type authenticator interface {
+	authenticate(ctx context.Context) (*identity, error)
+}
+
+// authorizer decides whether a caller may perform an operation. A denial comes with its reason.
+type authorizer interface {
+	authorize(ctx context.Context, user *identity, attrs attributes) (bool, string, error)
+}
+
+// auth authenticates the callers of the gRPC server and authorizes every operation of their requests.
+type auth struct {
+	authenticator authenticator
+	authorizer    authorizer
+	// store resolves the namespace of the networks and slices that requests refer to by name.
+	store registry.Store
+	// certNamespace is the namespace member clusters are registered in.
+	certNamespace string
+}
+
+type identityKey struct{}
+
+// unaryInterceptor rejects unauthenticated calls with Unauthenticated, and calls with any unauthorized
+// operation with PermissionDenied. List calls only need authentication: their handlers return the
+// records the caller may get. Calls without known operations are denied, so that no new call goes
+// unauthorized.
+func (a *auth) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	user, err := a.authenticator.authenticate(ctx)
+	if err != nil {
+		log.Printf("Unauthenticated call to %s: %v", info.FullMethod, err)
+		return nil, status.Errorf(codes.Unauthenticated, "could not authenticate: %v", err)
+	}
+
+	operations, err := a.requestAttributes(ctx, req)
+	if err != nil {
+		log.Printf("Denied %s to %s: %v", info.FullMethod, user.User, err)
+		return nil, status.Errorf(codes.PermissionDenied, "%s may not call %s: %v", user.User, info.FullMethod, err)
+	}
+	for _, attrs := range operations {
+		allowed, reason, err := a.authorizer.authorize(ctx, user, attrs)
+		if err != nil {
+			log.Printf("Could not authorize %s to %s: %v", info.FullMethod, user.User, err)
+			return nil, status.Errorf(codes.Unavailable, "could not authorize request: %v", err)
+		}
+		if !allowed {
+			log.Printf("Denied %s to %s: %s: %s", info.FullMethod, user.User, attrs, reason)
+			return nil, status.Errorf(codes.PermissionDenied, "%s may not %s", user.User, attrs)
+		}
+	}
+	return handler(context.WithValue(ctx, identityKey{}, user), req)
+}
+
+// allowed tells whether the caller of the request may perform all the operations. Every operation is
+// allowed when authorization is disabled.
+func (a *auth) allowed(ctx context.Context, attrs []attributes) bool {
+	if a == nil {
+		return true
+	}
+	user, ok := ctx.Value(identityKey{}).(*identity)
+	if !ok {
+		return false
+	}
+	for _, operation := range attrs {
+		if allowed, _, err := a.authorizer.authorize(ctx, user, operation); err != nil || !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// requestAttributes returns the operations performed by a request, one per namespace it changes.
+// Networks and slices given only by name are looked up in the registry to find their namespace. List
+// requests perform none, as their handlers filter the records; other unknown requests fail.
+func (a *auth) requestAttributes(ctx context.Context, req interface{}) ([]attributes, error) {
+	switch req := req.(type) {
+	case *l2sces.CreateNetworkRequest:
+		return networkAttributes("create", req.GetNetwork(), req.GetNamespace()), nil
+	case *l2sces.DeleteNetworkRequest:
+		network, namespace := req.GetNetwork(), req.GetNamespace()
+		if len(network.GetClusters()) == 0 {
+			if record, err := a.store.GetNetwork(ctx, network.GetName()); err == nil {
+				network, namespace = record.GetNetwork(), utils.DefaultIfEmpty(namespace, record.GetNamespace())
+			}
+		}
+		return networkAttributes("delete", network, namespace), nil
+	case *l2sces.GetNetworkRequest:
+		record, _ := a.store.GetNetwork(ctx, req.GetName())
+		return networkAttributes("get", &l2sces.L2Network{Name: req.GetName(), Clusters: record.GetNetwork().GetClusters()}, record.GetNamespace()), nil
+	case *l2sces.CreateSliceRequest:
+		return []attributes{sliceAttributes("create", registry.SliceName(req.GetSlice()), req.GetNamespace())}, nil
+	case *l2sces.DeleteSliceRequest:
+		return []attributes{a.resolveSliceAttributes(ctx, "delete", req.GetSlice(), "", "", req.GetNamespace())}, nil
+	case *l2sces.GetSliceRequest:
+		return []attributes{a.resolveSliceAttributes(ctx, "get", nil, req.GetName(), "", "")}, nil
+	case *l2sces.GetSliceTopologyRequest:
+		return []attributes{a.resolveSliceAttributes(ctx, "get", nil, req.GetName(), "", "")}, nil
+	case *l2sces.CreateOverlayRequest:
+		return []attributes{sliceAttributes("create", req.GetOverlay().GetProvider().GetName(), req.GetNamespace())}, nil
+	case *l2sces.AddClusterRequest:
+		return []attributes{a.resolveSliceAttributes(ctx, "update", req.GetSlice(), req.GetSliceName(), req.GetProviderName(), req.GetNamespace())}, nil
+	case *l2sces.RemoveClusterRequest:
+		return []attributes{a.resolveSliceAttributes(ctx, "update", req.GetSlice(), req.GetOverlayName(), req.GetProviderName(), req.GetNamespace())}, nil
+	case *l2sces.DeleteOverlayRequest:
+		return []attributes{a.resolveSliceAttributes(ctx, "delete", req.GetSlice(), req.GetOverlayName(), req.GetProviderName(), req.GetNamespace())}, nil
+	case *l2sces.ListNetworksRequest, *l2sces.ListSlicesRequest:
+		return nil, nil
+	case *l2sces.RegisterClusterRequest:
+		// Member clusters are shared by every namespace, so they are registered in the one of their Secrets
+		return []attributes{{Verb: "create", Resource: clustersResource, Name: req.GetClusterName(), Namespace: a.certNamespace}}, nil
+	}
+	return nil, fmt.Errorf("no operations known for request %T", req)
+}
+
+// resolveSliceAttributes returns the operation on the slice a request changes, resolved as the handlers do:
+// a slice with clusters is named after itself or, lacking a provider, after the provider of the request.
+// Otherwise it is looked up in the registry by name, which also gives its namespace.
+func (a *auth) resolveSliceAttributes(ctx context.Context, verb string, slice *l2sces.Slice, name string, providerName string, namespace string) attributes {
+	if len(slice.GetClusters()) > 0 {
+		if slice.GetProvider() == nil {
+			return sliceAttributes(verb, utils.DefaultIfEmpty(slice.GetName(), providerName), namespace)
+		}
+		return sliceAttributes(verb, registry.SliceName(slice), namespace)
+	}
+
+	name = utils.DefaultIfEmpty(name, registry.SliceName(slice))
+	if record, err := a.store.GetSlice(ctx, name); err == nil {
+		name, namespace = registry.SliceName(record.GetSlice()), utils.DefaultIfEmpty(namespace, record.GetNamespace())
+	}
+	return sliceAttributes(verb, name, namespace)
+}
+
+// networkAttributes returns the operation on a network in every namespace it spans, as clusters may place
+// it in their own namespace.
+func networkAttributes(verb string, network *l2sces.L2Network, namespace string) []attributes {
+	namespace = utils.DefaultIfEmpty(namespace, "default")
+	namespaces := []string{namespace}
+	for _, cluster := range network.GetClusters() {
+		clusterNamespace := utils.DefaultIfEmpty(cluster.GetNamespace(), namespace)
+		if !slices.Contains(namespaces, clusterNamespace) {
+			namespaces = append(namespaces, clusterNamespace)
+		}
+	}
+
+	attrs := make([]attributes, len(namespaces))
+	for index, namespace := range namespaces {
+		attrs[index] = attributes{Verb: verb, Resource: networksResource, Name: network.GetName(), Namespace: namespace}
+	}
+	return attrs
+}
+
+func sliceAttributes(verb string, name string, namespace string) attributes {
+	return attributes{Verb: verb, Resource: slicesResource, Name: name, Namespace: utils.DefaultIfEmpty(namespace, "default")}
+}
+
+// kubernetesAuthenticator identifies callers by their verified client certificate, or by the bearer token
+// in their authorization metadata, reviewed by the API server.
+type kubernetesAuthenticator struct {
+	clientset kubernetes.Interface
+}
+
+func (authn kubernetesAuthenticator) authenticate(ctx context.Context) (*identity, error) {
+	if token := bearerToken(ctx); token != "" {
+		review, err := authn.clientset.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{Token: token},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error reviewing token: %v", err)
+		}
+		if !review.Status.Authenticated {
+			return nil, fmt.Errorf("invalid token: %s", utils.DefaultIfEmpty(review.Status.Error, "not authenticated"))
+		}
+		user := review.Status.User
+		extra := map[string][]string{}
+		for key, values := range user.Extra {
+			extra[key] = []string(values)
+		}
+		return &identity{User: user.Username, UID: user.UID, Groups: user.Groups, Extra: extra}, nil
+	}
+
+	if user := certificateIdentity(ctx); user != nil {
+		return user, nil
+	}
+	return nil, errors.New("no bearer token nor client certificate")
+}
+
+// bearerToken returns the token of the authorization metadata of the request, if any.
+func bearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		if token, found := strings.CutPrefix(value, "Bearer "); found {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// certificateIdentity returns the caller named by its verified client certificate: the common name is the
+// user and the organizations are its groups, as in Kubernetes.
+func certificateIdentity(ctx context.Context) *identity {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	subject := tlsInfo.State.VerifiedChains[0][0].Subject
+	return &identity{User: subject.CommonName, Groups: subject.Organization}
+}
+
+// subjectAccessReviewer authorizes operations through SubjectAccessReviews.
+type subjectAccessReviewer struct {
+	clientset kubernetes.Interface
+}
+
+func (authz subjectAccessReviewer) authorize(ctx context.Context, user *identity, attrs attributes) (bool, string, error) {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, values := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(values)
+	}
+	review, err := authz.clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User: user.User, UID: user.UID, Groups: user.Groups, Extra: extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group: authGroup, Resource: attrs.Resource, Verb: attrs.Verb, Name: attrs.Name, Namespace: attrs.Namespace,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", fmt.Errorf("error reviewing access: %v", err)
+	}
+	return review.Status.Allowed, utils.DefaultIfEmpty(review.Status.Reason, "no RBAC rule allows it"), nil
+}
+
+// authorizationPolicy authorizes operations with a list of rules. An operation is allowed if any rule
+// matches the caller and the operation.
+type authorizationPolicy struct {
+	Rules []policyRule `yaml:"rules"`
+}
+
+// policyRule allows its users and groups the given verbs on the given resources, namespaces and names.
+// Every value is a shell pattern, such as "*" or "tenant-a-*". Empty verbs, resources, namespaces or names
+// match any.
+type policyRule struct {
+	Users      []string `yaml:"users"`
+	Groups     []string `yaml:"groups"`
+	Verbs      []string `yaml:"verbs"`
+	Resources  []string `yaml:"resources"`
+	Namespaces []string `yaml:"namespaces"`
+	Names      []string `yaml:"names"`
+}
+
+// loadPolicy reads an authorization policy from a YAML file.
+func loadPolicy(file string) (*authorizationPolicy, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy: %v", err)
+	}
+	policy := &authorizationPolicy{}
+	if err := yaml.UnmarshalStrict(data, policy); err != nil {
+		return nil, fmt.Errorf("error parsing policy: %v", err)
+	}
+	for index, rule := range policy.Rules {
+		if len(rule.Users) == 0 && len(rule.Groups) == 0 {
+			return nil, fmt.Errorf("rule %d of the policy has no users nor groups", index)
+		}
+		for _, pattern := range append(append(append(append(append(rule.Users, rule.Groups...), rule.Verbs...), rule.Resources...), rule.Namespaces...), rule.Names...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule %d of the policy has an invalid pattern %q", index, pattern)
+			}
+		}
+	}
+	return policy, nil
+}
+
+func (policy *authorizationPolicy) authorize(ctx context.Context, user *identity, attrs attributes) (bool, string, error) {
+	for _, rule := range policy.Rules {
+		subject := matchAny(rule.Users, user.User, false)
+		for _, group := range user.Groups {
+			subject = subject || matchAny(rule.Groups, group, false)
+		}
+		if subject && matchAny(rule.Verbs, attrs.Verb, true) && matchAny(rule.Resources, attrs.Resource, true) &&
+			matchAny(rule.Namespaces, attrs.Namespace, true) && matchAny(rule.Names, attrs.Name, true) {
+			return true, "", nil
+		}
+	}
+	return false, "no policy rule allows it", nil
+}
+
+// matchAny tells whether the value matches any of the patterns. No patterns match anything if emptyMatches.
+func matchAny(patterns []string, value string, emptyMatches bool) bool {
+	if len(patterns) == 0 {
+		return emptyMatches
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
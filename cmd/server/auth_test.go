@@ -0,0 +1,172 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/registry"
+)
+
+// newTestAuth returns an auth whose API server knows the token "tenant-a-token" of user tenant-a, and
+// allows it everything in namespace tenant-a through SubjectAccessReviews.
+func newTestAuth(t *testing.T) (*auth, registry.Store) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == "tenant-a-token" {
+			review.Status = authenticationv1.TokenReviewStatus{Authenticated: true, User: authenticationv1.UserInfo{Username: "tenant-a"}}
+		}
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "tenant-a" && attrs.Group == authGroup && attrs.Namespace == "tenant-a"
+		return true, review, nil
+	})
+
+	store, err := registry.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &auth{authenticator: kubernetesAuthenticator{clientset: clientset}, authorizer: subjectAccessReviewer{clientset: clientset}, store: store, certNamespace: "l2sces-system"}, store
+}
+
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	a, store := newTestAuth(t)
+	ctx := context.Background()
+	if err := store.PutSlice(ctx, &l2sces.SliceRecord{Slice: &l2sces.Slice{Name: "slice-b", Provider: &l2sces.Provider{Name: "slice-b"}}, Namespace: "tenant-b"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.PutSlice(ctx, &l2sces.SliceRecord{Slice: &l2sces.Slice{Name: "slice-a", Provider: &l2sces.Provider{Name: "slice-a"}}, Namespace: "tenant-a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		req      interface{}
+		expected codes.Code
+	}{
+		{"no token", ctx, &l2sces.ListSlicesRequest{}, codes.Unauthenticated},
+		{"invalid token", withToken("other-token"), &l2sces.ListSlicesRequest{}, codes.Unauthenticated},
+		{"own namespace", withToken("tenant-a-token"), &l2sces.CreateNetworkRequest{Network: &l2sces.L2Network{Name: "net"}, Namespace: "tenant-a"}, codes.OK},
+		{"cluster in another namespace", withToken("tenant-a-token"), &l2sces.CreateNetworkRequest{Namespace: "tenant-a",
+			Network: &l2sces.L2Network{Name: "net", Clusters: []*l2sces.Cluster{{Name: "a"}, {Name: "b", Namespace: "tenant-b"}}}}, codes.PermissionDenied},
+		{"other namespace", withToken("tenant-a-token"), &l2sces.CreateSliceRequest{Slice: &l2sces.Slice{Name: "slice"}, Namespace: "tenant-b"}, codes.PermissionDenied},
+		{"registered slice", withToken("tenant-a-token"), &l2sces.DeleteOverlayRequest{OverlayName: "slice-a"}, codes.OK},
+		{"slice registered elsewhere", withToken("tenant-a-token"), &l2sces.DeleteOverlayRequest{OverlayName: "slice-b"}, codes.PermissionDenied},
+		{"slice name over provider name", withToken("tenant-a-token"), &l2sces.AddClusterRequest{SliceName: "slice-b", ProviderName: "slice-a"}, codes.PermissionDenied},
+		{"cluster registration", withToken("tenant-a-token"), &l2sces.RegisterClusterRequest{ClusterName: "cluster-a"}, codes.PermissionDenied},
+		{"unknown request", withToken("tenant-a-token"), &l2sces.Slice{}, codes.PermissionDenied},
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	for _, test := range tests {
+		_, err := a.unaryInterceptor(test.ctx, test.req, &grpc.UnaryServerInfo{FullMethod: "/test"}, handler)
+		if code := status.Code(err); code != test.expected {
+			t.Errorf("%s: expected %s, got %s (%v)", test.name, test.expected, code, err)
+		}
+	}
+}
+
+// TestListFiltered checks that list calls only return the records the caller may get.
+func TestListFiltered(t *testing.T) {
+	a, store := newTestAuth(t)
+	s := &server{Store: store, auth: a}
+	ctx := context.Background()
+	for _, namespace := range []string{"tenant-a", "tenant-b"} {
+		record := &l2sces.NetworkRecord{Network: &l2sces.L2Network{Name: "net-" + namespace}, Namespace: namespace}
+		if err := store.PutNetwork(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := a.unaryInterceptor(withToken("tenant-a-token"), &l2sces.ListNetworksRequest{}, &grpc.UnaryServerInfo{FullMethod: "/test"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.ListNetworks(ctx, req.(*l2sces.ListNetworksRequest))
+		})
+	if err != nil {
+		t.Fatalf("ListNetworks failed: %v", err)
+	}
+	networks := resp.(*l2sces.ListNetworksResponse).GetNetworks()
+	if len(networks) != 1 || networks[0].GetNetwork().GetName() != "net-tenant-a" {
+		t.Errorf("expected only net-tenant-a, got %v", networks)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	policyYAML := `rules:
+- groups: ["tenant-a"]
+  verbs: ["create", "delete"]
+  resources: ["slices"]
+  namespaces: ["tenant-a"]
+  names: ["tenant-a-*"]
+- users: ["admin"]
+`
+	if err := os.WriteFile(file, []byte(policyYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := loadPolicy(file)
+	if err != nil {
+		t.Fatalf("loadPolicy failed: %v", err)
+	}
+
+	tenant := &identity{User: "alice", Groups: []string{"tenant-a"}}
+	tests := []struct {
+		name     string
+		user     *identity
+		attrs    attributes
+		expected bool
+	}{
+		{"matching rule", tenant, attributes{Verb: "create", Resource: "slices", Name: "tenant-a-slice", Namespace: "tenant-a"}, true},
+		{"other name", tenant, attributes{Verb: "create", Resource: "slices", Name: "tenant-b-slice", Namespace: "tenant-a"}, false},
+		{"other verb", tenant, attributes{Verb: "update", Resource: "slices", Name: "tenant-a-slice", Namespace: "tenant-a"}, false},
+		{"other resource", tenant, attributes{Verb: "create", Resource: "networks", Name: "tenant-a-net", Namespace: "tenant-a"}, false},
+		{"admin", &identity{User: "admin"}, attributes{Verb: "delete", Resource: "networks", Name: "net", Namespace: "tenant-b"}, true},
+	}
+	for _, test := range tests {
+		if allowed, _, _ := policy.authorize(context.Background(), test.user, test.attrs); allowed != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, allowed)
+		}
+	}
+
+	if err := os.WriteFile(file, []byte("rules:\n- verbs: [\"get\"]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadPolicy(file); err == nil {
+		t.Error("expected a rule without users nor groups to be rejected")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/mdclient"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/registry"
+)
+
+const (
+	// errorDomain is the domain of the ErrorInfo attached to every error.
+	errorDomain = "l2sces.l2sm.io"
+	// retryDelay is suggested to clients when a cluster is unavailable.
+	retryDelay = 10 * time.Second
+)
+
+// requestError returns the gRPC error of a failed request. Its code tells the client whether retrying
+// makes sense, and the state every cluster was left in is attached as details, if the request got that far.
+func requestError(ctx context.Context, message string, err error, results []mdclient.ClusterResult) error {
+	code, cluster := errorCode(ctx, err, results)
+	st := status.New(code, fmt.Sprintf("%s: %v", message, err))
+
+	errorInfo := &errdetails.ErrorInfo{Reason: code.String(), Domain: errorDomain}
+	if cluster != "" {
+		errorInfo.Metadata = map[string]string{"cluster": cluster}
+	}
+	details := []protoadapt.MessageV1{errorInfo}
+	if code == codes.Unavailable {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retryDelay)})
+	}
+	for _, result := range clusterResults(results) {
+		details = append(details, result)
+	}
+
+	if withDetails, detailsErr := st.WithDetails(details...); detailsErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// invalidRequest returns the InvalidArgument error of a request that failed validation. Every violation
+// is listed in a BadRequest detail, under the path of its field.
+func invalidRequest(message string, errs field.ErrorList) error {
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("%s: %v", message, errs.ToAggregate()))
+
+	badRequest := &errdetails.BadRequest{}
+	for _, err := range errs {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field: err.Field, Description: err.ErrorBody(),
+		})
+	}
+	errorInfo := &errdetails.ErrorInfo{Reason: codes.InvalidArgument.String(), Domain: errorDomain}
+	if withDetails, detailsErr := st.WithDetails(errorInfo, badRequest); detailsErr == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// errorCode returns the code of a failed request, and the cluster whose failure decided it, if any. A
+// request canceled by the client or out of time gets codes.Canceled or codes.DeadlineExceeded. Otherwise
+// the code follows the error of the first failing cluster, or the error of the request.
+func errorCode(ctx context.Context, err error, results []mdclient.ClusterResult) (codes.Code, string) {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return codes.DeadlineExceeded, ""
+	case errors.Is(ctx.Err(), context.Canceled):
+		return codes.Canceled, ""
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			return classify(result.Err), result.Cluster
+		}
+	}
+	return classify(err), ""
+}
+
+// classify maps an error from the registry, the MDClient or a member cluster to a gRPC code.
+func classify(err error) codes.Code {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, mdclient.ErrInvalidArgument), errors.Is(err, operator.ErrInvalidKubeconfig), apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return codes.InvalidArgument
+	case errors.Is(err, mdclient.ErrNotFound), errors.Is(err, registry.ErrNotFound), apierrors.IsNotFound(err):
+		return codes.NotFound
+	case errors.Is(err, mdclient.ErrAlreadyExists), errors.Is(err, registry.ErrNamespaceConflict), apierrors.IsAlreadyExists(err), apierrors.IsConflict(err):
+		return codes.AlreadyExists
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return codes.PermissionDenied
+	case apierrors.IsServiceUnavailable(err), apierrors.IsTimeout(err), apierrors.IsServerTimeout(err),
+		apierrors.IsTooManyRequests(err), errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return codes.Unavailable
+	}
+	return codes.Unknown
+}
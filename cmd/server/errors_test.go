@@ -0,0 +1,119 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/mdclient"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/registry"
+)
+
+func TestClassify(t *testing.T) {
+	resource := schema.GroupResource{Group: "l2sm.l2sm.k8s.local", Resource: "l2networks"}
+	tests := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{"invalid request", fmt.Errorf("%w: bad cidr", mdclient.ErrInvalidArgument), codes.InvalidArgument},
+		{"not registered", fmt.Errorf("slice test: %w", registry.ErrNotFound), codes.NotFound},
+		{"missing object", fmt.Errorf("error deleting resource: %w", apierrors.NewNotFound(resource, "net")), codes.NotFound},
+		{"existing object", apierrors.NewAlreadyExists(resource, "net"), codes.AlreadyExists},
+		{"forbidden", fmt.Errorf("error applying: %w", apierrors.NewForbidden(resource, "net", errors.New("denied"))), codes.PermissionDenied},
+		{"unreachable cluster", &url.Error{Op: "Get", URL: "https://cluster:6443", Err: errors.New("connection refused")}, codes.Unavailable},
+		{"cluster timeout", fmt.Errorf("cluster a: %w", context.DeadlineExceeded), codes.Unavailable},
+		{"unknown", errors.New("boom"), codes.Unknown},
+	}
+
+	for _, test := range tests {
+		if code := classify(test.err); code != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, code)
+		}
+	}
+}
+
+// TestRequestError checks that the code follows the first failing cluster and that every cluster is attached.
+func TestRequestError(t *testing.T) {
+	results := []mdclient.ClusterResult{
+		{Cluster: "a", State: mdclient.ClusterRolledBack},
+		{Cluster: "b", State: mdclient.ClusterFailed, Err: &url.Error{Op: "Get", URL: "https://b:6443", Err: errors.New("connection refused")}},
+	}
+	err := requestError(context.Background(), "could not create network", errors.New("cluster b failed"), results)
+
+	st := status.Convert(err)
+	if st.Code() != codes.Unavailable {
+		t.Errorf("expected Unavailable, got %s", st.Code())
+	}
+
+	var clusters []string
+	var errorInfo *errdetails.ErrorInfo
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *l2sces.ClusterResult:
+			clusters = append(clusters, detail.GetCluster()+"="+detail.GetState())
+		case *errdetails.ErrorInfo:
+			errorInfo = detail
+		}
+	}
+	if len(clusters) != 2 || clusters[0] != "a=RolledBack" || clusters[1] != "b=Failed" {
+		t.Errorf("expected the state of both clusters, got %v", clusters)
+	}
+	if errorInfo == nil || errorInfo.GetMetadata()["cluster"] != "b" {
+		t.Errorf("expected the error info to point to cluster b, got %v", errorInfo)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if code := status.Code(requestError(ctx, "could not create network", context.Canceled, nil)); code != codes.Canceled {
+		t.Errorf("expected Canceled, got %s", code)
+	}
+}
+
+// TestInvalidRequest checks that every violation is listed with its field path.
+func TestInvalidRequest(t *testing.T) {
+	errs := field.ErrorList{
+		field.Required(field.NewPath("network", "provider"), ""),
+		field.Invalid(field.NewPath("network", "pod_cidr"), "10.1.0.0", "must be a valid CIDR"),
+	}
+	st := status.Convert(invalidRequest("invalid network", errs))
+	if st.Code() != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %s", st.Code())
+	}
+
+	var violations []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				violations = append(violations, violation.GetField())
+			}
+		}
+	}
+	if len(violations) != 2 || violations[0] != "network.provider" || violations[1] != "network.pod_cidr" {
+		t.Errorf("expected violations of network.provider and network.pod_cidr, got %v", violations)
+	}
+}
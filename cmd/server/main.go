@@ -17,17 +17,26 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net"
 	"path/filepath"
 
 	"google.golang.org/grpc"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 
 	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/internal/env"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/grpctls"
 	"github.com/Networks-it-uc3m/l2sc-es/pkg/mdclient"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/registry"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologygenerator"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
 )
 
 func main() {
@@ -37,9 +46,6 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	// Create a new gRPC server
-	grpcServer := grpc.NewServer()
-
 	config, err := rest.InClusterConfig()
 	if err != nil {
 		// If in-cluster config is not available, try the local kubeconfig
@@ -48,13 +54,46 @@ func main() {
 			log.Fatalf("could not create config from either in-cluster or kubeconfig: %v", err)
 		}
 	}
-	restcli, err := mdclient.NewClient(mdclient.RestType, config)
+
+	restcli, err := mdclient.NewClient(mdclient.ClientType(env.GetClientType()), config, env.GetKubeconfigDir())
 	if err != nil {
 		log.Fatalf("Failed to create multi domain client: %v", err)
 	}
 
+	store, err := newStore(config)
+	if err != nil {
+		log.Fatalf("Failed to create registry: %v", err)
+	}
+
+	certs, err := operator.NewCertStore(config, certNamespace())
+	if err != nil {
+		log.Fatalf("Failed to create certificate store: %v", err)
+	}
+
+	var linkCosts []*l2sces.LinkCost
+	if env.GetLinkCostsFile() != "" {
+		linkCosts, err = topologygenerator.LoadLinkCosts(env.GetLinkCostsFile())
+		if err != nil {
+			log.Fatalf("Failed to load link costs: %v", err)
+		}
+	}
+
+	// Create a new gRPC server
+	serverOptions, err := tlsOptions(config)
+	if err != nil {
+		log.Fatalf("Failed to configure TLS: %v", err)
+	}
+	auth, err := newAuth(config, store)
+	if err != nil {
+		log.Fatalf("Failed to configure authorization: %v", err)
+	}
+	if auth != nil {
+		serverOptions = append(serverOptions, grpc.UnaryInterceptor(auth.unaryInterceptor))
+	}
+	grpcServer := grpc.NewServer(serverOptions...)
+
 	// Register the server with the gRPC server
-	l2sces.RegisterL2SMMultiDomainServiceServer(grpcServer, &server{MDClient: restcli})
+	l2sces.RegisterL2SMMultiDomainServiceServer(grpcServer, &server{MDClient: restcli, Store: store, auth: auth, Certs: certs, LinkCosts: linkCosts})
 
 	log.Printf("Server listening at %v", lis.Addr())
 
@@ -63,3 +102,84 @@ func main() {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
+
+// certNamespace returns the namespace clusters registered through RegisterCluster are stored in:
+// CERT_NAMESPACE, or the one of the registry if it searches every namespace.
+func certNamespace() string {
+	return utils.DefaultIfEmpty(env.GetCertNamespace(), env.GetRegistryNamespace())
+}
+
+// newStore creates the registry selected by the REGISTRY_STORE environment variable.
+func newStore(config *rest.Config) (registry.Store, error) {
+	switch registry.StoreType(env.GetRegistryStore()) {
+	case registry.KubernetesType:
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		return registry.NewKubernetesStore(clientset, env.GetRegistryNamespace()), nil
+	case registry.FileType:
+		return registry.NewFileStore(env.GetRegistryPath())
+	default:
+		return nil, fmt.Errorf("unsupported registry store %s", env.GetRegistryStore())
+	}
+}
+
+// tlsOptions returns the options that secure the gRPC server with the certificate of TLS_SECRET or
+// TLS_CERT_FILE, reloaded when rotated. Without either, requests and their bearer tokens are sent in
+// plain text, so this is only meant for local development.
+func tlsOptions(config *rest.Config) ([]grpc.ServerOption, error) {
+	var source grpctls.Source
+	switch {
+	case env.GetTLSSecret() != "":
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		source = grpctls.SecretSource{Clientset: clientset, Namespace: env.GetTLSSecretNamespace(),
+			Name: env.GetTLSSecret(), ClientCAName: env.GetTLSClientCASecret()}
+	case env.GetTLSCertFile() != "":
+		source = grpctls.FileSource{CertFile: env.GetTLSCertFile(), KeyFile: env.GetTLSKeyFile(), ClientCAFile: env.GetTLSClientCAFile()}
+	default:
+		log.Printf("TLS is disabled: set TLS_SECRET or TLS_CERT_FILE to encrypt requests")
+		return nil, nil
+	}
+
+	interval, err := env.GetTLSReloadInterval()
+	if err != nil {
+		return nil, err
+	}
+	reloader, err := grpctls.NewReloader(context.Background(), source, interval)
+	if err != nil {
+		return nil, err
+	}
+	go reloader.Run(context.Background())
+	return []grpc.ServerOption{grpc.Creds(reloader.Credentials())}, nil
+}
+
+// newAuth creates the authorization selected by the AUTHORIZATION environment variable. Without it, any
+// caller reaching the server may change any namespace of the member clusters, so it is nil only for
+// local development.
+func newAuth(config *rest.Config, store registry.Store) (*auth, error) {
+	if env.GetAuthorization() == "" {
+		log.Printf("Authorization is disabled: set AUTHORIZATION to authenticate and authorize callers")
+		return nil, nil
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &auth{authenticator: kubernetesAuthenticator{clientset: clientset}, store: store, certNamespace: certNamespace()}
+	switch AuthorizationType(env.GetAuthorization()) {
+	case KubernetesAuthorization:
+		a.authorizer = subjectAccessReviewer{clientset: clientset}
+	case PolicyAuthorization:
+		if a.authorizer, err = loadPolicy(env.GetAuthorizationPolicyFile()); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported authorization %s", env.GetAuthorization())
+	}
+	return a, nil
+}
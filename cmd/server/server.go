@@ -16,54 +16,508 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
 	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
 	"github.com/Networks-it-uc3m/l2sc-es/pkg/mdclient"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/registry"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologyanalysis"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologygenerator"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/validation"
 )
 
 // server implements the L2SMMultiDomainServiceServer interface
 type server struct {
 	l2sces.UnimplementedL2SMMultiDomainServiceServer
 	mdclient.MDClient
+	// Store records the created networks and slices, so that they can be deleted or updated by name.
+	Store registry.Store
+	// auth authorizes the records returned by list calls. Nil if authorization is disabled.
+	auth *auth
+	// Certs stores the credentials of the member clusters registered with RegisterCluster.
+	Certs *operator.CertStore
+	// LinkCosts are the costs between member clusters used by slices with a topology, unless the slice
+	// gives its own.
+	LinkCosts []*l2sces.LinkCost
 }
 
 // CreateNetwork calls a method from mdclient to create a network
 func (s *server) CreateNetwork(ctx context.Context, req *l2sces.CreateNetworkRequest) (*l2sces.CreateNetworkResponse, error) {
-	err := s.MDClient.CreateNetwork(req.GetNetwork(), req.GetNamespace())
-	// Call the mdclient.CreateNetwork method (to be implemented later)
+	errs := validation.ValidateL2Network(req.GetNetwork(), field.NewPath("network"))
+	if errs = append(errs, validation.ValidateNamespace(req.GetNamespace(), field.NewPath("namespace"))...); len(errs) > 0 {
+		return nil, invalidRequest("invalid network", errs)
+	}
+	if err := registry.CheckNetworkNamespace(ctx, s.Store, req.GetNetwork().GetName(), req.GetNamespace()); err != nil {
+		return nil, requestError(ctx, "could not create network", err, nil)
+	}
+	results, err := s.MDClient.CreateNetwork(ctx, req.GetNetwork(), req.GetNamespace())
+	if err != nil {
+		return nil, requestError(ctx, "could not create network", err, results)
+	}
+	err = s.Store.PutNetwork(context.WithoutCancel(ctx), &l2sces.NetworkRecord{Network: allocatedNetwork(req.GetNetwork()), Namespace: req.GetNamespace()})
 	if err != nil {
-		return nil, fmt.Errorf("could not create network: %v", err)
+		return nil, requestError(ctx, "network created but not registered", err, nil)
 	}
-	return &l2sces.CreateNetworkResponse{Message: "Network created successfully", Patches: l2sminterface.GetWorkloadPatchInstructions(req.GetNetwork().GetName())}, nil
+	return &l2sces.CreateNetworkResponse{Message: "Network created successfully", Patches: l2sminterface.GetWorkloadPatchInstructions(req.GetNetwork().GetName()),
+		Clusters: clusterResults(results)}, nil
 }
 
-// DeleteNetwork calls a method from mdclient to delete a network
+// DeleteNetwork calls a method from mdclient to delete a network. If the network has no clusters, it is
+// looked up in the registry by name.
 func (s *server) DeleteNetwork(ctx context.Context, req *l2sces.DeleteNetworkRequest) (*l2sces.DeleteNetworkResponse, error) {
-	// Call the mdclient.DeleteNetwork method (to be implemented later)
-	err := s.MDClient.DeleteNetwork(req.GetNetwork(), req.GetNamespace())
+	errs := validation.ValidateL2NetworkName(req.GetNetwork(), field.NewPath("network"))
+	if errs = append(errs, validation.ValidateNamespace(req.GetNamespace(), field.NewPath("namespace"))...); len(errs) > 0 {
+		return nil, invalidRequest("invalid network", errs)
+	}
+	network, namespace := req.GetNetwork(), req.GetNamespace()
+	if len(network.GetClusters()) == 0 {
+		record, err := s.Store.GetNetwork(ctx, network.GetName())
+		if err != nil {
+			return nil, requestError(ctx, "could not delete network", err, nil)
+		}
+		network, namespace = record.GetNetwork(), utils.DefaultIfEmpty(namespace, record.GetNamespace())
+	}
+	if err := registry.CheckNetworkNamespace(ctx, s.Store, network.GetName(), namespace); err != nil {
+		return nil, requestError(ctx, "could not delete network", err, nil)
+	}
+
+	results, err := s.MDClient.DeleteNetwork(ctx, network, namespace)
 	if err != nil {
-		return nil, fmt.Errorf("could not delete network: %v", err)
+		return nil, requestError(ctx, "could not delete network", err, results)
 	}
-	return &l2sces.DeleteNetworkResponse{Message: "Network deleted successfully"}, nil
+	if err := s.Store.DeleteNetwork(context.WithoutCancel(ctx), network.GetName()); err != nil {
+		return nil, requestError(ctx, "network deleted but not unregistered", err, nil)
+	}
+	return &l2sces.DeleteNetworkResponse{Message: "Network deleted successfully", Clusters: clusterResults(results)}, nil
 }
 
+// CreateSlice creates a slice, and returns the links it got with their total cost and single points of failure.
 func (s *server) CreateSlice(ctx context.Context, req *l2sces.CreateSliceRequest) (*l2sces.CreateSliceResponse, error) {
-	err := s.MDClient.CreateSlice(req.GetSlice(), req.GetNamespace())
+	slice := s.withLinkCosts(req.GetSlice(), clusterNames(req.GetSlice().GetClusters()))
+	errs := validation.ValidateSlice(slice, field.NewPath("slice"))
+	if errs = append(errs, validation.ValidateNamespace(req.GetNamespace(), field.NewPath("namespace"))...); len(errs) > 0 {
+		return nil, invalidRequest("invalid slice", errs)
+	}
+	if err := registry.CheckSliceNamespace(ctx, s.Store, registry.SliceName(slice), req.GetNamespace()); err != nil {
+		return nil, requestError(ctx, "could not create slice", err, nil)
+	}
+	results, err := s.MDClient.CreateSlice(ctx, slice, req.GetNamespace())
 
 	if err != nil {
-		return nil, fmt.Errorf("could now create slice: %v", err)
+		return nil, requestError(ctx, "could not create slice", err, results)
+	}
+	if err := s.putSlice(ctx, req.GetSlice(), req.GetNamespace()); err != nil {
+		return nil, requestError(ctx, "slice created but not registered", err, nil)
 	}
 
-	return &l2sces.CreateSliceResponse{Message: "Slice created succesfully"}, nil
+	// The slice was validated, so its topology can generate its links
+	links, _ := topologygenerator.SliceLinks(slice)
+	return &l2sces.CreateSliceResponse{Message: "Slice created succesfully", Clusters: clusterResults(results),
+		Links: links, Cost: topologygenerator.Cost(links, slice.GetTopology().GetCosts()), Warnings: topologyWarnings(slice.GetClusters(), links)}, nil
 }
 
+// DeleteSlice deletes a slice. If the slice has no clusters, it is looked up in the registry by name.
 func (s *server) DeleteSlice(ctx context.Context, req *l2sces.DeleteSliceRequest) (*l2sces.DeleteSliceResponse, error) {
-	// Call the mdclient.DeleteNetwork method (to be implemented later)
-	err := s.MDClient.DeleteSlice(req.GetSlice(), req.GetNamespace())
+	errs := validation.ValidateSliceReference(req.GetSlice(), "", field.NewPath("slice"), field.NewPath("slice", "name"))
+	if errs = append(errs, validation.ValidateNamespace(req.GetNamespace(), field.NewPath("namespace"))...); len(errs) > 0 {
+		return nil, invalidRequest("invalid slice", errs)
+	}
+	slice, namespace, err := s.resolveSlice(ctx, req.GetSlice(), "", req.GetNamespace())
+	if err != nil {
+		return nil, requestError(ctx, "could not delete slice", err, nil)
+	}
+	if err := registry.CheckSliceNamespace(ctx, s.Store, registry.SliceName(slice), namespace); err != nil {
+		return nil, requestError(ctx, "could not delete slice", err, nil)
+	}
+	results, err := s.MDClient.DeleteSlice(ctx, slice, namespace)
+	if err != nil {
+		return nil, requestError(ctx, "could not delete slice", err, results)
+	}
+	if err := s.Store.DeleteSlice(context.WithoutCancel(ctx), registry.SliceName(slice)); err != nil {
+		return nil, requestError(ctx, "slice deleted but not unregistered", err, nil)
+	}
+	return &l2sces.DeleteSliceResponse{Message: "Slice deleted successfully", Clusters: clusterResults(results)}, nil
+}
+
+func (s *server) CreateOverlay(ctx context.Context, req *l2sces.CreateOverlayRequest) (*l2sces.CreateOverlayResponse, error) {
+	errs := validation.ValidateOverlay(req.GetOverlay(), req.GetClusters(), field.NewPath("overlay"), field.NewPath("clusters"))
+	if errs = append(errs, validation.ValidateNamespace(req.GetNamespace(), field.NewPath("namespace"))...); len(errs) > 0 {
+		return nil, invalidRequest("invalid overlay", errs)
+	}
+	if err := registry.CheckSliceNamespace(ctx, s.Store, req.GetOverlay().GetProvider().GetName(), req.GetNamespace()); err != nil {
+		return nil, requestError(ctx, "could not create overlay", err, nil)
+	}
+	results, err := s.MDClient.CreateOverlay(ctx, req.GetOverlay(), req.GetClusters(), req.GetNamespace())
+	if err != nil {
+		return nil, requestError(ctx, "could not create overlay", err, results)
+	}
+	slice := &l2sces.Slice{Provider: req.GetOverlay().GetProvider(), Clusters: req.GetClusters(), Links: req.GetOverlay().GetLinks()}
+	if err := s.putSlice(ctx, slice, req.GetNamespace()); err != nil {
+		return nil, requestError(ctx, "overlay created but not registered", err, nil)
+	}
+	links, _ := topologygenerator.SliceLinks(slice)
+	return &l2sces.CreateOverlayResponse{Message: "Overlay created successfully", Clusters: clusterResults(results),
+		Warnings: topologyWarnings(slice.GetClusters(), links)}, nil
+}
+
+func (s *server) AddCluster(ctx context.Context, req *l2sces.AddClusterRequest) (*l2sces.AddClusterResponse, error) {
+	errs := validation.ValidateSliceReference(req.GetSlice(), req.GetSliceName(), field.NewPath("slice"), field.NewPath("slice_name"))
+	if errs = append(errs, validation.ValidateNamespace(req.GetNamespace(), field.NewPath("namespace"))...); len(errs) > 0 {
+		return nil, invalidRequest("invalid request", errs)
+	}
+	slice, namespace, err := s.resolveSlice(ctx, req.GetSlice(), req.GetSliceName(), req.GetNamespace())
+	if err != nil {
+		return nil, requestError(ctx, "could not add cluster", err, nil)
+	}
+	registered := sliceWithProvider(slice, req.GetProviderName(), req.GetProviderDomain())
+	slice = s.withLinkCosts(registered, append(clusterNames(registered.GetClusters()), req.GetCluster().GetName()))
+	if errs := validation.ValidateClusterJoin(slice, req.GetCluster(), req.GetLinks(), field.NewPath("cluster"), field.NewPath("links")); len(errs) > 0 {
+		return nil, invalidRequest("invalid cluster", errs)
+	}
+	if err := registry.CheckSliceNamespace(ctx, s.Store, registry.SliceName(slice), namespace); err != nil {
+		return nil, requestError(ctx, "could not add cluster", err, nil)
+	}
+	err = s.MDClient.AddCluster(ctx, slice, req.GetCluster(), req.GetLinks(), namespace)
+	if err != nil {
+		return nil, requestError(ctx, "could not add cluster", err, nil)
+	}
+	if err := s.putSlice(ctx, withCostsOf(joinCluster(slice, req.GetCluster(), req.GetLinks()), registered), namespace); err != nil {
+		return nil, requestError(ctx, "cluster added but not registered", err, nil)
+	}
+	return &l2sces.AddClusterResponse{Message: "Cluster added successfully"}, nil
+}
+
+func (s *server) RemoveCluster(ctx context.Context, req *l2sces.RemoveClusterRequest) (*l2sces.RemoveClusterResponse, error) {
+	errs := validation.ValidateSliceReference(req.GetSlice(), req.GetOverlayName(), field.NewPath("slice"), field.NewPath("overlay_name"))
+	if req.GetClusterName() == "" {
+		errs = append(errs, field.Required(field.NewPath("cluster_name"), ""))
+	}
+	if errs = append(errs, validation.ValidateNamespace(req.GetNamespace(), field.NewPath("namespace"))...); len(errs) > 0 {
+		return nil, invalidRequest("invalid request", errs)
+	}
+	slice, namespace, err := s.resolveSlice(ctx, req.GetSlice(), req.GetOverlayName(), req.GetNamespace())
+	if err != nil {
+		return nil, requestError(ctx, "could not remove cluster", err, nil)
+	}
+	registered := sliceWithProvider(slice, req.GetProviderName(), req.GetProviderDomain())
+	slice = s.withLinkCosts(registered, clusterNames(registered.GetClusters()))
+	left := leaveCluster(slice, req.GetClusterName())
+	// Explicit links are not generated again, even if none is left
+	links := left.GetLinks()
+	if len(slice.GetLinks()) == 0 {
+		if links, err = topologygenerator.SliceLinks(left); err != nil {
+			return nil, invalidRequest("the slice can't lose the cluster", field.ErrorList{field.Invalid(field.NewPath("cluster_name"), req.GetClusterName(), err.Error())})
+		}
+	}
+	if errs := validation.ValidateConnected(clusterNames(left.GetClusters()), links, field.NewPath("cluster_name")); len(errs) > 0 {
+		return nil, invalidRequest("the slice can't lose the cluster", errs)
+	}
+	if err := registry.CheckSliceNamespace(ctx, s.Store, registry.SliceName(slice), namespace); err != nil {
+		return nil, requestError(ctx, "could not remove cluster", err, nil)
+	}
+	err = s.MDClient.RemoveCluster(ctx, slice, req.GetClusterName(), namespace)
+	if err != nil {
+		return nil, requestError(ctx, "could not remove cluster", err, nil)
+	}
+	if err := s.putSlice(ctx, withCostsOf(left, registered), namespace); err != nil {
+		return nil, requestError(ctx, "cluster removed but not unregistered", err, nil)
+	}
+	return &l2sces.RemoveClusterResponse{Message: "Cluster removed successfully"}, nil
+}
+
+func (s *server) DeleteOverlay(ctx context.Context, req *l2sces.DeleteOverlayRequest) (*l2sces.DeleteOverlayResponse, error) {
+	errs := validation.ValidateSliceReference(req.GetSlice(), req.GetOverlayName(), field.NewPath("slice"), field.NewPath("overlay_name"))
+	if errs = append(errs, validation.ValidateNamespace(req.GetNamespace(), field.NewPath("namespace"))...); len(errs) > 0 {
+		return nil, invalidRequest("invalid overlay", errs)
+	}
+	slice, namespace, err := s.resolveSlice(ctx, req.GetSlice(), req.GetOverlayName(), req.GetNamespace())
+	if err != nil {
+		return nil, requestError(ctx, "could not delete overlay", err, nil)
+	}
+	slice = sliceWithProvider(slice, req.GetProviderName(), req.GetProviderDomain())
+	if err := registry.CheckSliceNamespace(ctx, s.Store, registry.SliceName(slice), namespace); err != nil {
+		return nil, requestError(ctx, "could not delete overlay", err, nil)
+	}
+	results, err := s.MDClient.DeleteOverlay(ctx, slice, namespace)
+	if err != nil {
+		return nil, requestError(ctx, "could not delete overlay", err, results)
+	}
+	if err := s.Store.DeleteSlice(context.WithoutCancel(ctx), registry.SliceName(slice)); err != nil {
+		return nil, requestError(ctx, "overlay deleted but not unregistered", err, nil)
+	}
+	return &l2sces.DeleteOverlayResponse{Message: "Overlay deleted successfully", Clusters: clusterResults(results)}, nil
+}
+
+func (s *server) GetNetwork(ctx context.Context, req *l2sces.GetNetworkRequest) (*l2sces.GetNetworkResponse, error) {
+	record, err := s.Store.GetNetwork(ctx, req.GetName())
+	if err != nil {
+		return nil, requestError(ctx, "could not get network", err, nil)
+	}
+	return &l2sces.GetNetworkResponse{Network: redactNetwork(record)}, nil
+}
+
+func (s *server) ListNetworks(ctx context.Context, req *l2sces.ListNetworksRequest) (*l2sces.ListNetworksResponse, error) {
+	records, err := s.Store.ListNetworks(ctx)
+	if err != nil {
+		return nil, requestError(ctx, "could not list networks", err, nil)
+	}
+	allowed := make([]*l2sces.NetworkRecord, 0, len(records))
+	for _, record := range records {
+		if s.auth.allowed(ctx, networkAttributes("get", record.GetNetwork(), record.GetNamespace())) {
+			allowed = append(allowed, redactNetwork(record))
+		}
+	}
+	return &l2sces.ListNetworksResponse{Networks: allowed}, nil
+}
+
+func (s *server) GetSlice(ctx context.Context, req *l2sces.GetSliceRequest) (*l2sces.GetSliceResponse, error) {
+	record, err := s.Store.GetSlice(ctx, req.GetName())
 	if err != nil {
-		return nil, fmt.Errorf("could not delete network: %v", err)
+		return nil, requestError(ctx, "could not get slice", err, nil)
+	}
+	return &l2sces.GetSliceResponse{Slice: redactSlice(record)}, nil
+}
+
+// GetSliceTopology renders the clusters and links of a registered slice, with their live status if asked
+// for and the client can read it.
+func (s *server) GetSliceTopology(ctx context.Context, req *l2sces.GetSliceTopologyRequest) (*l2sces.GetSliceTopologyResponse, error) {
+	format := utils.DefaultIfEmpty(req.GetFormat(), topologygenerator.JSONFormat)
+	if !slices.Contains(topologygenerator.Formats, format) {
+		return nil, invalidRequest("invalid request", field.ErrorList{field.NotSupported(field.NewPath("format"), format, topologygenerator.Formats)})
+	}
+	record, err := s.Store.GetSlice(ctx, req.GetName())
+	if err != nil {
+		return nil, requestError(ctx, "could not get slice", err, nil)
+	}
+	slice := s.withLinkCosts(record.GetSlice(), clusterNames(record.GetSlice().GetClusters()))
+	graph, err := topologygenerator.NewGraph(registry.SliceName(slice), slice)
+	if err != nil {
+		return nil, requestError(ctx, "could not get slice topology", err, nil)
+	}
+	if reader, ok := s.MDClient.(mdclient.LinkStatusReader); ok && req.GetLive() {
+		graph.SetLinkStatus(reader.ConnectedNeighbors(ctx, slice, record.GetNamespace()))
+	}
+	topology, err := topologygenerator.Render(graph, format)
+	if err != nil {
+		return nil, requestError(ctx, "could not render slice topology", err, nil)
+	}
+	return &l2sces.GetSliceTopologyResponse{Topology: string(topology)}, nil
+}
+
+func (s *server) ListSlices(ctx context.Context, req *l2sces.ListSlicesRequest) (*l2sces.ListSlicesResponse, error) {
+	records, err := s.Store.ListSlices(ctx)
+	if err != nil {
+		return nil, requestError(ctx, "could not list slices", err, nil)
+	}
+	allowed := make([]*l2sces.SliceRecord, 0, len(records))
+	for _, record := range records {
+		if s.auth.allowed(ctx, []attributes{sliceAttributes("get", registry.SliceName(record.GetSlice()), record.GetNamespace())}) {
+			allowed = append(allowed, redactSlice(record))
+		}
+	}
+	return &l2sces.ListSlicesResponse{Slices: allowed}, nil
+}
+
+// putSlice registers a slice, naming it after its provider if it has no name. The clusters were already
+// changed, so the registry is updated even if the request was canceled meanwhile.
+func (s *server) putSlice(ctx context.Context, slice *l2sces.Slice, namespace string) error {
+	if slice.GetName() == "" {
+		slice = proto.Clone(slice).(*l2sces.Slice)
+		slice.Name = registry.SliceName(slice)
+	}
+	return s.Store.PutSlice(context.WithoutCancel(ctx), &l2sces.SliceRecord{Slice: slice, Namespace: namespace})
+}
+
+// RegisterCluster registers a member cluster from a kubeconfig that reaches it, so that requests can
+// reference the cluster by name only.
+func (s *server) RegisterCluster(ctx context.Context, req *l2sces.RegisterClusterRequest) (*l2sces.RegisterClusterResponse, error) {
+	if errs := validation.ValidateClusterRegistration(req); len(errs) > 0 {
+		return nil, invalidRequest("invalid request", errs)
+	}
+	registration, err := operator.RegisterCluster(ctx, s.Certs, req.GetClusterName(), req.GetKubeconfig(), operator.RegistrationOptions{
+		Context:                 req.GetContext(),
+		CreateServiceAccount:    req.GetCreateServiceAccount(),
+		ServiceAccountNamespace: req.GetServiceAccountNamespace(),
+		ServiceAccountName:      req.GetServiceAccountName(),
+		TokenDuration:           time.Duration(req.GetTokenExpirationSeconds()) * time.Second,
+		Untrusted:               true,
+	})
+	if err != nil {
+		return nil, requestError(ctx, "could not register cluster", err, nil)
+	}
+
+	response := &l2sces.RegisterClusterResponse{Message: "Cluster registered successfully", Server: registration.Server,
+		ServiceAccount: registration.ServiceAccount}
+	if !registration.Created {
+		response.Message = "Cluster registration updated successfully"
+	}
+	if !registration.TokenExpiry.IsZero() {
+		response.TokenExpiry = registration.TokenExpiry.Format(time.RFC3339)
+	}
+	return response, nil
+}
+
+// resolveSlice returns the slice given in a request, or the registered one when the request only names it.
+// The namespace of the request takes precedence over the registered one.
+func (s *server) resolveSlice(ctx context.Context, slice *l2sces.Slice, name string, namespace string) (*l2sces.Slice, string, error) {
+	if len(slice.GetClusters()) > 0 {
+		return slice, namespace, nil
+	}
+	name = utils.DefaultIfEmpty(name, registry.SliceName(slice))
+	record, err := s.Store.GetSlice(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	return record.GetSlice(), utils.DefaultIfEmpty(namespace, record.GetNamespace()), nil
+}
+
+// clusterResults converts the results of a multi-cluster apply to their gRPC form.
+func clusterResults(results []mdclient.ClusterResult) []*l2sces.ClusterResult {
+	converted := make([]*l2sces.ClusterResult, len(results))
+	for index, result := range results {
+		converted[index] = &l2sces.ClusterResult{Cluster: result.Cluster, State: string(result.State), Objects: result.Objects, Status: result.Status}
+		if result.Err != nil {
+			converted[index].Error = result.Err.Error()
+		}
+	}
+	return converted
+}
+
+// sliceWithProvider fills the provider of the slice from the provider fields of the overlay requests
+// when the slice doesn't carry one.
+func sliceWithProvider(slice *l2sces.Slice, providerName string, providerDomain string) *l2sces.Slice {
+	if slice == nil {
+		slice = &l2sces.Slice{}
+	}
+	if slice.GetProvider() == nil {
+		slice.Provider = &l2sces.Provider{Name: providerName, Domain: providerDomain}
+	}
+	return slice
+}
+
+// topologyWarnings logs and returns the single points of failure of the links between the clusters.
+func topologyWarnings(clusters []*l2sces.Cluster, links []*l2sces.Link) []string {
+	warnings := topologyanalysis.Analyze(clusterNames(clusters), links).Warnings()
+	for _, warning := range warnings {
+		log.Printf("Warning: %s", warning)
+	}
+	return warnings
+}
+
+// withLinkCosts returns the slice with the known costs between the given clusters added to its topology,
+// if it has one. The costs given by the slice take precedence. The known costs are only merged to compute
+// the links, and never registered, so that a change of LINK_COSTS_FILE applies to the existing slices.
+func (s *server) withLinkCosts(slice *l2sces.Slice, clusters []string) *l2sces.Slice {
+	if slice.GetTopology() == nil || len(s.LinkCosts) == 0 {
+		return slice
+	}
+	known := []*l2sces.LinkCost{}
+	for _, cost := range s.LinkCosts {
+		if slices.Contains(clusters, cost.GetEndpointA()) && slices.Contains(clusters, cost.GetEndpointB()) {
+			known = append(known, cost)
+		}
+	}
+	withCosts := proto.Clone(slice).(*l2sces.Slice)
+	withCosts.Topology.Costs = topologygenerator.MergeCosts(known, slice.GetTopology().GetCosts())
+	return withCosts
+}
+
+// withCostsOf returns the slice with the link costs of the topology of the original one, as requested,
+// instead of the ones merged by withLinkCosts.
+func withCostsOf(slice *l2sces.Slice, original *l2sces.Slice) *l2sces.Slice {
+	if slice.GetTopology() == nil {
+		return slice
+	}
+	restored := proto.Clone(slice).(*l2sces.Slice)
+	restored.Topology.Costs = original.GetTopology().GetCosts()
+	return restored
+}
+
+// joinCluster returns the slice after the cluster joined it through the given links. A slice without links
+// keeps its topology, a full mesh by default, unless the cluster joins it through specific links: then the
+// links of the topology are kept instead.
+func joinCluster(slice *l2sces.Slice, cluster *l2sces.Cluster, links []*l2sces.Link) *l2sces.Slice {
+	joined := proto.Clone(slice).(*l2sces.Slice)
+	if len(slice.GetLinks()) > 0 || len(links) > 0 {
+		if len(joined.Links) == 0 {
+			// The client already generated them to add the cluster, so the topology is valid
+			joined.Links, _ = topologygenerator.SliceLinks(slice)
+			joined.Topology = nil
+		}
+		if len(links) == 0 {
+			for _, sliceCluster := range slice.GetClusters() {
+				links = append(links, &l2sces.Link{EndpointA: sliceCluster.GetName(), EndpointB: cluster.GetName()})
+			}
+		}
+		joined.Links = append(joined.Links, links...)
+	}
+	joined.Clusters = append(joined.Clusters, cluster)
+	return joined
+}
+
+// leaveCluster returns the slice without the given cluster and its links.
+func leaveCluster(slice *l2sces.Slice, clusterName string) *l2sces.Slice {
+	left := proto.Clone(slice).(*l2sces.Slice)
+	left.Clusters = nil
+	for _, cluster := range slice.GetClusters() {
+		if cluster.GetName() != clusterName {
+			left.Clusters = append(left.Clusters, cluster)
+		}
+	}
+	left.Links = nil
+	for _, link := range slice.GetLinks() {
+		if link.GetEndpointA() != clusterName && link.GetEndpointB() != clusterName {
+			left.Links = append(left.Links, link)
+		}
+	}
+	return left
+}
+
+func clusterNames(clusters []*l2sces.Cluster) []string {
+	names := make([]string, len(clusters))
+	for index, cluster := range clusters {
+		names[index] = cluster.GetName()
+	}
+	return names
+}
+
+// allocatedNetwork returns a copy of the network with the pod address range each cluster got. The network
+// was created, so its pod CIDR could be split.
+func allocatedNetwork(network *l2sces.L2Network) *l2sces.L2Network {
+	allocated := proto.Clone(network).(*l2sces.L2Network)
+	pools, _ := mdclient.PodAddressPools(network)
+	for index, pool := range pools {
+		allocated.Clusters[index].PodAddressPool = pool
+	}
+	return allocated
+}
+
+// redactNetwork returns a copy of the record without the bearer tokens of its clusters.
+func redactNetwork(record *l2sces.NetworkRecord) *l2sces.NetworkRecord {
+	redacted := proto.Clone(record).(*l2sces.NetworkRecord)
+	redactClusters(redacted.GetNetwork().GetClusters())
+	return redacted
+}
+
+// redactSlice returns a copy of the record without the bearer tokens of its clusters.
+func redactSlice(record *l2sces.SliceRecord) *l2sces.SliceRecord {
+	redacted := proto.Clone(record).(*l2sces.SliceRecord)
+	redactClusters(redacted.GetSlice().GetClusters())
+	return redacted
+}
+
+func redactClusters(clusters []*l2sces.Cluster) {
+	for _, cluster := range clusters {
+		if cluster.GetRestConfig() != nil {
+			cluster.RestConfig.BearerToken = ""
+		}
 	}
-	return &l2sces.DeleteSliceResponse{Message: "Slice deleted successfully"}, nil
 }
@@ -0,0 +1,217 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/mdclient"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/registry"
+)
+
+// sliceClient records the slices it creates.
+type sliceClient struct {
+	mdclient.MDClient
+	created *l2sces.Slice
+}
+
+func (client *sliceClient) CreateSlice(ctx context.Context, slice *l2sces.Slice, namespace string) ([]mdclient.ClusterResult, error) {
+	client.created = slice
+	return nil, nil
+}
+
+// TestCreateSliceCosts checks that the known link costs complete the ones of the slice, and that the links
+// and their cost are returned, while only the costs of the request are registered.
+func TestCreateSliceCosts(t *testing.T) {
+	store, err := registry.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &sliceClient{}
+	s := &server{MDClient: client, Store: store, LinkCosts: []*l2sces.LinkCost{
+		{EndpointA: "cluster-1", EndpointB: "cluster-2", Cost: 10},
+		{EndpointA: "cluster-2", EndpointB: "cluster-3", Cost: 20},
+		{EndpointA: "cluster-3", EndpointB: "cluster-4", Cost: 5},
+	}}
+
+	slice := &l2sces.Slice{
+		Provider: &l2sces.Provider{Name: "test-slice", Domain: "10.0.0.1"},
+		Clusters: []*l2sces.Cluster{
+			{Name: "cluster-1", GatewayNode: &l2sces.Node{Name: "node-1", IpAddress: "172.20.0.3"}},
+			{Name: "cluster-2", GatewayNode: &l2sces.Node{Name: "node-2", IpAddress: "172.20.0.4"}},
+			{Name: "cluster-3", GatewayNode: &l2sces.Node{Name: "node-3", IpAddress: "172.20.0.5"}},
+		},
+		Topology: &l2sces.Topology{Strategy: "MinimumCost", Redundancy: 1, Costs: []*l2sces.LinkCost{
+			{EndpointA: "cluster-3", EndpointB: "cluster-1", Cost: 1},
+		}},
+	}
+	resp, err := s.CreateSlice(context.Background(), &l2sces.CreateSliceRequest{Slice: slice})
+	if err != nil {
+		t.Fatalf("CreateSlice failed: %v", err)
+	}
+	if len(resp.GetLinks()) != 2 || resp.GetCost() != 11 {
+		t.Errorf("expected the cluster-1 links with a cost of 11, got %v with a cost of %v", resp.GetLinks(), resp.GetCost())
+	}
+	if len(resp.GetWarnings()) != 3 {
+		t.Errorf("expected cluster-1 and both links to be single points of failure, got %v", resp.GetWarnings())
+	}
+	if costs := client.created.GetTopology().GetCosts(); len(costs) != 3 {
+		t.Errorf("expected the costs between the clusters of the slice only, got %v", costs)
+	}
+	if len(slice.GetTopology().GetCosts()) != 1 {
+		t.Error("expected the request not to be modified")
+	}
+	record, err := store.GetSlice(context.Background(), "test-slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if costs := record.GetSlice().GetTopology().GetCosts(); len(costs) != 1 {
+		t.Errorf("expected only the costs of the request to be registered, got %v", costs)
+	}
+}
+
+// TestRemoveClusterDisconnecting checks that a cluster can't leave a slice it holds together.
+func TestRemoveClusterDisconnecting(t *testing.T) {
+	store, err := registry.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{MDClient: &sliceClient{}, Store: store}
+	slice := &l2sces.Slice{
+		Provider: &l2sces.Provider{Name: "test-slice"},
+		Clusters: []*l2sces.Cluster{{Name: "cluster-1"}, {Name: "cluster-2"}, {Name: "cluster-3"}},
+		Links:    []*l2sces.Link{{EndpointA: "cluster-1", EndpointB: "cluster-2"}, {EndpointA: "cluster-2", EndpointB: "cluster-3"}},
+	}
+	if err := store.PutSlice(context.Background(), &l2sces.SliceRecord{Slice: slice}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.RemoveCluster(context.Background(), &l2sces.RemoveClusterRequest{OverlayName: "test-slice", ClusterName: "cluster-2"})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("expected %s, got %s (%v)", codes.InvalidArgument, code, err)
+	}
+}
+
+// statusClient reports the neighbors each cluster is connected to.
+type statusClient struct {
+	mdclient.MDClient
+	connected map[string][]string
+}
+
+func (client *statusClient) ConnectedNeighbors(ctx context.Context, slice *l2sces.Slice, namespace string) map[string][]string {
+	return client.connected
+}
+
+// TestGetSliceTopology checks that the live status of the links is only overlaid when asked for.
+func TestGetSliceTopology(t *testing.T) {
+	store, err := registry.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{MDClient: &statusClient{connected: map[string][]string{"cluster-1": {}, "cluster-2": {}}}, Store: store}
+	slice := &l2sces.Slice{
+		Provider: &l2sces.Provider{Name: "test-slice"},
+		Clusters: []*l2sces.Cluster{{Name: "cluster-1"}, {Name: "cluster-2"}},
+	}
+	if err := store.PutSlice(context.Background(), &l2sces.SliceRecord{Slice: slice}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.GetSliceTopology(context.Background(), &l2sces.GetSliceTopologyRequest{Name: "test-slice", Format: "dot"})
+	if err != nil {
+		t.Fatalf("GetSliceTopology failed: %v", err)
+	}
+	if !strings.Contains(resp.GetTopology(), `"cluster-1" -- "cluster-2";`) {
+		t.Errorf("expected a link without status, got:\n%s", resp.GetTopology())
+	}
+
+	resp, err = s.GetSliceTopology(context.Background(), &l2sces.GetSliceTopologyRequest{Name: "test-slice", Format: "dot", Live: true})
+	if err != nil {
+		t.Fatalf("GetSliceTopology failed: %v", err)
+	}
+	if !strings.Contains(resp.GetTopology(), `label="down"`) {
+		t.Errorf("expected the link to be down, got:\n%s", resp.GetTopology())
+	}
+
+	_, err = s.GetSliceTopology(context.Background(), &l2sces.GetSliceTopologyRequest{Name: "test-slice", Format: "svg"})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("expected %s, got %s (%v)", codes.InvalidArgument, code, err)
+	}
+}
+
+// networkClient creates every network.
+type networkClient struct {
+	mdclient.MDClient
+}
+
+func (client *networkClient) CreateNetwork(ctx context.Context, network *l2sces.L2Network, namespace string) ([]mdclient.ClusterResult, error) {
+	return nil, nil
+}
+
+// TestCreateNetworkAllocations checks that the pod address range of each cluster is registered.
+func TestCreateNetworkAllocations(t *testing.T) {
+	store, err := registry.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &server{MDClient: &networkClient{}, Store: store}
+	network := &l2sces.L2Network{Name: "net", Type: "vnet", PodCidr: "10.0.0.0/16", Provider: &l2sces.Provider{Name: "idco", Domain: "10.0.0.1"},
+		Clusters: []*l2sces.Cluster{{Name: "cluster-1"}, {Name: "cluster-2", PodAddressPool: "10.1.0.0/24"}}}
+	if _, err := s.CreateNetwork(context.Background(), &l2sces.CreateNetworkRequest{Network: network}); err != nil {
+		t.Fatalf("CreateNetwork failed: %v", err)
+	}
+
+	record, err := store.GetNetwork(context.Background(), "net")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pools, _ := mdclient.PodAddressPools(network)
+	for index, cluster := range record.GetNetwork().GetClusters() {
+		if cluster.GetPodAddressPool() == "" || cluster.GetPodAddressPool() != pools[index] {
+			t.Errorf("expected cluster %s to be registered with %s, got %q", cluster.GetName(), pools[index], cluster.GetPodAddressPool())
+		}
+	}
+	if network.GetClusters()[0].GetPodAddressPool() != "" {
+		t.Error("expected the request not to be modified")
+	}
+}
+
+// TestNamespaceConflict checks that a slice registered in a namespace can't be replaced from another one.
+func TestNamespaceConflict(t *testing.T) {
+	store, err := registry.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &sliceClient{}
+	s := &server{MDClient: client, Store: store}
+	slice := &l2sces.Slice{Provider: &l2sces.Provider{Name: "test-slice", Domain: "10.0.0.1"}, Clusters: []*l2sces.Cluster{{Name: "cluster-1"}}}
+	if err := store.PutSlice(context.Background(), &l2sces.SliceRecord{Slice: slice, Namespace: "tenant-b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.CreateSlice(context.Background(), &l2sces.CreateSliceRequest{Slice: slice, Namespace: "tenant-a"})
+	if code := status.Code(err); code != codes.AlreadyExists {
+		t.Errorf("expected %s, got %s (%v)", codes.AlreadyExists, code, err)
+	}
+	if client.created != nil {
+		t.Error("expected no cluster to be changed")
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 Universidad Carlos III de Madrid
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	l2scesv1 "github.com/Networks-it-uc3m/l2sc-es/api/v1"
+	"github.com/Networks-it-uc3m/l2sc-es/internal/ocminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
+)
+
+// MemberClusterScheme knows the L2S-M types the controllers write in the member clusters.
+var MemberClusterScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(MemberClusterScheme))
+	utilruntime.Must(l2smv1.AddToScheme(MemberClusterScheme))
+}
+
+// ErrNotRegistered is wrapped by the errors of MemberClusters for clusters without credentials in the
+// management cluster.
+var ErrNotRegistered = errors.New("not registered")
+
+// MemberClusters gives access to the member clusters a slice spans, by cluster name.
+type MemberClusters interface {
+	GetClient(ctx context.Context, clusterName string) (client.Client, error)
+}
+
+// SecretMemberClusters resolves member clusters from the l2sm-cert Secrets of the management cluster.
+// The Secret must hold the API server URL and a bearer token besides the CA certificate.
+type SecretMemberClusters struct {
+	Reader client.Reader
+	// Namespace is the namespace of the Secrets, every namespace if empty.
+	Namespace string
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list
+
+func (m *SecretMemberClusters) GetClient(ctx context.Context, clusterName string) (client.Client, error) {
+	secrets := &corev1.SecretList{}
+	if err := m.Reader.List(ctx, secrets, client.InNamespace(m.Namespace), client.MatchingLabels{operator.CertLabel: clusterName}); err != nil {
+		return nil, fmt.Errorf("could not list secrets of cluster %s: %v", clusterName, err)
+	}
+	if len(secrets.Items) == 0 {
+		return nil, fmt.Errorf("cluster %s is %w", clusterName, ErrNotRegistered)
+	}
+
+	clusterConfig, err := operator.ClusterConfigFromSecret(&secrets.Items[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(clusterConfig, client.Options{Scheme: MemberClusterScheme})
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=watch
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=placementdecisions,verbs=get;list;watch
+
+// selectClusters returns the member clusters a SliceNetwork spans: the ones listed in its spec, or the ones
+// its cluster selector resolves to, sorted by name. Label selectors match the l2sm-cert Secrets of the given
+// namespace, or of every namespace if empty.
+func selectClusters(ctx context.Context, reader client.Reader, certNamespace string, sliceNetwork *l2scesv1.SliceNetwork) ([]string, error) {
+	selector := sliceNetwork.Spec.ClusterSelector
+	switch {
+	case selector == nil:
+		return uniqueNames(sliceNetwork.Spec.Clusters), nil
+	case selector.LabelSelector != nil:
+		return labeledClusters(ctx, reader, certNamespace, selector.LabelSelector)
+	case selector.Placement != nil:
+		return placedClusters(ctx, reader, sliceNetwork.Namespace, selector.Placement.Name)
+	}
+	return nil, errors.New("the cluster selector is empty")
+}
+
+// labeledClusters returns the registered member clusters whose l2sm-cert Secret matches the label selector.
+// Only the metadata of the Secrets is read.
+func labeledClusters(ctx context.Context, reader client.Reader, namespace string, labelSelector *metav1.LabelSelector) ([]string, error) {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %v", err)
+	}
+	secrets := &metav1.PartialObjectMetadataList{}
+	secrets.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("SecretList"))
+	if err := reader.List(ctx, secrets, client.InNamespace(namespace), client.HasLabels{operator.CertLabel}); err != nil {
+		return nil, fmt.Errorf("could not list the registered clusters: %v", err)
+	}
+
+	clusterNames := []string{}
+	for _, secret := range secrets.Items {
+		if selector.Matches(labels.Set(secret.Labels)) {
+			clusterNames = append(clusterNames, secret.Labels[operator.CertLabel])
+		}
+	}
+	sort.Strings(clusterNames)
+	return uniqueNames(clusterNames), nil
+}
+
+// placedClusters returns the clusters decided by an Open Cluster Management Placement.
+func placedClusters(ctx context.Context, reader client.Reader, namespace string, placementName string) ([]string, error) {
+	decisions := &unstructured.UnstructuredList{}
+	decisions.SetGroupVersionKind(ocminterface.PlacementDecisionGVK.GroupVersion().WithKind(ocminterface.PlacementDecisionGVK.Kind + "List"))
+	err := reader.List(ctx, decisions, client.InNamespace(namespace), client.MatchingLabels{ocminterface.PlacementLabel: placementName})
+	if err != nil {
+		return nil, fmt.Errorf("could not list the decisions of placement %s: %v", placementName, err)
+	}
+
+	clusterNames := []string{}
+	for index := range decisions.Items {
+		clusterNames = append(clusterNames, ocminterface.DecidedClusters(&decisions.Items[index])...)
+	}
+	sort.Strings(clusterNames)
+	return uniqueNames(clusterNames), nil
+}
+
+// setLabel sets a label on an object, creating its label map if needed.
+func setLabel(objectMeta *metav1.ObjectMeta, key, value string) {
+	if objectMeta.Labels == nil {
+		objectMeta.Labels = map[string]string{}
+	}
+	objectMeta.Labels[key] = value
+}
+
+// ignoreNotRegistered returns nil for the errors of unregistered clusters, so that the objects of a slice
+// resource count as removed from clusters it could never reach.
+func ignoreNotRegistered(err error) error {
+	if errors.Is(err, ErrNotRegistered) {
+		return nil
+	}
+	return err
+}
+
+// deleteLabeled deletes an object from a member cluster if it carries the given label, so that objects
+// not created by the controllers are left alone. A missing object counts as deleted.
+func deleteLabeled(ctx context.Context, memberClient client.Client, obj client.Object, key, value string) error {
+	err := memberClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if obj.GetLabels()[key] != value {
+		return nil
+	}
+	return client.IgnoreNotFound(memberClient.Delete(ctx, obj))
+}
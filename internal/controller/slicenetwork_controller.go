@@ -18,46 +18,328 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	l2scesv1 "github.com/Networks-it-uc3m/l2sc-es/api/v1"
+	"github.com/Networks-it-uc3m/l2sc-es/internal/ocminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
+)
+
+const (
+	// conditionReady is the condition type that summarizes every cluster of a slice resource.
+	conditionReady = "Ready"
+
+	// sliceNetworkLabel marks the L2Networks created on behalf of a SliceNetwork.
+	sliceNetworkLabel = "l2sces.l2sm.io/slicenetwork"
+
+	// requeueInterval is how often a resource that is not ready yet is checked again.
+	requeueInterval = 30 * time.Second
+
+	// sliceFinalizer keeps slice resources around until their member cluster objects are deleted.
+	sliceFinalizer = "l2sces.l2sm.io/finalizer"
 )
 
 // SliceNetworkReconciler reconciles a SliceNetwork object
 type SliceNetworkReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme         *runtime.Scheme
+	MemberClusters MemberClusters
+	// CertNamespace is the namespace of the l2sm-cert Secrets label selectors match, every namespace if empty.
+	CertNamespace string
 }
 
 // +kubebuilder:rbac:groups=l2sces.l2sm.io,resources=slicenetworks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=l2sces.l2sm.io,resources=slicenetworks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=l2sces.l2sm.io,resources=slicenetworks/finalizers,verbs=update
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the SliceNetwork object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
+// Reconcile creates or updates the L2Network of a SliceNetwork in every cluster listed in its spec, or
+// selected by its cluster selector, and records the outcome of each cluster in the status. The L2Network is
+// removed from the clusters that are no longer part of the SliceNetwork. When the SliceNetwork is deleted,
+// the L2Networks are removed before its finalizer is released.
 func (r *SliceNetworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = logf.FromContext(ctx)
+	log := logf.FromContext(ctx)
+
+	sliceNetwork := &l2scesv1.SliceNetwork{}
+	if err := r.Get(ctx, req.NamespacedName, sliceNetwork); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !sliceNetwork.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, sliceNetwork)
+	}
+
+	if controllerutil.AddFinalizer(sliceNetwork, sliceFinalizer) {
+		if err := r.Update(ctx, sliceNetwork); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	clusterNames, err := selectClusters(ctx, r.Client, r.CertNamespace, sliceNetwork)
+	if err != nil {
+		log.Info("could not select the clusters", "error", err.Error())
+		meta.SetStatusCondition(&sliceNetwork.Status.Conditions, metav1.Condition{
+			Type:               conditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ClusterSelectionFailed",
+			Message:            err.Error(),
+			ObservedGeneration: sliceNetwork.Generation,
+		})
+		if err := r.Status().Update(ctx, sliceNetwork); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueInterval}, nil
+	}
+
+	l2network := l2sminterface.ConstructL2NetworkFromSliceNetwork(sliceNetwork)
+
+	clusterStatuses := make([]l2scesv1.SliceClusterStatus, 0, len(clusterNames))
+	var provisioned []string
+	for _, clusterName := range clusterNames {
+		clusterStatus := r.reconcileCluster(ctx, clusterName, l2network)
+		if clusterStatus.Status == l2scesv1.ClusterStatusError {
+			log.Info("could not provision l2network", "cluster", clusterName, "error", clusterStatus.Message)
+		}
+		// Only clusters the L2Network was applied in, now or before, have to be cleaned up
+		if clusterStatus.Status != l2scesv1.ClusterStatusError || slices.Contains(sliceNetwork.Status.ProvisionedClusters, clusterName) {
+			provisioned = append(provisioned, clusterName)
+		}
+		clusterStatuses = append(clusterStatuses, clusterStatus)
+	}
 
-	// TODO(user): your logic here
+	// Clusters that can't be cleaned yet are kept in the status, so that they are retried
+	for _, clusterName := range leftClusters(sliceNetwork, clusterNames) {
+		if err := r.removeL2Network(ctx, sliceNetwork, clusterName); err != nil {
+			log.Info("could not remove l2network", "cluster", clusterName, "error", err.Error())
+			clusterStatuses = append(clusterStatuses, l2scesv1.SliceClusterStatus{
+				ClusterName: clusterName,
+				Status:      l2scesv1.ClusterStatusError,
+				Message:     fmt.Sprintf("could not remove the l2network from a cluster that left: %v", err),
+			})
+			provisioned = append(provisioned, clusterName)
+		}
+	}
 
+	sliceNetwork.Status.Clusters = clusterNames
+	sliceNetwork.Status.ProvisionedClusters = provisioned
+	sliceNetwork.Status.ClusterStatuses = clusterStatuses
+	condition := readyCondition(clusterStatuses, sliceNetwork.Generation)
+	if len(clusterNames) == 0 {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "NoClusters", "no cluster matches the cluster selector"
+	}
+	meta.SetStatusCondition(&sliceNetwork.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, sliceNetwork); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !meta.IsStatusConditionTrue(sliceNetwork.Status.Conditions, conditionReady) {
+		return ctrl.Result{RequeueAfter: requeueInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// reconcileCluster makes sure the given L2Network exists in the member cluster and reports its state.
+func (r *SliceNetworkReconciler) reconcileCluster(ctx context.Context, clusterName string, l2network *l2smv1.L2Network) l2scesv1.SliceClusterStatus {
+	clusterStatus := l2scesv1.SliceClusterStatus{ClusterName: clusterName}
+
+	memberClient, err := r.MemberClusters.GetClient(ctx, clusterName)
+	if err != nil {
+		clusterStatus.Status = l2scesv1.ClusterStatusError
+		clusterStatus.Message = err.Error()
+		return clusterStatus
+	}
+
+	existing := &l2smv1.L2Network{ObjectMeta: metav1.ObjectMeta{Name: l2network.Name, Namespace: l2network.Namespace}}
+	_, err = controllerutil.CreateOrUpdate(ctx, memberClient, existing, func() error {
+		setLabel(&existing.ObjectMeta, sliceNetworkLabel, l2network.Name)
+		existing.Spec.Type = l2network.Spec.Type
+		existing.Spec.Provider = l2network.Spec.Provider
+		return nil
+	})
+	if err != nil {
+		clusterStatus.Status = l2scesv1.ClusterStatusError
+		clusterStatus.Message = err.Error()
+		return clusterStatus
+	}
+
+	if existing.Status.InternalConnectivity == nil || *existing.Status.InternalConnectivity != l2smv1.OnlineStatus {
+		clusterStatus.Status = l2scesv1.ClusterStatusPending
+		clusterStatus.Message = "waiting for the l2network to connect to the SDN controller"
+		return clusterStatus
+	}
+
+	clusterStatus.Status = l2scesv1.ClusterStatusReady
+	return clusterStatus
+}
+
+// finalize deletes the L2Network from every cluster the SliceNetwork was provisioned in, as recorded in its
+// status. Clusters that can't be reached are retried, and the finalizer is only removed once all of them
+// are clean. Clusters that are no longer registered count as clean.
+func (r *SliceNetworkReconciler) finalize(ctx context.Context, sliceNetwork *l2scesv1.SliceNetwork) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(sliceNetwork, sliceFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	var failures []string
+	for _, clusterName := range uniqueNames(sliceNetwork.Status.ProvisionedClusters) {
+		if err := r.removeL2Network(ctx, sliceNetwork, clusterName); err != nil {
+			failures = append(failures, fmt.Sprintf("cluster %s: %v", clusterName, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		meta.SetStatusCondition(&sliceNetwork.Status.Conditions, deletingCondition(failures, sliceNetwork.Generation))
+		if err := r.Status().Update(ctx, sliceNetwork); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueInterval}, nil
+	}
+
+	controllerutil.RemoveFinalizer(sliceNetwork, sliceFinalizer)
+	return ctrl.Result{}, r.Update(ctx, sliceNetwork)
+}
+
+// removeL2Network deletes the L2Network of the SliceNetwork from a member cluster. Nothing is left to delete
+// from a cluster that is not registered.
+func (r *SliceNetworkReconciler) removeL2Network(ctx context.Context, sliceNetwork *l2scesv1.SliceNetwork, clusterName string) error {
+	memberClient, err := r.MemberClusters.GetClient(ctx, clusterName)
+	if err != nil {
+		return ignoreNotRegistered(err)
+	}
+	l2network := &l2smv1.L2Network{ObjectMeta: metav1.ObjectMeta{Name: sliceNetwork.Name, Namespace: sliceNetwork.Namespace}}
+	return deleteLabeled(ctx, memberClient, l2network, sliceNetworkLabel, sliceNetwork.Name)
+}
+
+// leftClusters returns the clusters the SliceNetwork was provisioned in that are no longer part of it.
+func leftClusters(sliceNetwork *l2scesv1.SliceNetwork, clusterNames []string) []string {
+	left := []string{}
+	for _, clusterName := range uniqueNames(sliceNetwork.Status.ProvisionedClusters) {
+		if !slices.Contains(clusterNames, clusterName) {
+			left = append(left, clusterName)
+		}
+	}
+	return left
+}
+
+// deletingCondition reports the clusters that still hold objects of a slice resource being deleted.
+func deletingCondition(failures []string, generation int64) metav1.Condition {
+	return metav1.Condition{
+		Type:               conditionReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Deleting",
+		Message:            "waiting for " + strings.Join(failures, "; "),
+		ObservedGeneration: generation,
+	}
+}
+
+// uniqueNames removes the repeated names of a list, keeping the order of their first appearance.
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
+
+// readyCondition summarizes the per-cluster statuses in a single Ready condition.
+func readyCondition(clusterStatuses []l2scesv1.SliceClusterStatus, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               conditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Provisioned",
+		Message:            "all clusters are ready",
+		ObservedGeneration: generation,
+	}
+	for _, clusterStatus := range clusterStatuses {
+		switch clusterStatus.Status {
+		case l2scesv1.ClusterStatusError:
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "ProvisioningFailed"
+			condition.Message = "cluster " + clusterStatus.ClusterName + ": " + clusterStatus.Message
+			return condition
+		case l2scesv1.ClusterStatusPending:
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = "Provisioning"
+			condition.Message = "cluster " + clusterStatus.ClusterName + " is not ready yet"
+		}
+	}
+	return condition
+}
+
+// SetupWithManager sets up the controller with the Manager. SliceNetworks with a cluster selector are
+// reconciled again when the registered clusters change, or the decisions of their Placement if Open Cluster
+// Management is installed.
 func (r *SliceNetworkReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&l2scesv1.SliceNetwork{}).
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.labelSelectedSliceNetworks),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				_, registered := obj.GetLabels()[operator.CertLabel]
+				return registered && (r.CertNamespace == "" || obj.GetNamespace() == r.CertNamespace)
+			})))
+
+	if _, err := mgr.GetRESTMapper().RESTMapping(ocminterface.PlacementDecisionGVK.GroupKind(), ocminterface.PlacementDecisionGVK.Version); err == nil {
+		decision := &unstructured.Unstructured{}
+		decision.SetGroupVersionKind(ocminterface.PlacementDecisionGVK)
+		controllerBuilder = controllerBuilder.Watches(decision, handler.EnqueueRequestsFromMapFunc(r.placementSelectedSliceNetworks))
+	}
+
+	return controllerBuilder.
 		Named("slicenetwork").
 		Complete(r)
 }
+
+// labelSelectedSliceNetworks returns the SliceNetworks selecting their clusters by label.
+func (r *SliceNetworkReconciler) labelSelectedSliceNetworks(ctx context.Context, _ client.Object) []reconcile.Request {
+	return r.selectingSliceNetworks(ctx, func(sliceNetwork *l2scesv1.SliceNetwork) bool {
+		return sliceNetwork.Spec.ClusterSelector.LabelSelector != nil
+	})
+}
+
+// placementSelectedSliceNetworks returns the SliceNetworks selecting their clusters with the Placement of
+// the given PlacementDecision.
+func (r *SliceNetworkReconciler) placementSelectedSliceNetworks(ctx context.Context, decision client.Object) []reconcile.Request {
+	placementName := decision.GetLabels()[ocminterface.PlacementLabel]
+	return r.selectingSliceNetworks(ctx, func(sliceNetwork *l2scesv1.SliceNetwork) bool {
+		placement := sliceNetwork.Spec.ClusterSelector.Placement
+		return placement != nil && placement.Name == placementName
+	}, client.InNamespace(decision.GetNamespace()))
+}
+
+func (r *SliceNetworkReconciler) selectingSliceNetworks(ctx context.Context, selects func(*l2scesv1.SliceNetwork) bool, opts ...client.ListOption) []reconcile.Request {
+	sliceNetworks := &l2scesv1.SliceNetworkList{}
+	if err := r.List(ctx, sliceNetworks, opts...); err != nil {
+		logf.FromContext(ctx).Error(err, "could not list the slicenetworks to reconcile")
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for index := range sliceNetworks.Items {
+		sliceNetwork := &sliceNetworks.Items[index]
+		if sliceNetwork.Spec.ClusterSelector != nil && selects(sliceNetwork) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(sliceNetwork)})
+		}
+	}
+	return requests
+}
@@ -19,15 +19,22 @@ package controller
 import (
 	"context"
 
+	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	l2scesv1 "github.com/Networks-it-uc3m/l2sc-es/api/v1"
+	"github.com/Networks-it-uc3m/l2sc-es/internal/ocminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
 )
 
 var _ = Describe("SliceNetwork Controller", func() {
@@ -38,11 +45,21 @@ var _ = Describe("SliceNetwork Controller", func() {
 
 		typeNamespacedName := types.NamespacedName{
 			Name:      resourceName,
-			Namespace: "default", // TODO(user):Modify as needed
+			Namespace: "default",
 		}
 		slicenetwork := &l2scesv1.SliceNetwork{}
 
+		var memberClusters fakeMemberClusters
+		var controllerReconciler *SliceNetworkReconciler
+
 		BeforeEach(func() {
+			memberClusters = newFakeMemberClusters("cluster-a", "cluster-b")
+			controllerReconciler = &SliceNetworkReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				MemberClusters: memberClusters,
+			}
+
 			By("creating the custom resource for the Kind SliceNetwork")
 			err := k8sClient.Get(ctx, typeNamespacedName, slicenetwork)
 			if err != nil && errors.IsNotFound(err) {
@@ -51,34 +68,296 @@ var _ = Describe("SliceNetwork Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: l2scesv1.SliceNetworkSpec{
+						Clusters: []string{"cluster-a", "cluster-b"},
+						Type:     "vnet",
+						Provider: &l2smv1.ProviderSpec{
+							Name:   "test-slice",
+							Domain: []string{"idco.example.com"},
+						},
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
 			resource := &l2scesv1.SliceNetwork{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance SliceNetwork")
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
 		})
-		It("should successfully reconcile the resource", func() {
+		It("should create the l2network in every member cluster", func() {
 			By("Reconciling the created resource")
-			controllerReconciler := &SliceNetworkReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, clusterName := range []string{"cluster-a", "cluster-b"} {
+				l2network := &l2smv1.L2Network{}
+				Expect(memberClusters[clusterName].Get(ctx, typeNamespacedName, l2network)).To(Succeed())
+				Expect(l2network.Spec.Type).To(Equal(l2smv1.NetworkType("vnet")))
+				Expect(l2network.Spec.Provider.Name).To(Equal("test-slice"))
+			}
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, slicenetwork)).To(Succeed())
+			Expect(slicenetwork.Status.ClusterStatuses).To(HaveLen(2))
+			for _, clusterStatus := range slicenetwork.Status.ClusterStatuses {
+				Expect(clusterStatus.Status).To(Equal(l2scesv1.ClusterStatusPending))
+			}
+			Expect(meta.IsStatusConditionFalse(slicenetwork.Status.Conditions, conditionReady)).To(BeTrue())
+		})
+		It("should report an error for an unknown cluster", func() {
+			partialReconciler := &SliceNetworkReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				MemberClusters: fakeMemberClusters{"cluster-a": memberClusters["cluster-a"]},
+			}
+
+			_, err := partialReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, slicenetwork)).To(Succeed())
+			Expect(slicenetwork.Status.ClusterStatuses).To(ContainElement(HaveField("Status", l2scesv1.ClusterStatusError)))
+			condition := meta.FindStatusCondition(slicenetwork.Status.Conditions, conditionReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("ProvisioningFailed"))
+		})
+		It("should delete the l2networks before releasing the finalizer", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, slicenetwork)).To(Succeed())
+			Expect(slicenetwork.Finalizers).To(ContainElement(sliceFinalizer))
+			Expect(k8sClient.Delete(ctx, slicenetwork)).To(Succeed())
+
+			By("retrying while a cluster is unreachable")
+			partialReconciler := &SliceNetworkReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				MemberClusters: fakeMemberClusters{"cluster-a": memberClusters["cluster-a"], "cluster-b": nil},
+			}
+			result, err := partialReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).NotTo(BeZero())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, slicenetwork)).To(Succeed())
+			condition := meta.FindStatusCondition(slicenetwork.Status.Conditions, conditionReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("Deleting"))
+			Expect(condition.Message).To(ContainSubstring("cluster-b"))
+			l2network := &l2smv1.L2Network{}
+			Expect(errors.IsNotFound(memberClusters["cluster-a"].Get(ctx, typeNamespacedName, l2network))).To(BeTrue())
+
+			By("releasing the finalizer once every cluster is clean")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(memberClusters["cluster-b"].Get(ctx, typeNamespacedName, l2network))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, slicenetwork))).To(BeTrue())
+		})
+		It("should not wait for clusters that were never registered on deletion", func() {
+			partialReconciler := &SliceNetworkReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				MemberClusters: fakeMemberClusters{"cluster-a": memberClusters["cluster-a"]},
+			}
+			_, err := partialReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, slicenetwork)).To(Succeed())
+			Expect(slicenetwork.Status.ProvisionedClusters).To(Equal([]string{"cluster-a"}))
+			Expect(k8sClient.Delete(ctx, slicenetwork)).To(Succeed())
+
+			_, err = partialReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(memberClusters["cluster-a"].Get(ctx, typeNamespacedName, &l2smv1.L2Network{}))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, slicenetwork))).To(BeTrue())
+		})
+	})
+
+	Context("When selecting the clusters", func() {
+		const resourceName = "selected-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		var memberClusters fakeMemberClusters
+		var controllerReconciler *SliceNetworkReconciler
+
+		createSliceNetwork := func(clusterSelector *l2scesv1.ClusterSelector) {
+			resource := &l2scesv1.SliceNetwork{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: l2scesv1.SliceNetworkSpec{
+					ClusterSelector: clusterSelector,
+					Type:            "vnet",
+					Provider: &l2smv1.ProviderSpec{
+						Name:   "test-slice",
+						Domain: []string{"idco.example.com"},
+					},
+				},
 			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
 
+		reconcileSelected := func() *l2scesv1.SliceNetwork {
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			sliceNetwork := &l2scesv1.SliceNetwork{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceNetwork)).To(Succeed())
+			return sliceNetwork
+		}
+
+		hasL2Network := func(clusterName string) bool {
+			err := memberClusters[clusterName].Get(ctx, typeNamespacedName, &l2smv1.L2Network{})
+			if errors.IsNotFound(err) {
+				return false
+			}
+			Expect(err).NotTo(HaveOccurred())
+			return true
+		}
+
+		BeforeEach(func() {
+			memberClusters = newFakeMemberClusters("cluster-a", "cluster-b", "cluster-c")
+			controllerReconciler = &SliceNetworkReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				MemberClusters: memberClusters,
+				CertNamespace:  "default",
+			}
+		})
+
+		AfterEach(func() {
+			resource := &l2scesv1.SliceNetwork{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+				Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+				_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			for _, namespace := range []string{"default", "kube-public"} {
+				Expect(k8sClient.DeleteAllOf(ctx, &corev1.Secret{}, client.InNamespace(namespace), client.HasLabels{operator.CertLabel})).To(Succeed())
+			}
+		})
+
+		It("should follow the clusters matching the label selector", func() {
+			for clusterName, zone := range map[string]string{"cluster-a": "east", "cluster-b": "east", "cluster-c": "west"} {
+				Expect(k8sClient.Create(ctx, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      clusterName + "-cert",
+						Namespace: "default",
+						Labels:    map[string]string{operator.CertLabel: clusterName, "zone": zone},
+					},
+				})).To(Succeed())
+			}
+			// Secrets outside of the cert namespace don't register clusters
+			Expect(k8sClient.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cluster-c-cert",
+					Namespace: "kube-public",
+					Labels:    map[string]string{operator.CertLabel: "cluster-c", "zone": "east"},
+				},
+			})).To(Succeed())
+			createSliceNetwork(&l2scesv1.ClusterSelector{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "east"}},
+			})
+
+			sliceNetwork := reconcileSelected()
+			Expect(sliceNetwork.Status.Clusters).To(Equal([]string{"cluster-a", "cluster-b"}))
+			Expect(hasL2Network("cluster-a")).To(BeTrue())
+			Expect(hasL2Network("cluster-b")).To(BeTrue())
+			Expect(hasL2Network("cluster-c")).To(BeFalse())
+
+			By("removing the l2network from a cluster that stops matching")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "cluster-b-cert", Namespace: "default"}, secret)).To(Succeed())
+			secret.Labels["zone"] = "west"
+			Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+
+			sliceNetwork = reconcileSelected()
+			Expect(sliceNetwork.Status.Clusters).To(Equal([]string{"cluster-a"}))
+			Expect(sliceNetwork.Status.ClusterStatuses).To(HaveLen(1))
+			Expect(hasL2Network("cluster-a")).To(BeTrue())
+			Expect(hasL2Network("cluster-b")).To(BeFalse())
+		})
+		It("should follow the decisions of the placement", func() {
+			decision := &unstructured.Unstructured{}
+			decision.SetGroupVersionKind(ocminterface.PlacementDecisionGVK)
+			decision.SetName("east-decision-1")
+			decision.SetNamespace("default")
+			decision.SetLabels(map[string]string{ocminterface.PlacementLabel: "east"})
+			Expect(k8sClient.Create(ctx, decision)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, decision))).To(Succeed())
+			})
+
+			createSliceNetwork(&l2scesv1.ClusterSelector{
+				Placement: &l2scesv1.PlacementReference{Name: "east"},
+			})
+
+			By("reporting that no cluster was decided yet")
+			sliceNetwork := reconcileSelected()
+			Expect(sliceNetwork.Status.Clusters).To(BeEmpty())
+			condition := meta.FindStatusCondition(sliceNetwork.Status.Conditions, conditionReady)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("NoClusters"))
+
+			By("extending to the decided clusters")
+			Expect(unstructured.SetNestedSlice(decision.Object, []interface{}{
+				map[string]interface{}{"clusterName": "cluster-c", "reason": ""},
+			}, "status", "decisions")).To(Succeed())
+			Expect(k8sClient.Status().Update(ctx, decision)).To(Succeed())
+
+			sliceNetwork = reconcileSelected()
+			Expect(sliceNetwork.Status.Clusters).To(Equal([]string{"cluster-c"}))
+			Expect(hasL2Network("cluster-c")).To(BeTrue())
+			Expect(hasL2Network("cluster-a")).To(BeFalse())
+		})
+		It("should reject a spec with both clusters and a cluster selector", func() {
+			resource := &l2scesv1.SliceNetwork{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: l2scesv1.SliceNetworkSpec{
+					Clusters: []string{"cluster-a"},
+					ClusterSelector: &l2scesv1.ClusterSelector{
+						Placement: &l2scesv1.PlacementReference{Name: "east"},
+					},
+					Type: "vnet",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).NotTo(Succeed())
 		})
 	})
 })
@@ -18,42 +18,408 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 
+	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	l2scesv1 "github.com/Networks-it-uc3m/l2sc-es/api/v1"
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologyanalysis"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologygenerator"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/validation"
+)
+
+const (
+	// sliceOverlayLabel marks the NetworkEdgeDevices and Overlays created on behalf of a SliceOverlay.
+	sliceOverlayLabel = "l2sces.l2sm.io/sliceoverlay"
+
+	// conditionResilient tells whether a SliceOverlay survives the failure of any of its clusters or links.
+	conditionResilient = "Resilient"
 )
 
 // SliceOverlayReconciler reconciles a SliceOverlay object
 type SliceOverlayReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme         *runtime.Scheme
+	MemberClusters MemberClusters
 }
 
 // +kubebuilder:rbac:groups=l2sces.l2sm.io,resources=sliceoverlays,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=l2sces.l2sm.io,resources=sliceoverlays/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=l2sces.l2sm.io,resources=sliceoverlays/finalizers,verbs=update
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the SliceOverlay object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
-//
-// For more details, check Reconcile and its Result here:
-// - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.22.4/pkg/reconcile
+// Reconcile deploys a NetworkEdgeDevice in every cluster of the SliceOverlay topology, with the
+// neighbors given by the topology links, and an Overlay in the clusters that list their nodes. Both are
+// removed from the clusters that leave the topology, as recorded in the status, and the Overlay from the
+// clusters that stop listing their nodes. When the SliceOverlay is deleted, both are removed before its
+// finalizer is released.
 func (r *SliceOverlayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	_ = logf.FromContext(ctx)
+	log := logf.FromContext(ctx)
+
+	sliceOverlay := &l2scesv1.SliceOverlay{}
+	if err := r.Get(ctx, req.NamespacedName, sliceOverlay); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !sliceOverlay.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, sliceOverlay)
+	}
+
+	if controllerutil.AddFinalizer(sliceOverlay, sliceFinalizer) {
+		if err := r.Update(ctx, sliceOverlay); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	topology := sliceOverlay.Spec.Topology
+	if topology == nil {
+		topology = &l2scesv1.OverlayTopology{}
+	}
+
+	nedGenerator := newNEDGenerator(sliceOverlay.Spec.Provider)
+	nedGenerator.SwitchTemplate = sliceOverlay.Spec.SwitchTemplate
+
+	links, err := overlayLinks(topology)
+	var analysis *topologyanalysis.Analysis
+	if err == nil {
+		analysis, err = analyzeOverlay(topology, links)
+	}
+	if err != nil {
+		// Only a change of the spec can fix the topology, so there is no point in requeueing
+		sliceOverlay.Status.Phase = l2scesv1.SliceOverlayPhaseFailed
+		meta.SetStatusCondition(&sliceOverlay.Status.Conditions, metav1.Condition{
+			Type:               conditionReady,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: sliceOverlay.Generation,
+			Reason:             "InvalidTopology",
+			Message:            err.Error(),
+		})
+		return ctrl.Result{}, r.Status().Update(ctx, sliceOverlay)
+	}
+	clusterNeighbors := l2sminterface.ComputeNeighbors(links, overlayGateways(topology))
+
+	// NetworkEdgeDevices are named after the provider, so the ones of a previous provider are removed first
+	nedName := nedGenerator.NEDName()
+	if previous := sliceOverlay.Status.NEDName; previous != "" && previous != nedName {
+		var failures []string
+		for _, clusterName := range sliceOverlay.Status.Clusters {
+			if err := r.removeNED(ctx, sliceOverlay, clusterName, previous); err != nil {
+				failures = append(failures, fmt.Sprintf("cluster %s: %v", clusterName, err))
+			}
+		}
+		if len(failures) > 0 {
+			sliceOverlay.Status.Phase = l2scesv1.SliceOverlayPhaseFailed
+			meta.SetStatusCondition(&sliceOverlay.Status.Conditions, metav1.Condition{
+				Type:               conditionReady,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: sliceOverlay.Generation,
+				Reason:             "CleanupFailed",
+				Message:            "could not remove the network edge devices of the previous provider: " + strings.Join(failures, "; "),
+			})
+			if err := r.Status().Update(ctx, sliceOverlay); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: requeueInterval}, nil
+		}
+	}
+
+	var deployedSwitches int32
+	var failures []string
+	var clusters, overlayClusters []string
+	allAvailable := true
+	for _, cluster := range topology.Nodes {
+		deployed, available, err := r.reconcileCluster(ctx, sliceOverlay, cluster, nedGenerator, clusterNeighbors[cluster.Name])
+		// Only clusters holding objects of the slice, now or before, have to be cleaned up
+		if deployed || slices.Contains(sliceOverlay.Status.Clusters, cluster.Name) {
+			clusters = append(clusters, cluster.Name)
+		}
+		// An Overlay that could not be removed yet is still recorded, so that it is retried
+		if (deployed && len(cluster.Nodes) > 0) || (err != nil && slices.Contains(sliceOverlay.Status.OverlayClusters, cluster.Name)) {
+			overlayClusters = append(overlayClusters, cluster.Name)
+		}
+		if err != nil {
+			log.Info("could not deploy the slice", "cluster", cluster.Name, "error", err.Error())
+			failures = append(failures, fmt.Sprintf("cluster %s: %v", cluster.Name, err))
+			continue
+		}
+		deployedSwitches++
+		allAvailable = allAvailable && available
+	}
+
+	// Clusters that can't be cleaned yet are kept in the status, so that they are retried
+	for _, clusterName := range leftOverlayClusters(sliceOverlay, clusters) {
+		if err := r.cleanupCluster(ctx, sliceOverlay, clusterName, []string{nedName}); err != nil {
+			log.Info("could not remove the slice", "cluster", clusterName, "error", err.Error())
+			failures = append(failures, fmt.Sprintf("cluster %s: could not remove the slice from a cluster that left: %v", clusterName, err))
+			if slices.Contains(sliceOverlay.Status.Clusters, clusterName) {
+				clusters = append(clusters, clusterName)
+			}
+			if slices.Contains(sliceOverlay.Status.OverlayClusters, clusterName) {
+				overlayClusters = append(overlayClusters, clusterName)
+			}
+		}
+	}
 
-	// TODO(user): your logic here
+	condition := metav1.Condition{
+		Type:               conditionReady,
+		ObservedGeneration: sliceOverlay.Generation,
+	}
+	switch {
+	case len(failures) > 0:
+		sliceOverlay.Status.Phase = l2scesv1.SliceOverlayPhaseFailed
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "DeploymentFailed"
+		condition.Message = failures[0]
+	case !allAvailable:
+		sliceOverlay.Status.Phase = l2scesv1.SliceOverlayPhasePending
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Deploying"
+		condition.Message = "waiting for the network edge devices to become available"
+	default:
+		sliceOverlay.Status.Phase = l2scesv1.SliceOverlayPhaseReady
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Deployed"
+		condition.Message = "all network edge devices are available"
+	}
+	sliceOverlay.Status.DeployedSwitches = deployedSwitches
+	sliceOverlay.Status.Clusters = clusters
+	sliceOverlay.Status.OverlayClusters = overlayClusters
+	sliceOverlay.Status.NEDName = nedName
+	meta.SetStatusCondition(&sliceOverlay.Status.Conditions, condition)
+	meta.SetStatusCondition(&sliceOverlay.Status.Conditions, resilienceCondition(analysis, sliceOverlay.Generation))
+	if err := r.Status().Update(ctx, sliceOverlay); err != nil {
+		return ctrl.Result{}, err
+	}
 
+	if condition.Status != metav1.ConditionTrue {
+		return ctrl.Result{RequeueAfter: requeueInterval}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+// reconcileCluster creates or updates the NetworkEdgeDevice, and the Overlay if the cluster lists its
+// nodes, in a member cluster. The Overlay is removed if the cluster no longer lists its nodes. It returns
+// whether the NetworkEdgeDevice was applied, even if the Overlay then failed, and whether it is already
+// available.
+func (r *SliceOverlayReconciler) reconcileCluster(ctx context.Context, sliceOverlay *l2scesv1.SliceOverlay, cluster l2scesv1.OverlayCluster,
+	nedGenerator *l2sminterface.NEDGenerator, neighbors []l2sminterface.Neighbor) (bool, bool, error) {
+
+	if cluster.Gateway == nil {
+		return false, false, fmt.Errorf("no gateway defined")
+	}
+
+	memberClient, err := r.MemberClusters.GetClient(ctx, cluster.Name)
+	if err != nil {
+		return false, false, err
+	}
+
+	desired := nedGenerator.ConstructNED(l2sminterface.NEDValues{
+		NodeConfig: l2sminterface.NodeConfig{NodeName: cluster.Gateway.NodeName, IPAddress: cluster.Gateway.IPAddress},
+		Neighbors:  neighbors,
+	})
+
+	ned := &l2smv1.NetworkEdgeDevice{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: sliceOverlay.Namespace}}
+	_, err = controllerutil.CreateOrUpdate(ctx, memberClient, ned, func() error {
+		setLabel(&ned.ObjectMeta, sliceOverlayLabel, sliceOverlay.Name)
+		ned.Spec = desired.Spec
+		return nil
+	})
+	if err != nil {
+		return false, false, err
+	}
+
+	if len(cluster.Nodes) > 0 {
+		desiredOverlay := l2sminterface.ConstructOverlay(sliceOverlay.Name, sliceOverlay.Namespace, cluster.Nodes)
+		overlay := &l2smv1.Overlay{ObjectMeta: metav1.ObjectMeta{Name: desiredOverlay.Name, Namespace: desiredOverlay.Namespace}}
+		_, err = controllerutil.CreateOrUpdate(ctx, memberClient, overlay, func() error {
+			setLabel(&overlay.ObjectMeta, sliceOverlayLabel, sliceOverlay.Name)
+			overlay.Spec = desiredOverlay.Spec
+			return nil
+		})
+		if err != nil {
+			return true, false, err
+		}
+	} else if slices.Contains(sliceOverlay.Status.OverlayClusters, cluster.Name) {
+		overlay := &l2smv1.Overlay{ObjectMeta: metav1.ObjectMeta{Name: sliceOverlay.Name, Namespace: sliceOverlay.Namespace}}
+		if err := deleteLabeled(ctx, memberClient, overlay, sliceOverlayLabel, sliceOverlay.Name); err != nil {
+			return true, false, err
+		}
+	}
+
+	return true, ned.Status.Availability != nil && *ned.Status.Availability == l2smv1.OnlineStatus, nil
+}
+
+// finalize deletes the NetworkEdgeDevice and Overlay from the clusters they were deployed in, as recorded in
+// the status, under the name of the current and the deployed provider. Clusters that can't be reached are
+// retried, and the finalizer is only removed once all of them are clean. Clusters that are no longer
+// registered count as clean.
+func (r *SliceOverlayReconciler) finalize(ctx context.Context, sliceOverlay *l2scesv1.SliceOverlay) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(sliceOverlay, sliceFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	nedNames := []string{newNEDGenerator(sliceOverlay.Spec.Provider).NEDName()}
+	if sliceOverlay.Status.NEDName != "" {
+		nedNames = uniqueNames(append(nedNames, sliceOverlay.Status.NEDName))
+	}
+
+	var failures []string
+	for _, clusterName := range deployedOverlayClusters(sliceOverlay) {
+		if err := r.cleanupCluster(ctx, sliceOverlay, clusterName, nedNames); err != nil {
+			failures = append(failures, fmt.Sprintf("cluster %s: %v", clusterName, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		sliceOverlay.Status.Phase = l2scesv1.SliceOverlayPhaseDeleting
+		meta.SetStatusCondition(&sliceOverlay.Status.Conditions, deletingCondition(failures, sliceOverlay.Generation))
+		if err := r.Status().Update(ctx, sliceOverlay); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueInterval}, nil
+	}
+
+	controllerutil.RemoveFinalizer(sliceOverlay, sliceFinalizer)
+	return ctrl.Result{}, r.Update(ctx, sliceOverlay)
+}
+
+// cleanupCluster deletes the objects of a SliceOverlay from a member cluster: its Overlay, and its
+// NetworkEdgeDevice under any of the given names. Nothing is left to delete from a cluster that is not
+// registered.
+func (r *SliceOverlayReconciler) cleanupCluster(ctx context.Context, sliceOverlay *l2scesv1.SliceOverlay, clusterName string, nedNames []string) error {
+	memberClient, err := r.MemberClusters.GetClient(ctx, clusterName)
+	if err != nil {
+		return ignoreNotRegistered(err)
+	}
+
+	for _, nedName := range nedNames {
+		ned := &l2smv1.NetworkEdgeDevice{ObjectMeta: metav1.ObjectMeta{Name: nedName, Namespace: sliceOverlay.Namespace}}
+		if err := deleteLabeled(ctx, memberClient, ned, sliceOverlayLabel, sliceOverlay.Name); err != nil {
+			return err
+		}
+	}
+	overlay := &l2smv1.Overlay{ObjectMeta: metav1.ObjectMeta{Name: sliceOverlay.Name, Namespace: sliceOverlay.Namespace}}
+	return deleteLabeled(ctx, memberClient, overlay, sliceOverlayLabel, sliceOverlay.Name)
+}
+
+// removeNED deletes the NetworkEdgeDevice of a SliceOverlay with the given name from a member cluster, if
+// the cluster is still registered.
+func (r *SliceOverlayReconciler) removeNED(ctx context.Context, sliceOverlay *l2scesv1.SliceOverlay, clusterName string, nedName string) error {
+	memberClient, err := r.MemberClusters.GetClient(ctx, clusterName)
+	if err != nil {
+		return ignoreNotRegistered(err)
+	}
+	ned := &l2smv1.NetworkEdgeDevice{ObjectMeta: metav1.ObjectMeta{Name: nedName, Namespace: sliceOverlay.Namespace}}
+	return deleteLabeled(ctx, memberClient, ned, sliceOverlayLabel, sliceOverlay.Name)
+}
+
+// deployedOverlayClusters returns the clusters holding objects of the SliceOverlay, as recorded in its status.
+func deployedOverlayClusters(sliceOverlay *l2scesv1.SliceOverlay) []string {
+	return uniqueNames(append(append([]string{}, sliceOverlay.Status.Clusters...), sliceOverlay.Status.OverlayClusters...))
+}
+
+// leftOverlayClusters returns the clusters holding objects of the SliceOverlay that are no longer part of it.
+func leftOverlayClusters(sliceOverlay *l2scesv1.SliceOverlay, clusterNames []string) []string {
+	left := []string{}
+	for _, clusterName := range deployedOverlayClusters(sliceOverlay) {
+		if !slices.Contains(clusterNames, clusterName) {
+			left = append(left, clusterName)
+		}
+	}
+	return left
+}
+
+// newNEDGenerator translates the provider of a SliceOverlay into a NEDGenerator.
+func newNEDGenerator(provider *l2smv1.ProviderSpec) *l2sminterface.NEDGenerator {
+	if provider == nil {
+		return l2sminterface.NewNEDGenerator(l2sminterface.SDNController{})
+	}
+	sdnController := l2sminterface.SDNController{
+		Name:        provider.Name,
+		SDNPort:     provider.SDNPort,
+		DNSPort:     provider.DNSPort,
+		OFPort:      provider.OFPort,
+		DNSGRPCPort: provider.DNSGRPCPort,
+	}
+	if len(provider.Domain) > 0 {
+		sdnController.Domain = provider.Domain[0]
+	}
+	return l2sminterface.NewNEDGenerator(sdnController)
+}
+
+// overlayLinks returns the links of the topology, the ones its strategy generates, or a full mesh between its
+// clusters if neither is given.
+func overlayLinks(topology *l2scesv1.OverlayTopology) ([]*l2sces.Link, error) {
+	if len(topology.Links) > 0 {
+		links := make([]*l2sces.Link, len(topology.Links))
+		for index, link := range topology.Links {
+			links[index] = &l2sces.Link{EndpointA: link.EndpointA, EndpointB: link.EndpointB}
+		}
+		return links, nil
+	}
+
+	slice := &l2sces.Slice{}
+	for _, cluster := range topology.Nodes {
+		slice.Clusters = append(slice.Clusters, &l2sces.Cluster{Name: cluster.Name, Region: cluster.Region})
+	}
+	if strategy := topology.Strategy; strategy != nil {
+		slice.Topology = &l2sces.Topology{Strategy: strategy.Type, Hub: strategy.Hub, Degree: strategy.Degree}
+	}
+	return topologygenerator.SliceLinks(slice)
+}
+
+// analyzeOverlay checks that the links join the clusters of the topology once each, and connect them all.
+func analyzeOverlay(topology *l2scesv1.OverlayTopology, links []*l2sces.Link) (*topologyanalysis.Analysis, error) {
+	clusterNames := make([]string, len(topology.Nodes))
+	for index, cluster := range topology.Nodes {
+		clusterNames[index] = cluster.Name
+	}
+	if errs := validation.ValidateLinks(links, clusterNames, field.NewPath("spec", "topology", "links")); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+	analysis := topologyanalysis.Analyze(clusterNames, links)
+	return analysis, analysis.Err()
+}
+
+// resilienceCondition tells whether the overlay survives the failure of any cluster or link.
+func resilienceCondition(analysis *topologyanalysis.Analysis, generation int64) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               conditionResilient,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             "NoSinglePointOfFailure",
+		Message:            fmt.Sprintf("no cluster or link splits the overlay, which is %d hops across", analysis.Diameter),
+	}
+	if warnings := analysis.Warnings(); len(warnings) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "SinglePointOfFailure"
+		condition.Message = strings.Join(warnings, "; ")
+	}
+	return condition
+}
+
+// overlayGateways maps every cluster of the topology to its gateway.
+func overlayGateways(topology *l2scesv1.OverlayTopology) map[string]l2sminterface.NodeConfig {
+	gateways := make(map[string]l2sminterface.NodeConfig)
+	for _, cluster := range topology.Nodes {
+		if cluster.Gateway != nil {
+			gateways[cluster.Name] = l2sminterface.NodeConfig{NodeName: cluster.Gateway.NodeName, IPAddress: cluster.Gateway.IPAddress}
+		}
+	}
+	return gateways
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *SliceOverlayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -19,9 +19,11 @@ package controller
 import (
 	"context"
 
+	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -38,11 +40,21 @@ var _ = Describe("SliceOverlay Controller", func() {
 
 		typeNamespacedName := types.NamespacedName{
 			Name:      resourceName,
-			Namespace: "default", // TODO(user):Modify as needed
+			Namespace: "default",
 		}
 		sliceoverlay := &l2scesv1.SliceOverlay{}
 
+		var memberClusters fakeMemberClusters
+		var controllerReconciler *SliceOverlayReconciler
+
 		BeforeEach(func() {
+			memberClusters = newFakeMemberClusters("cluster-a", "cluster-b", "cluster-c")
+			controllerReconciler = &SliceOverlayReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				MemberClusters: memberClusters,
+			}
+
 			By("creating the custom resource for the Kind SliceOverlay")
 			err := k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)
 			if err != nil && errors.IsNotFound(err) {
@@ -51,34 +63,269 @@ var _ = Describe("SliceOverlay Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: l2scesv1.SliceOverlaySpec{
+						Provider: &l2smv1.ProviderSpec{
+							Name:   "test-slice",
+							Domain: []string{"idco.example.com"},
+						},
+						Topology: &l2scesv1.OverlayTopology{
+							Nodes: []l2scesv1.OverlayCluster{
+								{Name: "cluster-a", Gateway: &l2smv1.NodeConfigSpec{NodeName: "node-a", IPAddress: "10.0.0.1"}, Nodes: []string{"node-a", "node-a2"}},
+								{Name: "cluster-b", Gateway: &l2smv1.NodeConfigSpec{NodeName: "node-b", IPAddress: "10.0.0.2"}},
+								{Name: "cluster-c", Gateway: &l2smv1.NodeConfigSpec{NodeName: "node-c", IPAddress: "10.0.0.3"}},
+							},
+							Links: []l2scesv1.OverlayLink{
+								{EndpointA: "cluster-a", EndpointB: "cluster-b"},
+								{EndpointA: "cluster-b", EndpointB: "cluster-c"},
+							},
+						},
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
 			resource := &l2scesv1.SliceOverlay{}
 			err := k8sClient.Get(ctx, typeNamespacedName, resource)
+			if errors.IsNotFound(err) {
+				return
+			}
 			Expect(err).NotTo(HaveOccurred())
 
 			By("Cleanup the specific resource instance SliceOverlay")
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, resource))).To(BeTrue())
 		})
-		It("should successfully reconcile the resource", func() {
+		It("should deploy a network edge device per cluster with its neighbors", func() {
 			By("Reconciling the created resource")
-			controllerReconciler := &SliceOverlayReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			nedName := types.NamespacedName{Name: "test-slice-ned", Namespace: "default"}
+			ned := &l2smv1.NetworkEdgeDevice{}
+			Expect(memberClusters["cluster-b"].Get(ctx, nedName, ned)).To(Succeed())
+			Expect(ned.Spec.NodeConfig.IPAddress).To(Equal("10.0.0.2"))
+			Expect(ned.Spec.Neighbors).To(ConsistOf(
+				l2smv1.NeighborSpec{Node: "cluster-a", Domain: "10.0.0.1"},
+				l2smv1.NeighborSpec{Node: "cluster-c", Domain: "10.0.0.3"},
+			))
+
+			Expect(memberClusters["cluster-c"].Get(ctx, nedName, ned)).To(Succeed())
+			Expect(ned.Spec.Neighbors).To(ConsistOf(l2smv1.NeighborSpec{Node: "cluster-b", Domain: "10.0.0.2"}))
+
+			By("deploying the overlay only where the cluster nodes are given")
+			overlay := &l2smv1.Overlay{}
+			Expect(memberClusters["cluster-a"].Get(ctx, typeNamespacedName, overlay)).To(Succeed())
+			Expect(overlay.Spec.Topology.Nodes).To(Equal([]string{"node-a", "node-a2"}))
+			Expect(errors.IsNotFound(memberClusters["cluster-b"].Get(ctx, typeNamespacedName, overlay))).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			Expect(sliceoverlay.Status.DeployedSwitches).To(Equal(int32(3)))
+			Expect(sliceoverlay.Status.Phase).To(Equal(l2scesv1.SliceOverlayPhasePending))
+
+			By("warning about the single points of failure of the line")
+			resilient := meta.FindStatusCondition(sliceoverlay.Status.Conditions, conditionResilient)
+			Expect(resilient.Status).To(Equal(metav1.ConditionFalse))
+			Expect(resilient.Message).To(ContainSubstring("cluster cluster-b is a single point of failure"))
+		})
+		It("should fail when a cluster can't be reached", func() {
+			partialReconciler := &SliceOverlayReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				MemberClusters: fakeMemberClusters{"cluster-a": memberClusters["cluster-a"], "cluster-b": memberClusters["cluster-b"]},
+			}
+
+			_, err := partialReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			Expect(sliceoverlay.Status.DeployedSwitches).To(Equal(int32(2)))
+			Expect(sliceoverlay.Status.Phase).To(Equal(l2scesv1.SliceOverlayPhaseFailed))
+		})
+		It("should link the clusters through the topology strategy", func() {
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			sliceoverlay.Spec.Topology.Links = nil
+			sliceoverlay.Spec.Topology.Strategy = &l2scesv1.TopologyStrategySpec{Type: "Star", Hub: "cluster-c"}
+			Expect(k8sClient.Update(ctx, sliceoverlay)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			nedName := types.NamespacedName{Name: "test-slice-ned", Namespace: "default"}
+			ned := &l2smv1.NetworkEdgeDevice{}
+			Expect(memberClusters["cluster-c"].Get(ctx, nedName, ned)).To(Succeed())
+			Expect(ned.Spec.Neighbors).To(ConsistOf(
+				l2smv1.NeighborSpec{Node: "cluster-a", Domain: "10.0.0.1"},
+				l2smv1.NeighborSpec{Node: "cluster-b", Domain: "10.0.0.2"},
+			))
+			Expect(memberClusters["cluster-a"].Get(ctx, nedName, ned)).To(Succeed())
+			Expect(ned.Spec.Neighbors).To(ConsistOf(l2smv1.NeighborSpec{Node: "cluster-c", Domain: "10.0.0.3"}))
+		})
+		It("should fail when the strategy can't link the clusters", func() {
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			sliceoverlay.Spec.Topology.Links = nil
+			sliceoverlay.Spec.Topology.Strategy = &l2scesv1.TopologyStrategySpec{Type: "KRegular", Degree: 3}
+			Expect(k8sClient.Update(ctx, sliceoverlay)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			Expect(sliceoverlay.Status.Phase).To(Equal(l2scesv1.SliceOverlayPhaseFailed))
+			Expect(meta.FindStatusCondition(sliceoverlay.Status.Conditions, conditionReady).Reason).To(Equal("InvalidTopology"))
+		})
+		It("should fail when the links leave a cluster out", func() {
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			sliceoverlay.Spec.Topology.Links = []l2scesv1.OverlayLink{{EndpointA: "cluster-a", EndpointB: "cluster-b"}}
+			Expect(k8sClient.Update(ctx, sliceoverlay)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			ready := meta.FindStatusCondition(sliceoverlay.Status.Conditions, conditionReady)
+			Expect(ready.Reason).To(Equal("InvalidTopology"))
+			Expect(ready.Message).To(ContainSubstring("[cluster-a cluster-b], [cluster-c]"))
+			Expect(errors.IsNotFound(memberClusters["cluster-a"].Get(ctx, types.NamespacedName{Name: "test-slice-ned", Namespace: "default"}, &l2smv1.NetworkEdgeDevice{}))).To(BeTrue())
+		})
+		It("should reject links and a strategy together", func() {
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			sliceoverlay.Spec.Topology.Strategy = &l2scesv1.TopologyStrategySpec{Type: "Ring"}
+			Expect(k8sClient.Update(ctx, sliceoverlay)).NotTo(Succeed())
+		})
+		It("should remove the slice from the clusters that leave it", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("dropping cluster-c and the nodes of cluster-a")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			Expect(sliceoverlay.Status.Clusters).To(ConsistOf("cluster-a", "cluster-b", "cluster-c"))
+			Expect(sliceoverlay.Status.OverlayClusters).To(ConsistOf("cluster-a"))
+			sliceoverlay.Spec.Topology.Nodes = sliceoverlay.Spec.Topology.Nodes[:2]
+			sliceoverlay.Spec.Topology.Nodes[0].Nodes = nil
+			sliceoverlay.Spec.Topology.Links = sliceoverlay.Spec.Topology.Links[:1]
+			Expect(k8sClient.Update(ctx, sliceoverlay)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			nedName := types.NamespacedName{Name: "test-slice-ned", Namespace: "default"}
+			Expect(errors.IsNotFound(memberClusters["cluster-c"].Get(ctx, nedName, &l2smv1.NetworkEdgeDevice{}))).To(BeTrue())
+			Expect(memberClusters["cluster-a"].Get(ctx, nedName, &l2smv1.NetworkEdgeDevice{})).To(Succeed())
+			Expect(errors.IsNotFound(memberClusters["cluster-a"].Get(ctx, typeNamespacedName, &l2smv1.Overlay{}))).To(BeTrue())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			Expect(sliceoverlay.Status.Clusters).To(ConsistOf("cluster-a", "cluster-b"))
+			Expect(sliceoverlay.Status.OverlayClusters).To(BeEmpty())
+		})
+		It("should replace the network edge devices when the provider is renamed", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			Expect(sliceoverlay.Status.NEDName).To(Equal("test-slice-ned"))
+			sliceoverlay.Spec.Provider.Name = "renamed-slice"
+			Expect(k8sClient.Update(ctx, sliceoverlay)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, clusterName := range []string{"cluster-a", "cluster-b", "cluster-c"} {
+				Expect(errors.IsNotFound(memberClusters[clusterName].Get(ctx, types.NamespacedName{Name: "test-slice-ned", Namespace: "default"}, &l2smv1.NetworkEdgeDevice{}))).To(BeTrue())
+				Expect(memberClusters[clusterName].Get(ctx, types.NamespacedName{Name: "renamed-slice-ned", Namespace: "default"}, &l2smv1.NetworkEdgeDevice{})).To(Succeed())
 			}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			Expect(sliceoverlay.Status.NEDName).To(Equal("renamed-slice-ned"))
+		})
+		It("should remove the network edge devices of clusters dropped before the deletion", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			sliceoverlay.Spec.Topology.Nodes = sliceoverlay.Spec.Topology.Nodes[:2]
+			sliceoverlay.Spec.Topology.Links = sliceoverlay.Spec.Topology.Links[:1]
+			sliceoverlay.Spec.Provider.Name = "renamed-slice"
+			Expect(k8sClient.Update(ctx, sliceoverlay)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, sliceoverlay)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
 
+			nedName := types.NamespacedName{Name: "test-slice-ned", Namespace: "default"}
+			for _, clusterName := range []string{"cluster-a", "cluster-b", "cluster-c"} {
+				Expect(errors.IsNotFound(memberClusters[clusterName].Get(ctx, nedName, &l2smv1.NetworkEdgeDevice{}))).To(BeTrue())
+			}
+			Expect(errors.IsNotFound(memberClusters["cluster-a"].Get(ctx, typeNamespacedName, &l2smv1.Overlay{}))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay))).To(BeTrue())
+		})
+		It("should remove the network edge devices and overlays on deletion", func() {
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
 				NamespacedName: typeNamespacedName,
 			})
 			Expect(err).NotTo(HaveOccurred())
-			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
-			// Example: If you expect a certain status condition after reconciliation, verify it here.
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, sliceoverlay)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			nedName := types.NamespacedName{Name: "test-slice-ned", Namespace: "default"}
+			for _, clusterName := range []string{"cluster-a", "cluster-b", "cluster-c"} {
+				Expect(errors.IsNotFound(memberClusters[clusterName].Get(ctx, nedName, &l2smv1.NetworkEdgeDevice{}))).To(BeTrue())
+			}
+			Expect(errors.IsNotFound(memberClusters["cluster-a"].Get(ctx, typeNamespacedName, &l2smv1.Overlay{}))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay))).To(BeTrue())
+		})
+		It("should not wait on deletion for clusters the slice was never deployed in", func() {
+			partialReconciler := &SliceOverlayReconciler{
+				Client:         k8sClient,
+				Scheme:         k8sClient.Scheme(),
+				MemberClusters: fakeMemberClusters{"cluster-a": memberClusters["cluster-a"], "cluster-c": nil},
+			}
+			_, err := partialReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay)).To(Succeed())
+			Expect(sliceoverlay.Status.Clusters).To(Equal([]string{"cluster-a"}))
+			Expect(k8sClient.Delete(ctx, sliceoverlay)).To(Succeed())
+
+			_, err = partialReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			nedName := types.NamespacedName{Name: "test-slice-ned", Namespace: "default"}
+			Expect(errors.IsNotFound(memberClusters["cluster-a"].Get(ctx, nedName, &l2smv1.NetworkEdgeDevice{}))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, sliceoverlay))).To(BeTrue())
 		})
 	})
 })
@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -66,7 +68,10 @@ var _ = BeforeSuite(func() {
 
 	By("bootstrapping test environment")
 	testEnv = &envtest.Environment{
-		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "config", "crd", "bases"),
+			filepath.Join("..", "..", "test", "crd", "ocm"),
+		},
 		ErrorIfCRDPathMissing: true,
 	}
 
@@ -114,3 +119,26 @@ func getFirstFoundEnvTestBinaryDir() string {
 	}
 	return ""
 }
+
+// fakeMemberClusters serves in-memory clients in place of the member clusters. Clusters registered with a nil
+// client can't be reached.
+type fakeMemberClusters map[string]client.Client
+
+func newFakeMemberClusters(clusterNames ...string) fakeMemberClusters {
+	clusters := fakeMemberClusters{}
+	for _, clusterName := range clusterNames {
+		clusters[clusterName] = fake.NewClientBuilder().WithScheme(MemberClusterScheme).Build()
+	}
+	return clusters
+}
+
+func (f fakeMemberClusters) GetClient(ctx context.Context, clusterName string) (client.Client, error) {
+	memberClient, ok := f[clusterName]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s is %w", clusterName, ErrNotRegistered)
+	}
+	if memberClient == nil {
+		return nil, fmt.Errorf("cluster %s can't be reached", clusterName)
+	}
+	return memberClient, nil
+}
@@ -15,7 +15,10 @@
 package env
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 func getEnv(key, defaultValue string) string {
@@ -40,3 +43,119 @@ func GetDefaultDNSPort() string {
 func GetDefaultOFPort() string {
 	return getEnv("DEFAULT_OF_PORT", "30663")
 }
+
+// GetRegistryStore returns where the gRPC server keeps its registry: "kubernetes" or "file".
+func GetRegistryStore() string {
+	return getEnv("REGISTRY_STORE", "kubernetes")
+}
+
+// GetRegistryNamespace returns the namespace of the registry Secrets when the kubernetes store is used.
+func GetRegistryNamespace() string {
+	return getEnv("REGISTRY_NAMESPACE", "default")
+}
+
+// GetRegistryPath returns the directory of the registry when the file store is used.
+func GetRegistryPath() string {
+	return getEnv("REGISTRY_PATH", "registry")
+}
+
+// GetKeepPartial tells whether objects created by a failed multi-cluster apply are kept instead of rolled back.
+func GetKeepPartial() bool {
+	return getEnv("KEEP_PARTIAL", "false") == "true"
+}
+
+// GetClusterParallelism returns how many member clusters are contacted at the same time. Zero means the default.
+func GetClusterParallelism() (int, error) {
+	value := getEnv("CLUSTER_PARALLELISM", "0")
+	parallelism, err := strconv.Atoi(value)
+	if err != nil || parallelism < 0 {
+		return 0, fmt.Errorf("invalid CLUSTER_PARALLELISM %q: expected a number of clusters", value)
+	}
+	return parallelism, nil
+}
+
+// GetClusterTimeout returns the time given to the calls made to a single member cluster. Zero means the default.
+func GetClusterTimeout() (time.Duration, error) {
+	return getDuration("CLUSTER_TIMEOUT")
+}
+
+// getDuration parses the duration of an environment variable, such as "30s". Zero if unset.
+func getDuration(key string) (time.Duration, error) {
+	value := getEnv(key, "0s")
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a duration such as \"30s\"", key, value)
+	}
+	return duration, nil
+}
+
+// GetTLSCertFile returns the certificate file of the gRPC server. TLS is disabled if neither it nor
+// GetTLSSecret is set.
+func GetTLSCertFile() string {
+	return getEnv("TLS_CERT_FILE", "")
+}
+
+// GetTLSKeyFile returns the key file of the gRPC server.
+func GetTLSKeyFile() string {
+	return getEnv("TLS_KEY_FILE", "")
+}
+
+// GetTLSClientCAFile returns the CA file that signs client certificates. Setting it enables mutual TLS.
+func GetTLSClientCAFile() string {
+	return getEnv("TLS_CLIENT_CA_FILE", "")
+}
+
+// GetTLSSecret returns the kubernetes.io/tls Secret holding the certificate and key of the gRPC server.
+func GetTLSSecret() string {
+	return getEnv("TLS_SECRET", "")
+}
+
+// GetTLSClientCASecret returns the Secret holding the client CA under ca.crt. Setting it enables mutual TLS.
+func GetTLSClientCASecret() string {
+	return getEnv("TLS_CLIENT_CA_SECRET", "")
+}
+
+// GetTLSSecretNamespace returns the namespace of the TLS Secrets, by default the one of the registry.
+func GetTLSSecretNamespace() string {
+	return getEnv("TLS_SECRET_NAMESPACE", GetRegistryNamespace())
+}
+
+// GetTLSReloadInterval returns how often the TLS certificate is checked for rotation. Zero means the default.
+func GetTLSReloadInterval() (time.Duration, error) {
+	return getDuration("TLS_RELOAD_INTERVAL")
+}
+
+// GetAuthorization returns how the gRPC server authorizes its callers: "kubernetes" or "policy". Callers are
+// neither authenticated nor authorized if empty.
+func GetAuthorization() string {
+	return getEnv("AUTHORIZATION", "")
+}
+
+// GetAuthorizationPolicyFile returns the policy file used by the "policy" authorization.
+func GetAuthorizationPolicyFile() string {
+	return getEnv("AUTHORIZATION_POLICY_FILE", "policy.yaml")
+}
+
+// GetClientType returns how member clusters are reached: "rest" through the credentials registered in the
+// management cluster, "kubeconfig" through the kubeconfigs of GetKubeconfigDir, or "ocm" through the
+// ManifestWorks of the management cluster, when it is an Open Cluster Management hub.
+func GetClientType() string {
+	return getEnv("CLIENT_TYPE", "rest")
+}
+
+// GetKubeconfigDir returns the directory of kubeconfigs used by the "kubeconfig" client type.
+func GetKubeconfigDir() string {
+	return getEnv("KUBECONFIG_DIR", "")
+}
+
+// GetCertNamespace returns the namespace of the l2sm-cert Secrets of the member clusters. Every namespace is
+// searched if empty.
+func GetCertNamespace() string {
+	return getEnv("CERT_NAMESPACE", "")
+}
+
+// GetLinkCostsFile returns the YAML file of the link costs between member clusters, used by slices with a
+// topology. No costs are known if empty.
+func GetLinkCostsFile() string {
+	return getEnv("LINK_COSTS_FILE", "")
+}
@@ -12,4 +12,173 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package ocminterface builds and reads the Open Cluster Management objects used to reach the member
+// clusters through an OCM hub. Only the fields used here are modeled, as unstructured objects.
 package ocminterface
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// ManagedClusterGVR is the cluster-scoped resource registering a member cluster in the hub. Its
+	// ManifestWorks live in the hub namespace named after it.
+	ManagedClusterGVR = schema.GroupVersionResource{Group: "cluster.open-cluster-management.io", Version: "v1", Resource: "managedclusters"}
+	// ManifestWorkGVR is the resource holding the objects the work agent of a member cluster applies in it.
+	ManifestWorkGVR = schema.GroupVersionResource{Group: "work.open-cluster-management.io", Version: "v1", Resource: "manifestworks"}
+	// PlacementDecisionGVK holds the clusters chosen by a Placement, which labels it with PlacementLabel.
+	PlacementDecisionGVK = schema.GroupVersionKind{Group: "cluster.open-cluster-management.io", Version: "v1beta1", Kind: "PlacementDecision"}
+)
+
+const (
+	// WorkApplied is the condition of a ManifestWork telling whether its objects were applied.
+	WorkApplied = "Applied"
+	// ManagedByLabel marks the ManifestWorks created by l2sces.
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	// PlacementLabel names the Placement a PlacementDecision belongs to.
+	PlacementLabel = "cluster.open-cluster-management.io/placement"
+)
+
+// ManifestWorkName returns the name of the ManifestWork wrapping an object, unique in its cluster.
+func ManifestWorkName(resource schema.GroupVersionResource, namespace string, name string) string {
+	return strings.ToLower(fmt.Sprintf("l2sces-%s-%s-%s", resource.Resource, namespace, name))
+}
+
+// ConstructManifestWork wraps an object in a ManifestWork for the given cluster. The work agent reports
+// back the feedback fields of the object, given as name and JSON path, like ".status.availability".
+func ConstructManifestWork(clusterName string, resource schema.GroupVersionResource, object *unstructured.Unstructured, feedback map[string]string) *unstructured.Unstructured {
+	work := &unstructured.Unstructured{}
+	work.SetAPIVersion(ManifestWorkGVR.GroupVersion().String())
+	work.SetKind("ManifestWork")
+	work.SetName(ManifestWorkName(resource, object.GetNamespace(), object.GetName()))
+	work.SetNamespace(clusterName)
+	work.SetLabels(map[string]string{ManagedByLabel: "l2sces"})
+
+	manifestConfig := map[string]interface{}{
+		"resourceIdentifier": map[string]interface{}{
+			"group":     resource.Group,
+			"resource":  resource.Resource,
+			"namespace": object.GetNamespace(),
+			"name":      object.GetName(),
+		},
+	}
+	if len(feedback) > 0 {
+		names := make([]string, 0, len(feedback))
+		for name := range feedback {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		jsonPaths := make([]interface{}, len(names))
+		for index, name := range names {
+			jsonPaths[index] = map[string]interface{}{"name": name, "path": feedback[name]}
+		}
+		manifestConfig["feedbackRules"] = []interface{}{
+			map[string]interface{}{"type": "JSONPaths", "jsonPaths": jsonPaths},
+		}
+	}
+
+	work.Object["spec"] = map[string]interface{}{
+		"workload":        map[string]interface{}{"manifests": []interface{}{object.DeepCopy().Object}},
+		"manifestConfigs": []interface{}{manifestConfig},
+	}
+	return work
+}
+
+// WorkStatus is the status a work agent reported for the current generation of a ManifestWork.
+type WorkStatus struct {
+	// Applied is the status of the Applied condition, Unknown until the agent reports it.
+	Applied metav1.ConditionStatus
+	// Message explains why the work was not applied.
+	Message string
+	// Feedback are the values of the feedback fields of the wrapped objects.
+	Feedback map[string]string
+}
+
+type manifestWorkStatus struct {
+	Conditions     []metav1.Condition `json:"conditions,omitempty"`
+	ResourceStatus struct {
+		Manifests []manifestCondition `json:"manifests,omitempty"`
+	} `json:"resourceStatus,omitempty"`
+}
+
+type manifestCondition struct {
+	Conditions     []metav1.Condition `json:"conditions,omitempty"`
+	StatusFeedback struct {
+		Values []feedbackValue `json:"values,omitempty"`
+	} `json:"statusFeedback,omitempty"`
+}
+
+type feedbackValue struct {
+	Name       string `json:"name"`
+	FieldValue struct {
+		Type    string  `json:"type"`
+		Integer *int64  `json:"integer,omitempty"`
+		String  *string `json:"string,omitempty"`
+		Boolean *bool   `json:"boolean,omitempty"`
+		JsonRaw *string `json:"jsonRaw,omitempty"`
+	} `json:"fieldValue"`
+}
+
+// GetWorkStatus reads the status of a ManifestWork. Conditions reported for an older generation of the
+// work are ignored, as they don't tell about its current objects.
+func GetWorkStatus(work *unstructured.Unstructured) (*WorkStatus, error) {
+	status := manifestWorkStatus{}
+	if content, found, _ := unstructured.NestedMap(work.Object, "status"); found {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &status); err != nil {
+			return nil, fmt.Errorf("invalid status of ManifestWork %s: %v", work.GetName(), err)
+		}
+	}
+
+	workStatus := &WorkStatus{Applied: metav1.ConditionUnknown, Feedback: map[string]string{}}
+	if condition := meta.FindStatusCondition(status.Conditions, WorkApplied); condition != nil && condition.ObservedGeneration == work.GetGeneration() {
+		workStatus.Applied = condition.Status
+		workStatus.Message = condition.Message
+	}
+	for _, manifest := range status.ResourceStatus.Manifests {
+		// The condition of the manifest tells better than the one of the work why it failed
+		if condition := meta.FindStatusCondition(manifest.Conditions, WorkApplied); condition != nil && condition.Status == metav1.ConditionFalse && condition.Message != "" {
+			workStatus.Message = condition.Message
+		}
+		for _, value := range manifest.StatusFeedback.Values {
+			workStatus.Feedback[value.Name] = value.String()
+		}
+	}
+	return workStatus, nil
+}
+
+func (value feedbackValue) String() string {
+	switch {
+	case value.FieldValue.String != nil:
+		return *value.FieldValue.String
+	case value.FieldValue.Integer != nil:
+		return strconv.FormatInt(*value.FieldValue.Integer, 10)
+	case value.FieldValue.Boolean != nil:
+		return strconv.FormatBool(*value.FieldValue.Boolean)
+	case value.FieldValue.JsonRaw != nil:
+		return *value.FieldValue.JsonRaw
+	}
+	return ""
+}
+
+// DecidedClusters returns the names of the clusters chosen in a PlacementDecision.
+func DecidedClusters(decision *unstructured.Unstructured) []string {
+	decisions, _, _ := unstructured.NestedSlice(decision.Object, "status", "decisions")
+	clusters := make([]string, 0, len(decisions))
+	for _, item := range decisions {
+		if entry, ok := item.(map[string]interface{}); ok {
+			if clusterName, _ := entry["clusterName"].(string); clusterName != "" {
+				clusters = append(clusters, clusterName)
+			}
+		}
+	}
+	return clusters
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocminterface
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TestConstructManifestWork checks that the object is wrapped in the namespace of its cluster, with the
+// feedback rules for its status.
+func TestConstructManifestWork(t *testing.T) {
+	resource := schema.GroupVersionResource{Group: "l2sm.l2sm.k8s.local", Version: "v1", Resource: "networkedgedevices"}
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion("l2sm.l2sm.k8s.local/v1")
+	object.SetKind("NetworkEdgeDevice")
+	object.SetName("idco-ned")
+	object.SetNamespace("l2sm-system")
+
+	work := ConstructManifestWork("edge-1", resource, object, map[string]string{"availability": ".status.availability"})
+	if work.GetNamespace() != "edge-1" || work.GetName() != "l2sces-networkedgedevices-l2sm-system-idco-ned" {
+		t.Errorf("unexpected ManifestWork %s/%s", work.GetNamespace(), work.GetName())
+	}
+	manifests, _, _ := unstructured.NestedSlice(work.Object, "spec", "workload", "manifests")
+	if len(manifests) != 1 || manifests[0].(map[string]interface{})["kind"] != "NetworkEdgeDevice" {
+		t.Errorf("expected the NED as only manifest, got %v", manifests)
+	}
+	configs, _, _ := unstructured.NestedSlice(work.Object, "spec", "manifestConfigs")
+	if name, _, _ := unstructured.NestedString(configs[0].(map[string]interface{}), "resourceIdentifier", "name"); name != "idco-ned" {
+		t.Errorf("expected the feedback rules to identify the NED, got %v", configs)
+	}
+}
+
+// TestGetWorkStatus checks that conditions of older generations are ignored and that feedback is read.
+func TestGetWorkStatus(t *testing.T) {
+	work := &unstructured.Unstructured{Object: map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{
+				"type": WorkApplied, "status": "False", "reason": "AppliedManifestFailed", "message": "failed",
+				"observedGeneration": int64(1), "lastTransitionTime": "2024-01-01T00:00:00Z",
+			}},
+			"resourceStatus": map[string]interface{}{"manifests": []interface{}{map[string]interface{}{
+				"statusFeedback": map[string]interface{}{"values": []interface{}{
+					map[string]interface{}{"name": "availability", "fieldValue": map[string]interface{}{"type": "String", "string": "Available"}},
+					map[string]interface{}{"name": "pods", "fieldValue": map[string]interface{}{"type": "Integer", "integer": int64(3)}},
+				}},
+			}}},
+		},
+	}}
+	work.SetGeneration(1)
+
+	status, err := GetWorkStatus(work)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.Applied != metav1.ConditionFalse || status.Message != "failed" {
+		t.Errorf("expected the work not to be applied, got %+v", status)
+	}
+	if status.Feedback["availability"] != "Available" || status.Feedback["pods"] != "3" {
+		t.Errorf("unexpected feedback %v", status.Feedback)
+	}
+
+	work.SetGeneration(2)
+	if status, _ := GetWorkStatus(work); status.Applied != metav1.ConditionUnknown {
+		t.Errorf("expected the condition of an older generation to be ignored, got %s", status.Applied)
+	}
+}
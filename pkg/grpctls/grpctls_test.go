@@ -0,0 +1,220 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpctls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testCA signs the certificates of the tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	ca := &testCA{}
+	ca.cert, ca.key, ca.pem = newCert(t, &x509.Certificate{
+		Subject: pkix.Name{CommonName: "test-ca"}, IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign,
+	}, nil)
+	return ca
+}
+
+// issue returns a PEM certificate and key signed by the CA, for a server if serverName is set.
+func (ca *testCA) issue(t *testing.T, commonName string, serverName string) ([]byte, []byte) {
+	template := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+	if serverName != "" {
+		template.DNSNames = []string{serverName}
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	}
+	_, key, certPEM := newCert(t, template, ca)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certPEM, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func newCert(t *testing.T, template *x509.Certificate, ca *testCA) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parent, signer := template, key
+	if ca != nil {
+		parent, signer = ca.cert, ca.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// handshake connects a client with the given configuration to a server using the reloader, and returns the
+// certificate the server presented.
+func handshake(t *testing.T, reloader *Reloader, clientConfig *tls.Config) (*x509.Certificate, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		_, _, err = reloader.Credentials().ServerHandshake(conn)
+		serverErr <- err
+	}()
+
+	clientConfig.NextProtos = []string{"h2"}
+	client, err := tls.Dial("tcp", listener.Addr().String(), clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	// With TLS 1.3 the client certificate is checked after the client handshake completes
+	if err := <-serverErr; err != nil {
+		return nil, err
+	}
+	return client.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestReloaderRotation(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	source := FileSource{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")}
+	certPEM, keyPEM := ca.issue(t, "first", "grpc-server")
+	writeFile(t, source.CertFile, certPEM)
+	writeFile(t, source.KeyFile, keyPEM)
+
+	ctx := context.Background()
+	reloader, err := NewReloader(ctx, source, 0)
+	if err != nil {
+		t.Fatalf("NewReloader failed: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "grpc-server"}
+
+	cert, err := handshake(t, reloader, clientConfig.Clone())
+	if err != nil || cert.Subject.CommonName != "first" {
+		t.Fatalf("expected the first certificate, got %v, %v", cert, err)
+	}
+
+	if reloaded, err := reloader.Reload(ctx); err != nil || reloaded {
+		t.Errorf("expected no reload without changes, got %v, %v", reloaded, err)
+	}
+
+	// A broken rotation keeps the previous certificate
+	writeFile(t, source.KeyFile, []byte("not a key"))
+	if _, err := reloader.Reload(ctx); err == nil {
+		t.Error("expected an invalid key to be rejected")
+	}
+
+	certPEM, keyPEM = ca.issue(t, "second", "grpc-server")
+	writeFile(t, source.CertFile, certPEM)
+	writeFile(t, source.KeyFile, keyPEM)
+	if reloaded, err := reloader.Reload(ctx); err != nil || !reloaded {
+		t.Fatalf("expected a reload, got %v, %v", reloaded, err)
+	}
+	cert, err = handshake(t, reloader, clientConfig.Clone())
+	if err != nil || cert.Subject.CommonName != "second" {
+		t.Errorf("expected the second certificate, got %v, %v", cert, err)
+	}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	certPEM, keyPEM := ca.issue(t, "server", "grpc-server")
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "grpc-server-tls", Namespace: "l2sm-system"},
+		Type:       corev1.SecretTypeTLS,
+		Data:       map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM, corev1.ServiceAccountRootCAKey: ca.pem},
+	}
+	clientset := fake.NewSimpleClientset(secret)
+	source := SecretSource{Clientset: clientset, Namespace: "l2sm-system", Name: "grpc-server-tls", ClientCAName: "grpc-server-tls"}
+
+	ctx := context.Background()
+	reloader, err := NewReloader(ctx, source, 0)
+	if err != nil {
+		t.Fatalf("NewReloader failed: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	if _, err := handshake(t, reloader, &tls.Config{RootCAs: roots, ServerName: "grpc-server"}); err == nil {
+		t.Error("expected a client without certificate to be rejected")
+	}
+
+	clientCertPEM, clientKeyPEM := ca.issue(t, "client", "")
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handshake(t, reloader, &tls.Config{RootCAs: roots, ServerName: "grpc-server", Certificates: []tls.Certificate{clientCert}}); err != nil {
+		t.Errorf("expected a client with certificate to be accepted, got %v", err)
+	}
+
+	// Removing the client CA must not turn mutual TLS off
+	delete(secret.Data, corev1.ServiceAccountRootCAKey)
+	if _, err := clientset.CoreV1().Secrets("l2sm-system").Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloader.Reload(ctx); err == nil {
+		t.Error("expected a secret without client CA to be rejected")
+	}
+}
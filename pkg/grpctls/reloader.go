@@ -0,0 +1,162 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpctls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// DefaultReloadInterval is how often the source is checked for a rotated certificate by default.
+const DefaultReloadInterval = time.Minute
+
+// Reloader serves the TLS configuration of the last bundle loaded from its source. Connections already
+// established keep the certificate they were opened with.
+type Reloader struct {
+	source   Source
+	interval time.Duration
+
+	mutex  sync.RWMutex
+	bundle *Bundle
+	config *tls.Config
+}
+
+// NewReloader loads the first bundle from the source, failing if it is not valid. A zero interval means
+// DefaultReloadInterval.
+func NewReloader(ctx context.Context, source Source, interval time.Duration) (*Reloader, error) {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	reloader := &Reloader{source: source, interval: interval}
+	if _, err := reloader.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// Run reloads the bundle every interval until ctx is done. A bundle that can't be loaded is logged and
+// the previous one is kept.
+func (reloader *Reloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(reloader.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if reloaded, err := reloader.Reload(ctx); err != nil {
+				log.Printf("Failed to reload TLS certificate, keeping the previous one: %v", err)
+			} else if reloaded {
+				log.Printf("Reloaded TLS certificate")
+			}
+		}
+	}
+}
+
+// Reload loads the bundle from the source, and tells whether it changed. Mutual TLS can't be turned off by
+// a reload: once the server asks for client certificates, a bundle without client CA is rejected.
+func (reloader *Reloader) Reload(ctx context.Context) (bool, error) {
+	bundle, err := reloader.source.Load(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	reloader.mutex.RLock()
+	current := reloader.bundle
+	reloader.mutex.RUnlock()
+	if current != nil && current.Equal(bundle) {
+		return false, nil
+	}
+	if current != nil && len(current.ClientCA) > 0 && len(bundle.ClientCA) == 0 {
+		return false, errors.New("the client CA was removed")
+	}
+
+	config, err := ServerConfig(bundle)
+	if err != nil {
+		return false, err
+	}
+	reloader.mutex.Lock()
+	reloader.bundle, reloader.config = bundle, config
+	reloader.mutex.Unlock()
+	return true, nil
+}
+
+// Credentials returns the transport credentials of a gRPC server using the current bundle.
+func (reloader *Reloader) Credentials() credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			reloader.mutex.RLock()
+			defer reloader.mutex.RUnlock()
+			return reloader.config, nil
+		},
+	})
+}
+
+// ServerConfig returns the TLS configuration of a server using the bundle. Clients must present a
+// certificate signed by the client CA, if the bundle has one.
+func ServerConfig(bundle *Bundle) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(bundle.Cert, bundle.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate or key: %v", err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2"},
+	}
+	if len(bundle.ClientCA) > 0 {
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(bundle.ClientCA) {
+			return nil, errors.New("invalid client CA: no PEM certificate found")
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// ClientConfig returns the TLS configuration of a client of the server. The server certificate is verified
+// against caFile, or the system roots if empty. certFile and keyFile are the client certificate, needed
+// when the server requires mutual TLS.
+func ClientConfig(caFile string, certFile string, keyFile string, serverName string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: serverName}
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA: %v", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, errors.New("invalid CA: no PEM certificate found")
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %v", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
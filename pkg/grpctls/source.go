@@ -0,0 +1,106 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpctls secures the gRPC endpoint of the server with TLS, or mutual TLS when a client CA is
+// given. The certificate is read from files or from a Secret, and reloaded when it is rotated.
+package grpctls
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Bundle holds the PEM encoded certificate and key of the server, and the CA that signs the certificates of
+// its clients. Without a client CA, clients are not asked for a certificate.
+type Bundle struct {
+	Cert     []byte
+	Key      []byte
+	ClientCA []byte
+}
+
+// Equal tells whether both bundles hold the same certificates.
+func (bundle *Bundle) Equal(other *Bundle) bool {
+	return bytes.Equal(bundle.Cert, other.Cert) && bytes.Equal(bundle.Key, other.Key) && bytes.Equal(bundle.ClientCA, other.ClientCA)
+}
+
+// Source loads the current Bundle.
+type Source interface {
+	Load(ctx context.Context) (*Bundle, error)
+}
+
+// FileSource reads the bundle from PEM files, such as the ones of a mounted Secret.
+type FileSource struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile enables mutual TLS if set.
+	ClientCAFile string
+}
+
+func (source FileSource) Load(ctx context.Context) (*Bundle, error) {
+	bundle := &Bundle{}
+	var err error
+	if bundle.Cert, err = os.ReadFile(source.CertFile); err != nil {
+		return nil, fmt.Errorf("error reading certificate: %v", err)
+	}
+	if bundle.Key, err = os.ReadFile(source.KeyFile); err != nil {
+		return nil, fmt.Errorf("error reading key: %v", err)
+	}
+	if source.ClientCAFile != "" {
+		if bundle.ClientCA, err = os.ReadFile(source.ClientCAFile); err != nil {
+			return nil, fmt.Errorf("error reading client CA: %v", err)
+		}
+	}
+	return bundle, nil
+}
+
+// SecretSource reads the bundle from a kubernetes.io/tls Secret, through the API server.
+type SecretSource struct {
+	Clientset kubernetes.Interface
+	Namespace string
+	// Name is the Secret holding the certificate and key of the server.
+	Name string
+	// ClientCAName is the Secret holding the client CA under ca.crt. It enables mutual TLS if set, and
+	// can be the same Secret as Name.
+	ClientCAName string
+}
+
+func (source SecretSource) Load(ctx context.Context) (*Bundle, error) {
+	secret, err := source.Clientset.CoreV1().Secrets(source.Namespace).Get(ctx, source.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting secret %s: %v", source.Name, err)
+	}
+	bundle := &Bundle{Cert: secret.Data[corev1.TLSCertKey], Key: secret.Data[corev1.TLSPrivateKeyKey]}
+	if len(bundle.Cert) == 0 || len(bundle.Key) == 0 {
+		return nil, fmt.Errorf("secret %s has no %s or %s", source.Name, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+	}
+
+	if source.ClientCAName != "" {
+		if source.ClientCAName != source.Name {
+			secret, err = source.Clientset.CoreV1().Secrets(source.Namespace).Get(ctx, source.ClientCAName, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("error getting secret %s: %v", source.ClientCAName, err)
+			}
+		}
+		if bundle.ClientCA = secret.Data[corev1.ServiceAccountRootCAKey]; len(bundle.ClientCA) == 0 {
+			return nil, fmt.Errorf("secret %s has no %s", source.ClientCAName, corev1.ServiceAccountRootCAKey)
+		}
+	}
+	return bundle, nil
+}
@@ -20,12 +20,16 @@ import (
 	"net"
 
 	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	l2scesv1 "github.com/Networks-it-uc3m/l2sc-es/api/v1"
 	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
 	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func ConstructL2NetworkFromL2smmd(network *l2sces.L2Network) (*l2smv1.L2Network, error) {
+	if network.GetProvider() == nil {
+		return nil, fmt.Errorf("network %s has no provider", network.GetName())
+	}
 
 	l2network := &l2smv1.L2Network{
 		TypeMeta: metav1.TypeMeta{
@@ -51,6 +55,28 @@ func ConstructL2NetworkFromL2smmd(network *l2sces.L2Network) (*l2smv1.L2Network,
 	return l2network, nil
 }
 
+// ConstructL2NetworkFromSliceNetwork builds the L2Network that a SliceNetwork places in each of its clusters.
+func ConstructL2NetworkFromSliceNetwork(sliceNetwork *l2scesv1.SliceNetwork) *l2smv1.L2Network {
+
+	l2network := &l2smv1.L2Network{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       GetKind(L2Network),
+			APIVersion: l2smv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sliceNetwork.Name,
+			Namespace: sliceNetwork.Namespace,
+		},
+		Spec: l2smv1.L2NetworkSpec{
+			Type: l2smv1.NetworkType(utils.DefaultIfEmpty(string(sliceNetwork.Spec.Type), "vnet")),
+		},
+	}
+	if sliceNetwork.Spec.Provider != nil {
+		l2network.Spec.Provider = sliceNetwork.Spec.Provider.DeepCopy()
+	}
+	return l2network
+}
+
 func ApplyCIDRs(networkCIDR string, l2network l2smv1.L2Network, numberClusters int) (*l2smv1.L2NetworkList, error) {
 	// Parse the input CIDR
 	ip, ipNet, err := net.ParseCIDR(networkCIDR)
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	l2scesv1 "github.com/Networks-it-uc3m/l2sc-es/api/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -141,3 +142,36 @@ func TestApplyCIDRs_Enhanced(t *testing.T) {
 		})
 	}
 }
+
+// TestConstructL2NetworkFromSliceNetwork checks that the type defaults to vnet and that the provider
+// is copied instead of shared with the SliceNetwork.
+func TestConstructL2NetworkFromSliceNetwork(t *testing.T) {
+	sliceNetwork := &l2scesv1.SliceNetwork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-network",
+			Namespace: "l2sm-system",
+		},
+		Spec: l2scesv1.SliceNetworkSpec{
+			Clusters: []string{"cluster-a", "cluster-b"},
+			Provider: &l2smv1.ProviderSpec{
+				Name:   "test-slice",
+				Domain: []string{"idco.example.com"},
+			},
+		},
+	}
+
+	l2network := ConstructL2NetworkFromSliceNetwork(sliceNetwork)
+
+	if l2network.Name != "test-network" || l2network.Namespace != "l2sm-system" {
+		t.Errorf("expected l2sm-system/test-network, got %s/%s", l2network.Namespace, l2network.Name)
+	}
+	if l2network.Spec.Type != "vnet" {
+		t.Errorf("expected default type vnet, got %s", l2network.Spec.Type)
+	}
+	if l2network.Spec.Provider == nil || l2network.Spec.Provider.Name != "test-slice" {
+		t.Fatalf("expected provider test-slice, got %v", l2network.Spec.Provider)
+	}
+	if l2network.Spec.Provider == sliceNetwork.Spec.Provider {
+		t.Errorf("expected the provider to be copied, not shared")
+	}
+}
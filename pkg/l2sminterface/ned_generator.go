@@ -16,6 +16,7 @@ package l2sminterface
 
 import (
 	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
 	"github.com/Networks-it-uc3m/l2sc-es/internal/env"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,6 +51,8 @@ type Neighbor struct {
 type NEDGenerator struct {
 	SliceName string
 	Provider  SDNController
+	// SwitchTemplate overrides the default NED switch pod when set.
+	SwitchTemplate *l2smv1.SwitchTemplateSpec
 }
 
 func NewNEDGenerator(sdnController SDNController) *NEDGenerator {
@@ -95,7 +98,7 @@ func (nedGenerator *NEDGenerator) ConstructNED(nedValues NEDValues) *l2smv1.Netw
 			APIVersion: l2smv1.GroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: nedGenerator.SliceName + "-ned",
+			Name: nedGenerator.NEDName(),
 		},
 		Spec: l2smv1.NetworkEdgeDeviceSpec{
 			Provider: &l2smv1.ProviderSpec{
@@ -111,13 +114,42 @@ func (nedGenerator *NEDGenerator) ConstructNED(nedValues NEDValues) *l2smv1.Netw
 				IPAddress: nedValues.NodeConfig.IPAddress,
 			},
 			Neighbors:      neighbors,
-			SwitchTemplate: defaultNEDTemplate(),
+			SwitchTemplate: nedGenerator.switchTemplate(),
 		},
 	}
 	return ned
 
 }
 
+// NEDName returns the name of the NetworkEdgeDevices of the slice.
+func (nedGenerator *NEDGenerator) NEDName() string {
+	return nedGenerator.SliceName + "-ned"
+}
+
+func (nedGenerator *NEDGenerator) switchTemplate() *l2smv1.SwitchTemplateSpec {
+	if nedGenerator.SwitchTemplate != nil {
+		return nedGenerator.SwitchTemplate.DeepCopy()
+	}
+	return defaultNEDTemplate()
+}
+
+// ComputeNeighbors returns the neighbors of every node from the links between them, reaching
+// each neighbor through the IP address of its gateway.
+func ComputeNeighbors(links []*l2sces.Link, gateways map[string]NodeConfig) map[string][]Neighbor {
+	neighbors := make(map[string][]Neighbor)
+	for _, link := range links {
+		neighbors[link.GetEndpointA()] = append(neighbors[link.GetEndpointA()], Neighbor{
+			Node:   link.GetEndpointB(),
+			Domain: gateways[link.GetEndpointB()].IPAddress,
+		})
+		neighbors[link.GetEndpointB()] = append(neighbors[link.GetEndpointB()], Neighbor{
+			Node:   link.GetEndpointA(),
+			Domain: gateways[link.GetEndpointA()].IPAddress,
+		})
+	}
+	return neighbors
+}
+
 func defaultNEDTemplate() *l2smv1.SwitchTemplateSpec {
 	return &l2smv1.SwitchTemplateSpec{
 		Spec: l2smv1.SwitchPodSpec{
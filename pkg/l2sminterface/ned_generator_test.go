@@ -0,0 +1,69 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package l2sminterface
+
+import (
+	"reflect"
+	"testing"
+
+	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// TestComputeNeighbors checks that every link shows up in the neighbors of both of its endpoints.
+func TestComputeNeighbors(t *testing.T) {
+	links := []*l2sces.Link{
+		{EndpointA: "a", EndpointB: "b"},
+		{EndpointA: "b", EndpointB: "c"},
+	}
+	gateways := map[string]NodeConfig{
+		"a": {NodeName: "node-a", IPAddress: "10.0.0.1"},
+		"b": {NodeName: "node-b", IPAddress: "10.0.0.2"},
+		"c": {NodeName: "node-c", IPAddress: "10.0.0.3"},
+	}
+
+	expected := map[string][]Neighbor{
+		"a": {{Node: "b", Domain: "10.0.0.2"}},
+		"b": {{Node: "a", Domain: "10.0.0.1"}, {Node: "c", Domain: "10.0.0.3"}},
+		"c": {{Node: "b", Domain: "10.0.0.2"}},
+	}
+
+	neighbors := ComputeNeighbors(links, gateways)
+	if !reflect.DeepEqual(neighbors, expected) {
+		t.Errorf("expected %v, got %v", expected, neighbors)
+	}
+}
+
+// TestConstructNEDSwitchTemplate checks that the generator's switch template replaces the default one.
+func TestConstructNEDSwitchTemplate(t *testing.T) {
+	nedGenerator := NewNEDGenerator(SDNController{Name: "test-slice", Domain: "idco.example.com"})
+
+	ned := nedGenerator.ConstructNED(NEDValues{NodeConfig: NodeConfig{NodeName: "node-a", IPAddress: "10.0.0.1"}})
+	if ned.Spec.SwitchTemplate.Spec.Containers[0].Image != SWITCH_DOCKER_IMAGE {
+		t.Errorf("expected default image %s, got %s", SWITCH_DOCKER_IMAGE, ned.Spec.SwitchTemplate.Spec.Containers[0].Image)
+	}
+
+	nedGenerator.SwitchTemplate = &l2smv1.SwitchTemplateSpec{}
+	nedGenerator.SwitchTemplate.Spec.Containers = append(nedGenerator.SwitchTemplate.Spec.Containers, defaultNEDTemplate().Spec.Containers[0])
+	nedGenerator.SwitchTemplate.Spec.Containers[0].Image = "custom/l2sm-switch:dev"
+
+	ned = nedGenerator.ConstructNED(NEDValues{NodeConfig: NodeConfig{NodeName: "node-a", IPAddress: "10.0.0.1"}})
+	if ned.Spec.SwitchTemplate.Spec.Containers[0].Image != "custom/l2sm-switch:dev" {
+		t.Errorf("expected custom image, got %s", ned.Spec.SwitchTemplate.Spec.Containers[0].Image)
+	}
+	if ned.Name != "test-slice-ned" {
+		t.Errorf("expected name test-slice-ned, got %s", ned.Name)
+	}
+}
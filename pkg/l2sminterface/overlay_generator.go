@@ -29,6 +29,9 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DefaultOverlayName is the name of the Overlays created from the gRPC requests.
+const DefaultOverlayName = "overlay-sample"
+
 type OverlayGenerator struct {
 	Values *l2smv1.TopologySpec
 }
@@ -41,7 +44,7 @@ func constructOverlayFromTopology(overlay *l2smv1.TopologySpec) (*l2smv1.Overlay
 			Kind:       "Overlay",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "overlay-sample",
+			Name: DefaultOverlayName,
 		},
 		Spec: l2smv1.OverlaySpec{
 			Provider: defaultProvider(),
@@ -137,7 +140,7 @@ func ConstructOverlayFromL2smmd(overlay *l2sces.Overlay) *l2smv1.Overlay {
 			APIVersion: l2smv1.GroupVersion.String(),
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name: "overlay-sample",
+			Name: DefaultOverlayName,
 		},
 		Spec: l2smv1.OverlaySpec{
 			Provider:       defaultProvider(),
@@ -151,6 +154,34 @@ func ConstructOverlayFromL2smmd(overlay *l2sces.Overlay) *l2smv1.Overlay {
 	return l2overlay
 }
 
+// ConstructOverlay builds the Overlay that connects the given nodes of a cluster in a full mesh.
+func ConstructOverlay(name string, namespace string, nodes []string) *l2smv1.Overlay {
+
+	links := []l2smv1.Link{}
+	for _, link := range topologygenerator.GenerateTopology(nodes) {
+		links = append(links, l2smv1.Link{EndpointA: link.GetEndpointA(), EndpointB: link.GetEndpointB()})
+	}
+
+	return &l2smv1.Overlay{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       GetKind(Overlay),
+			APIVersion: l2smv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: l2smv1.OverlaySpec{
+			Provider:       defaultProvider(),
+			SwitchTemplate: defaultSwitchTemplate(),
+			Topology: &l2smv1.TopologySpec{
+				Nodes: nodes,
+				Links: links,
+			},
+		},
+	}
+}
+
 func defaultSwitchTemplate() *l2smv1.SwitchTemplateSpec {
 	return &l2smv1.SwitchTemplateSpec{
 		Spec: l2smv1.SwitchPodSpec{
@@ -0,0 +1,141 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
+)
+
+// ClusterSource resolves the rest config of member clusters by name. It returns an error wrapping ErrNotFound
+// for clusters it doesn't know.
+type ClusterSource interface {
+	ClusterConfig(ctx context.Context, clusterName string) (*rest.Config, error)
+}
+
+// SecretClusterSource resolves member clusters from the l2sm-cert Secrets of the management cluster.
+type SecretClusterSource struct {
+	Store *operator.CertStore
+}
+
+// NewSecretClusterSource creates a source reading the Secrets of the namespace, or of every namespace if empty.
+func NewSecretClusterSource(managerClusterConfig *rest.Config, namespace string) (*SecretClusterSource, error) {
+	store, err := operator.NewCertStore(managerClusterConfig, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretClusterSource{Store: store}, nil
+}
+
+func (source *SecretClusterSource) ClusterConfig(ctx context.Context, clusterName string) (*rest.Config, error) {
+	credentials, err := source.Store.Get(ctx, clusterName)
+	if err != nil {
+		return nil, fmt.Errorf("could not get cluster credentials: %v", err)
+	}
+	if credentials == nil {
+		return nil, fmt.Errorf("%w: cluster %s is not registered", ErrNotFound, clusterName)
+	}
+	if len(credentials.Kubeconfig) > 0 {
+		return credentials.RestConfig()
+	}
+	// Clusters registered with only a CA certificate take the server and token from the request
+	return &rest.Config{
+		Host:        credentials.Server,
+		BearerToken: credentials.Token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData:   credentials.CAData,
+			CertData: credentials.CertData,
+			KeyData:  credentials.KeyData,
+		},
+	}, nil
+}
+
+// clusterSource returns the source of the member clusters, creating the default one on first use.
+func (restcli *RestClient) clusterSource() (ClusterSource, error) {
+	restcli.clustersOnce.Do(func() {
+		if restcli.Clusters != nil {
+			return
+		}
+		source, err := NewSecretClusterSource(&restcli.ManagerClusterConfig, "")
+		if err != nil {
+			restcli.clustersErr = err
+			return
+		}
+		restcli.Clusters = source
+	})
+	return restcli.Clusters, restcli.clustersErr
+}
+
+// newClusterClient creates a dynamic client for a cluster from its registered config. The server and
+// bearer token given in the request, if any, override the registered ones.
+func (restcli *RestClient) newClusterClient(ctx context.Context, cluster *l2sces.Cluster) (dynamic.Interface, error) {
+	source, err := restcli.clusterSource()
+	if err != nil {
+		return nil, err
+	}
+	registered, err := source.ClusterConfig(ctx, cluster.GetName())
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	clusterConfig, err := clusterRestConfig(cluster, registered)
+	if err != nil {
+		return nil, err
+	}
+	dynClient, err := dynamic.NewForConfig(clusterConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error contacting cluster %s: %v", ErrInvalidArgument, clusterConfig.String(), err)
+	}
+	return dynClient, nil
+}
+
+// clusterRestConfig overrides the registered config of a cluster, nil if it is not registered, with the
+// server and bearer token of the request. The registered credentials are only sent to the registered server:
+// a request pointing elsewhere keeps the registered CA, but has to bring its own token.
+func clusterRestConfig(cluster *l2sces.Cluster, registered *rest.Config) (*rest.Config, error) {
+	server := utils.DefaultIfEmpty(cluster.GetRestConfig().GetServer(), cluster.GetRestConfig().GetApiKey())
+	if registered == nil {
+		if server == "" {
+			return nil, fmt.Errorf("%w: cluster %s is not registered and the request has no API server for it", ErrNotFound, cluster.GetName())
+		}
+		registered = &rest.Config{}
+	}
+
+	clusterConfig := rest.CopyConfig(registered)
+	if server != "" && server != registered.Host {
+		clusterConfig = &rest.Config{Host: server, TLSClientConfig: rest.TLSClientConfig{CAData: registered.CAData, CAFile: registered.CAFile}}
+	}
+	clusterConfig.BearerToken = utils.DefaultIfEmpty(cluster.GetRestConfig().GetBearerToken(), clusterConfig.BearerToken)
+	if clusterConfig.Host == "" {
+		return nil, fmt.Errorf("%w: no API server for cluster %s", ErrInvalidArgument, cluster.GetName())
+	}
+	if !hasCredentials(clusterConfig) {
+		return nil, fmt.Errorf("%w: no credentials for cluster %s", ErrInvalidArgument, cluster.GetName())
+	}
+	return clusterConfig, nil
+}
+
+// hasCredentials tells whether the config authenticates with the API server in any way.
+func hasCredentials(config *rest.Config) bool {
+	return config.BearerToken != "" || config.BearerTokenFile != "" || config.ExecProvider != nil || config.AuthProvider != nil ||
+		len(config.CertData) > 0 || config.CertFile != "" || config.Username != ""
+}
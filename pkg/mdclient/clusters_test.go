@@ -0,0 +1,73 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// TestClusterRestConfig checks that registered configs are used and that the request overrides them.
+func TestClusterRestConfig(t *testing.T) {
+	registered := &rest.Config{Host: "https://registered:6443", BearerToken: "registered-token", TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")}}
+	exec := &rest.Config{Host: "https://kind:6443", ExecProvider: &clientcmdapi.ExecConfig{Command: "kubectl-oidc"}}
+
+	tests := []struct {
+		name          string
+		cluster       *l2sces.Cluster
+		registered    *rest.Config
+		expectedHost  string
+		expectedToken string
+	}{
+		{"registered", &l2sces.Cluster{Name: "a"}, registered, "https://registered:6443", "registered-token"},
+		{"token override", &l2sces.Cluster{Name: "a", RestConfig: &l2sces.RestConfig{BearerToken: "request-token"}}, registered, "https://registered:6443", "request-token"},
+		{"legacy api key", &l2sces.Cluster{Name: "a", RestConfig: &l2sces.RestConfig{ApiKey: "https://request:6443", BearerToken: "request-token"}},
+			nil, "https://request:6443", "request-token"},
+		{"server override", &l2sces.Cluster{Name: "a", RestConfig: &l2sces.RestConfig{Server: "https://request:6443", BearerToken: "request-token"}},
+			registered, "https://request:6443", "request-token"},
+		{"registered server", &l2sces.Cluster{Name: "a", RestConfig: &l2sces.RestConfig{Server: "https://registered:6443"}}, registered, "https://registered:6443", "registered-token"},
+		{"exec plugin", &l2sces.Cluster{Name: "a"}, exec, "https://kind:6443", ""},
+	}
+	for _, test := range tests {
+		clusterConfig, err := clusterRestConfig(test.cluster, test.registered)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if clusterConfig.Host != test.expectedHost || clusterConfig.BearerToken != test.expectedToken {
+			t.Errorf("%s: expected %s with %s, got %s with %s", test.name, test.expectedHost, test.expectedToken, clusterConfig.Host, clusterConfig.BearerToken)
+		}
+	}
+	if registered.BearerToken != "registered-token" {
+		t.Errorf("expected the registered config to be left untouched, got token %s", registered.BearerToken)
+	}
+
+	if _, err := clusterRestConfig(&l2sces.Cluster{Name: "a"}, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected an unregistered cluster to be not found, got %v", err)
+	}
+	if _, err := clusterRestConfig(&l2sces.Cluster{Name: "a"}, &rest.Config{Host: "https://registered:6443"}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected a cluster registered without credentials to be invalid, got %v", err)
+	}
+	// The registered credentials are never sent to a server of the request
+	other := &l2sces.Cluster{Name: "a", RestConfig: &l2sces.RestConfig{Server: "https://attacker:6443"}}
+	if _, err := clusterRestConfig(other, registered); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected a server override without a token to be invalid, got %v", err)
+	}
+}
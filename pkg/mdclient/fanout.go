@@ -0,0 +1,118 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultParallelism is the number of member clusters contacted at the same time by default.
+	DefaultParallelism = 8
+	// DefaultClusterTimeout is the time given by default to the calls made to a single member cluster.
+	DefaultClusterTimeout = 30 * time.Second
+)
+
+// errSkipped is returned for the clusters not contacted, or interrupted, because another cluster failed.
+var errSkipped = errors.New("skipped after a failure in another cluster")
+
+// fanOut runs calls against several member clusters concurrently.
+type fanOut struct {
+	parallelism int
+	timeout     time.Duration
+}
+
+func (restcli *RestClient) fanOut() fanOut {
+	return newFanOut(restcli.Parallelism, restcli.ClusterTimeout)
+}
+
+// newFanOut returns a fanOut with the given settings, or their defaults if not set.
+func newFanOut(parallelism int, timeout time.Duration) fanOut {
+	fan := fanOut{parallelism: parallelism, timeout: timeout}
+	if fan.parallelism <= 0 {
+		fan.parallelism = DefaultParallelism
+	}
+	if fan.timeout <= 0 {
+		fan.timeout = DefaultClusterTimeout
+	}
+	return fan
+}
+
+// run calls fn for every cluster index in [0, n), with at most parallelism calls at a time. Every call gets
+// its own deadline derived from ctx. With stopOnError, the first failure cancels the running calls and skips
+// the ones not started yet, which get errSkipped. The returned errors are in cluster order.
+func (fan fanOut) run(ctx context.Context, n int, stopOnError bool, fn func(ctx context.Context, index int) error) []error {
+	errs := make([]error, n)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mutex sync.Mutex
+	stopped := false
+
+	semaphore := make(chan struct{}, fan.parallelism)
+	var wg sync.WaitGroup
+	for index := 0; index < n; index++ {
+		// Both cases may be ready at once, so the context is checked again once a slot is taken
+		acquired := false
+		select {
+		case semaphore <- struct{}{}:
+			acquired = true
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			if acquired {
+				<-semaphore
+			}
+			mutex.Lock()
+			errs[index] = err
+			if stopped {
+				errs[index] = errSkipped
+			}
+			mutex.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			clusterCtx, clusterCancel := context.WithTimeout(ctx, fan.timeout)
+			defer clusterCancel()
+
+			err := fn(clusterCtx, index)
+			if err == nil {
+				return
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			if stopped && errors.Is(err, context.Canceled) {
+				err = errSkipped
+			}
+			errs[index] = err
+			if stopOnError && !stopped {
+				stopped = true
+				cancel()
+			}
+		}(index)
+	}
+	wg.Wait()
+	return errs
+}
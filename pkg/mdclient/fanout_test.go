@@ -0,0 +1,96 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestFanOutParallelism checks that no more than the configured number of calls run at the same time.
+func TestFanOutParallelism(t *testing.T) {
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+
+	fan := fanOut{parallelism: 3, timeout: time.Minute}
+	errs := fan.run(context.Background(), 10, false, func(ctx context.Context, index int) error {
+		mutex.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return nil
+	})
+
+	if err := errors.Join(errs...); err != nil {
+		t.Errorf("expected no errors, got %v", err)
+	}
+	if maxRunning != 3 {
+		t.Errorf("expected 3 calls at the same time, got %d", maxRunning)
+	}
+}
+
+// TestFanOutTimeout checks that a slow cluster fails on its own deadline without holding the others.
+func TestFanOutTimeout(t *testing.T) {
+	fan := fanOut{parallelism: 2, timeout: 20 * time.Millisecond}
+	start := time.Now()
+	errs := fan.run(context.Background(), 2, false, func(ctx context.Context, index int) error {
+		if index == 0 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	})
+
+	if !errors.Is(errs[0], context.DeadlineExceeded) {
+		t.Errorf("expected the slow cluster to exceed its deadline, got %v", errs[0])
+	}
+	if errs[1] != nil {
+		t.Errorf("expected the other cluster to succeed, got %v", errs[1])
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the fan out to end on the cluster deadline, took %v", elapsed)
+	}
+}
+
+// TestFanOutStopOnError checks that the first failure cancels running calls and skips the pending ones.
+func TestFanOutStopOnError(t *testing.T) {
+	fan := fanOut{parallelism: 2, timeout: time.Minute}
+	failure := errors.New("cluster unavailable")
+	errs := fan.run(context.Background(), 4, true, func(ctx context.Context, index int) error {
+		if index == 0 {
+			return failure
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if !errors.Is(errs[0], failure) {
+		t.Errorf("expected the failure of the first cluster, got %v", errs[0])
+	}
+	for index, err := range errs[1:] {
+		if !errors.Is(err, errSkipped) {
+			t.Errorf("expected cluster %d to be skipped, got %v", index+1, err)
+		}
+	}
+}
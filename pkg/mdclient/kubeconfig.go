@@ -0,0 +1,170 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	// Kubeconfigs of managed clusters often authenticate through OIDC
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+)
+
+// kubeconfigReloadDelay groups the changes made to the kubeconfig directory at once into a single reload.
+const kubeconfigReloadDelay = 500 * time.Millisecond
+
+// KubeconfigDirSource resolves member clusters from a directory of kubeconfig files. Every context of every
+// file is a cluster named after the context. The current context of each file is also named after the file,
+// without its extension, unless a context already has that name. Hidden files are ignored.
+type KubeconfigDirSource struct {
+	Dir string
+
+	mutex   sync.RWMutex
+	configs map[string]*rest.Config
+}
+
+// NewKubeconfigDirSource reads the kubeconfigs of the directory.
+func NewKubeconfigDirSource(dir string) (*KubeconfigDirSource, error) {
+	source := &KubeconfigDirSource{Dir: dir}
+	if err := source.Reload(); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func (source *KubeconfigDirSource) ClusterConfig(ctx context.Context, clusterName string) (*rest.Config, error) {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+	config, exists := source.configs[clusterName]
+	if !exists {
+		return nil, fmt.Errorf("%w: cluster %s has no kubeconfig in %s", ErrNotFound, clusterName, source.Dir)
+	}
+	return rest.CopyConfig(config), nil
+}
+
+// Clusters returns the names of the clusters found in the directory.
+func (source *KubeconfigDirSource) Clusters() []string {
+	source.mutex.RLock()
+	defer source.mutex.RUnlock()
+	names := make([]string, 0, len(source.configs))
+	for name := range source.configs {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Reload reads the kubeconfigs of the directory again. If any of them is invalid, the previous ones are kept.
+func (source *KubeconfigDirSource) Reload() error {
+	configs, err := readKubeconfigDir(source.Dir)
+	if err != nil {
+		return err
+	}
+	source.mutex.Lock()
+	source.configs = configs
+	source.mutex.Unlock()
+	return nil
+}
+
+// Watch reloads the kubeconfigs whenever the directory changes, until ctx is done.
+func (source *KubeconfigDirSource) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("could not watch %s: %v", source.Dir, err)
+	}
+	defer watcher.Close()
+	if err := watcher.Add(source.Dir); err != nil {
+		return fmt.Errorf("could not watch %s: %v", source.Dir, err)
+	}
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op != fsnotify.Chmod {
+				reload = time.After(kubeconfigReloadDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Error watching %s: %v", source.Dir, err)
+		case <-reload:
+			reload = nil
+			if err := source.Reload(); err != nil {
+				log.Printf("Failed to reload the kubeconfigs in %s, keeping the previous ones: %v", source.Dir, err)
+			} else {
+				log.Printf("Reloaded the kubeconfigs in %s: %v", source.Dir, source.Clusters())
+			}
+		}
+	}
+}
+
+func readKubeconfigDir(dir string) (map[string]*rest.Config, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get kube config files in %s: %v", dir, err)
+	}
+
+	configs := map[string]*rest.Config{}
+	origins := map[string]string{}
+	currentContexts := map[string]*rest.Config{}
+	for _, entry := range entries {
+		// Mounted Secrets and ConfigMaps keep their data in hidden directories
+		if strings.HasPrefix(entry.Name(), ".") || entry.IsDir() {
+			continue
+		}
+		file := filepath.Join(dir, entry.Name())
+		kubeconfig, err := clientcmd.LoadFromFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig %s: %v", entry.Name(), err)
+		}
+
+		for contextName := range kubeconfig.Contexts {
+			if origin, exists := origins[contextName]; exists {
+				return nil, fmt.Errorf("context %s is in both %s and %s", contextName, origin, entry.Name())
+			}
+			config, err := clientcmd.NewNonInteractiveClientConfig(*kubeconfig, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+			if err != nil {
+				return nil, fmt.Errorf("failed to build config of context %s in %s: %v", contextName, entry.Name(), err)
+			}
+			configs[contextName], origins[contextName] = config, entry.Name()
+		}
+		if current, exists := configs[kubeconfig.CurrentContext]; exists && origins[kubeconfig.CurrentContext] == entry.Name() {
+			currentContexts[strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))] = current
+		}
+	}
+
+	for fileName, config := range currentContexts {
+		if _, exists := configs[fileName]; !exists {
+			configs[fileName] = config
+		}
+	}
+	return configs, nil
+}
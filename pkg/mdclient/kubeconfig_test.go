@@ -0,0 +1,122 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// kindKubeconfig returns a kubeconfig with a context per cluster name, the first one being the current.
+func kindKubeconfig(names ...string) string {
+	kubeconfig := "apiVersion: v1\nkind: Config\ncurrent-context: " + names[0] + "\nclusters:\n"
+	for _, name := range names {
+		kubeconfig += fmt.Sprintf("- name: %s\n  cluster:\n    server: https://%s:6443\n", name, name)
+	}
+	kubeconfig += "users:\n- name: oidc\n  user:\n    exec:\n      apiVersion: client.authentication.k8s.io/v1\n      command: kubectl\n      args: [oidc-login, get-token]\n      interactiveMode: Never\ncontexts:\n"
+	for _, name := range names {
+		kubeconfig += fmt.Sprintf("- name: %s\n  context:\n    cluster: %s\n    user: oidc\n", name, name)
+	}
+	return kubeconfig
+}
+
+// TestKubeconfigDirSource checks that clusters are found by context and file name, and that hidden files are
+// skipped.
+func TestKubeconfigDirSource(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "edge.yaml"), []byte(kindKubeconfig("kind-edge-1", "kind-edge-2")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "core"), []byte(kindKubeconfig("kind-core")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".hidden"), []byte("not a kubeconfig"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewKubeconfigDirSource(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"kind-edge-1": "https://kind-edge-1:6443",
+		"kind-edge-2": "https://kind-edge-2:6443",
+		"edge":        "https://kind-edge-1:6443",
+		"kind-core":   "https://kind-core:6443",
+		"core":        "https://kind-core:6443",
+	}
+	for name, host := range expected {
+		config, err := source.ClusterConfig(context.Background(), name)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if config.Host != host || config.ExecProvider == nil {
+			t.Errorf("%s: expected %s with an exec plugin, got %s with %v", name, host, config.Host, config.ExecProvider)
+		}
+	}
+	if clusters := source.Clusters(); len(clusters) != len(expected) {
+		t.Errorf("expected %d clusters, got %v", len(expected), clusters)
+	}
+	if _, err := source.ClusterConfig(context.Background(), "unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected an unknown cluster to be not found, got %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "duplicate.yaml"), []byte(kindKubeconfig("kind-core")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Reload(); err == nil {
+		t.Errorf("expected a context defined twice to be rejected")
+	}
+	if _, err := source.ClusterConfig(context.Background(), "kind-core"); err != nil {
+		t.Errorf("expected the previous kubeconfigs to be kept, got %v", err)
+	}
+}
+
+// TestKubeconfigDirSourceWatch checks that kubeconfigs added to the directory are picked up.
+func TestKubeconfigDirSourceWatch(t *testing.T) {
+	dir := t.TempDir()
+	source, err := NewKubeconfigDirSource(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watching := make(chan error, 1)
+	go func() { watching <- source.Watch(ctx) }()
+
+	// The watch may not be set up yet, so keep writing until the cluster shows up
+	deadline := time.Now().Add(10 * time.Second)
+	for !slices.Contains(source.Clusters(), "kind-late") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the new kubeconfig to be loaded, got %v", source.Clusters())
+		}
+		if err := os.WriteFile(filepath.Join(dir, "late.yaml"), []byte(kindKubeconfig("kind-late")), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(2 * kubeconfigReloadDelay)
+	}
+
+	cancel()
+	if err := <-watching; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
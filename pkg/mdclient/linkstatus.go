@@ -0,0 +1,70 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+
+	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
+)
+
+// LinkStatusReader is implemented by the clients that can read the live status of the links of a slice.
+type LinkStatusReader interface {
+	// ConnectedNeighbors returns, for each cluster of the slice, the neighbors its NetworkEdgeDevice reports
+	// being connected to. Clusters whose NetworkEdgeDevice can't be read are left out.
+	ConnectedNeighbors(ctx context.Context, slice *l2sces.Slice, namespace string) map[string][]string
+}
+
+func (restcli *RestClient) ConnectedNeighbors(ctx context.Context, slice *l2sces.Slice, namespace string) map[string][]string {
+	namespace = utils.DefaultIfEmpty(namespace, "default")
+	nedName := newSliceNEDGenerator(slice.GetProvider()).NEDName()
+	resource := l2sminterface.GetGVR(l2sminterface.NetworkEdgeDevice)
+
+	clusters := slice.GetClusters()
+	connected := make([][]string, len(clusters))
+	restcli.fanOut().run(ctx, len(clusters), false, func(ctx context.Context, index int) error {
+		dynClient, err := restcli.newClusterClient(ctx, clusters[index])
+		if err != nil {
+			return err
+		}
+		obj, err := dynClient.Resource(resource).Namespace(namespace).Get(ctx, nedName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		ned := &l2smv1.NetworkEdgeDevice{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, ned); err != nil {
+			return err
+		}
+		connected[index] = []string{}
+		for _, neighbor := range ned.Status.ConnectedNeighbors {
+			connected[index] = append(connected[index], neighbor.Node)
+		}
+		return nil
+	})
+
+	neighbors := make(map[string][]string)
+	for index, cluster := range clusters {
+		if connected[index] != nil {
+			neighbors[cluster.GetName()] = connected[index]
+		}
+	}
+	return neighbors
+}
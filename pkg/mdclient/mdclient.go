@@ -15,9 +15,14 @@
 package mdclient
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
 
 	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/internal/env"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 )
 
@@ -25,30 +30,91 @@ type ClientType string
 
 const (
 	RestType ClientType = "rest"
+	// KubeconfigType resolves member clusters from a directory of kubeconfigs instead of the management cluster
+	KubeconfigType ClientType = "kubeconfig"
+	// OCMType reaches member clusters through the ManifestWorks of an Open Cluster Management hub
+	OCMType ClientType = "ocm"
 )
 
 type MDClient interface {
-	CreateNetwork(network *l2sces.L2Network, namespace string) error
-	DeleteNetwork(network *l2sces.L2Network, namespace string) error
-	CreateSlice(slice *l2sces.Slice, namespace string) error
-	DeleteSlice(slice *l2sces.Slice, namespace string) error
+	CreateNetwork(ctx context.Context, network *l2sces.L2Network, namespace string) ([]ClusterResult, error)
+	DeleteNetwork(ctx context.Context, network *l2sces.L2Network, namespace string) ([]ClusterResult, error)
+	CreateSlice(ctx context.Context, slice *l2sces.Slice, namespace string) ([]ClusterResult, error)
+	DeleteSlice(ctx context.Context, slice *l2sces.Slice, namespace string) ([]ClusterResult, error)
+	CreateOverlay(ctx context.Context, overlay *l2sces.Overlay, clusters []*l2sces.Cluster, namespace string) ([]ClusterResult, error)
+	AddCluster(ctx context.Context, slice *l2sces.Slice, cluster *l2sces.Cluster, links []*l2sces.Link, namespace string) error
+	RemoveCluster(ctx context.Context, slice *l2sces.Slice, clusterName string, namespace string) error
+	// UpdateSlice takes a running slice to the desired clusters and links, touching only the clusters that change.
+	UpdateSlice(ctx context.Context, current *l2sces.Slice, desired *l2sces.Slice, namespace string) error
+	DeleteOverlay(ctx context.Context, slice *l2sces.Slice, namespace string) ([]ClusterResult, error)
+}
+
+// memberClients is how a client reaches the member clusters. The RestClient calls their API servers, while
+// the OCMClient goes through the ManifestWorks of an Open Cluster Management hub.
+type memberClients interface {
+	fanOut() fanOut
+	newTransaction() *transaction
+	// newPlan returns an empty plan of the objects to apply in the cluster.
+	newPlan(ctx context.Context, cluster *l2sces.Cluster) (*clusterPlan, error)
+	deleteObject(ctx context.Context, cluster *l2sces.Cluster, resource schema.GroupVersionResource, namespace string, name string) error
 }
 
 func NewClient(clientType ClientType, config ...interface{}) (MDClient, error) {
 
+	clusterConfig := rest.Config{}
+	kubeconfigDir := ""
+	// Convert each element in the config slice to rest.Config or the kubeconfig directory
+	for _, cfg := range config {
+		switch c := cfg.(type) {
+		case *rest.Config:
+			clusterConfig = *c
+		case string:
+			kubeconfigDir = c
+		}
+	}
+
+	parallelism, err := env.GetClusterParallelism()
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := env.GetClusterTimeout()
+	if err != nil {
+		return nil, err
+	}
+
 	switch clientType {
 	case RestType:
-		clusterConfig := rest.Config{}
-		// Convert each element in the config slice to rest.Config
-		for _, cfg := range config {
-			// Assert that cfg is of type rest.Config
-			if c, ok := cfg.(*rest.Config); ok {
-				clusterConfig = *c
-			}
+		client := &RestClient{ManagerClusterConfig: clusterConfig, KeepPartial: env.GetKeepPartial(),
+			Parallelism: parallelism, ClusterTimeout: timeout}
+		source, err := NewSecretClusterSource(&clusterConfig, env.GetCertNamespace())
+		if err != nil {
+			return nil, err
+		}
+		client.Clusters = source
+		return client, nil
+	case KubeconfigType:
+		if kubeconfigDir == "" {
+			return nil, errors.New("no kubeconfig directory given")
 		}
-		client := &RestClient{ManagerClusterConfig: clusterConfig}
+		source, err := NewKubeconfigDirSource(kubeconfigDir)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Loaded the kubeconfigs in %s: %v", kubeconfigDir, source.Clusters())
+		go func() {
+			if err := source.Watch(context.Background()); err != nil {
+				log.Printf("Kubeconfigs in %s won't be reloaded: %v", kubeconfigDir, err)
+			}
+		}()
+		client := &RestClient{ManagerClusterConfig: clusterConfig, Clusters: source, KeepPartial: env.GetKeepPartial(),
+			Parallelism: parallelism, ClusterTimeout: timeout}
+		return client, nil
+	case OCMType:
+		// The management cluster is the hub
+		client := &OCMClient{HubClusterConfig: clusterConfig, KeepPartial: env.GetKeepPartial(),
+			Parallelism: parallelism, ClusterTimeout: timeout}
 		return client, nil
 	default:
-		return nil, errors.New("unsupported client type")
+		return nil, fmt.Errorf("unsupported client type %q", clientType)
 	}
 }
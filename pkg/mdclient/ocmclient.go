@@ -0,0 +1,180 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/internal/ocminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+)
+
+// DefaultWorkPollInterval is how often a ManifestWork is checked by default while waiting for its work agent.
+const DefaultWorkPollInterval = time.Second
+
+// workFeedback are the status fields the work agents report back for every kind of object, by name and
+// JSON path.
+var workFeedback = map[string]map[string]string{
+	l2sminterface.GetKind(l2sminterface.L2Network): {
+		"internalConnectivity": ".status.internalConnectivity",
+		"providerConnectivity": ".status.providerConnectivity",
+	},
+	l2sminterface.GetKind(l2sminterface.NetworkEdgeDevice): {
+		"availability": ".status.availability",
+		"openflowId":   ".status.openflowId",
+	},
+}
+
+// OCMClient reaches the member clusters through an Open Cluster Management hub instead of their API servers.
+// Every object is wrapped in its own ManifestWork, in the hub namespace of the ManagedCluster named like the
+// cluster, and an apply only succeeds once the work agent of the cluster has applied it.
+type OCMClient struct {
+	HubClusterConfig rest.Config
+	// KeepPartial leaves the ManifestWorks created by a failed apply in place, annotated with
+	// PartialAnnotation, instead of rolling them back.
+	KeepPartial bool
+	// Parallelism is the number of clusters handled at the same time. Defaults to DefaultParallelism.
+	Parallelism int
+	// ClusterTimeout bounds the calls made for a single cluster, including the wait for its work agent.
+	// Defaults to DefaultClusterTimeout.
+	ClusterTimeout time.Duration
+	// PollInterval is how often ManifestWorks are checked while waiting for the work agent. Defaults to
+	// DefaultWorkPollInterval.
+	PollInterval time.Duration
+}
+
+func (ocmcli *OCMClient) CreateNetwork(ctx context.Context, network *l2sces.L2Network, namespace string) ([]ClusterResult, error) {
+	return createNetwork(ctx, ocmcli, network, namespace)
+}
+
+func (ocmcli *OCMClient) DeleteNetwork(ctx context.Context, network *l2sces.L2Network, namespace string) ([]ClusterResult, error) {
+	return deleteNetwork(ctx, ocmcli, network, namespace)
+}
+
+func (ocmcli *OCMClient) CreateSlice(ctx context.Context, slice *l2sces.Slice, namespace string) ([]ClusterResult, error) {
+	return createSlice(ctx, ocmcli, slice, namespace)
+}
+
+func (ocmcli *OCMClient) DeleteSlice(ctx context.Context, slice *l2sces.Slice, namespace string) ([]ClusterResult, error) {
+	return deleteSlice(ctx, ocmcli, slice, namespace)
+}
+
+func (ocmcli *OCMClient) CreateOverlay(ctx context.Context, overlay *l2sces.Overlay, clusters []*l2sces.Cluster, namespace string) ([]ClusterResult, error) {
+	return createOverlay(ctx, ocmcli, overlay, clusters, namespace)
+}
+
+func (ocmcli *OCMClient) AddCluster(ctx context.Context, slice *l2sces.Slice, cluster *l2sces.Cluster, links []*l2sces.Link, namespace string) error {
+	return addCluster(ctx, ocmcli, slice, cluster, links, namespace)
+}
+
+func (ocmcli *OCMClient) RemoveCluster(ctx context.Context, slice *l2sces.Slice, clusterName string, namespace string) error {
+	return removeCluster(ctx, ocmcli, slice, clusterName, namespace)
+}
+
+func (ocmcli *OCMClient) UpdateSlice(ctx context.Context, current *l2sces.Slice, desired *l2sces.Slice, namespace string) error {
+	return updateSlice(ctx, ocmcli, current, desired, namespace)
+}
+
+func (ocmcli *OCMClient) DeleteOverlay(ctx context.Context, slice *l2sces.Slice, namespace string) ([]ClusterResult, error) {
+	return deleteSlice(ctx, ocmcli, slice, namespace)
+}
+
+func (ocmcli *OCMClient) fanOut() fanOut {
+	return newFanOut(ocmcli.Parallelism, ocmcli.ClusterTimeout)
+}
+
+func (ocmcli *OCMClient) newTransaction() *transaction {
+	return &transaction{fanOut: ocmcli.fanOut(), keepPartial: ocmcli.KeepPartial}
+}
+
+// newPlan returns a plan applying the objects as ManifestWorks of the ManagedCluster. The rest config of
+// the cluster given in the request is not used.
+func (ocmcli *OCMClient) newPlan(ctx context.Context, cluster *l2sces.Cluster) (*clusterPlan, error) {
+	hubClient, err := dynamic.NewForConfig(&ocmcli.HubClusterConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error contacting hub: %v", err)
+	}
+	_, err = hubClient.Resource(ocminterface.ManagedClusterGVR).Get(ctx, cluster.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: cluster %s is not a ManagedCluster of the hub", ErrNotFound, cluster.GetName())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting ManagedCluster %s: %w", cluster.GetName(), err)
+	}
+
+	return &clusterPlan{
+		cluster:   cluster.GetName(),
+		dynClient: hubClient,
+		wrap: func(obj plannedObject) plannedObject {
+			work := ocminterface.ConstructManifestWork(cluster.GetName(), obj.resource, obj.object, workFeedback[obj.object.GetKind()])
+			return plannedObject{resource: ocminterface.ManifestWorkGVR, object: work, manifest: obj.String()}
+		},
+		confirm: ocmcli.waitApplied,
+	}, nil
+}
+
+func (ocmcli *OCMClient) deleteObject(ctx context.Context, cluster *l2sces.Cluster, resource schema.GroupVersionResource, namespace string, name string) error {
+	hubClient, err := dynamic.NewForConfig(&ocmcli.HubClusterConfig)
+	if err != nil {
+		return fmt.Errorf("error contacting hub: %v", err)
+	}
+	return hubClient.Resource(ocminterface.ManifestWorkGVR).Namespace(cluster.GetName()).Delete(ctx, ocminterface.ManifestWorkName(resource, namespace, name), metav1.DeleteOptions{})
+}
+
+// waitApplied waits until the work agent reports every ManifestWork of the plan applied, and keeps the
+// feedback it reported as the status of the plan. A work the agent failed to apply fails the plan.
+func (ocmcli *OCMClient) waitApplied(ctx context.Context, plan *clusterPlan) error {
+	interval := ocmcli.PollInterval
+	if interval <= 0 {
+		interval = DefaultWorkPollInterval
+	}
+
+	status := map[string]string{}
+	for _, obj := range plan.applied {
+		var workStatus *ocminterface.WorkStatus
+		err := wait.PollUntilContextCancel(ctx, interval, true, func(ctx context.Context) (bool, error) {
+			work, err := plan.dynClient.Resource(obj.resource).Namespace(obj.object.GetNamespace()).Get(ctx, obj.object.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return false, fmt.Errorf("error getting ManifestWork of %s: %w", obj, err)
+			}
+			workStatus, err = ocminterface.GetWorkStatus(work)
+			if err != nil {
+				return false, err
+			}
+			return workStatus.Applied != metav1.ConditionUnknown, nil
+		})
+		if err != nil {
+			return fmt.Errorf("%s was not applied by the work agent: %w", obj, err)
+		}
+		if workStatus.Applied == metav1.ConditionFalse {
+			return fmt.Errorf("%s was not applied by the work agent: %s", obj, workStatus.Message)
+		}
+		for name, value := range workStatus.Feedback {
+			status[obj.String()+"."+name] = value
+		}
+	}
+	plan.status = status
+	return nil
+}
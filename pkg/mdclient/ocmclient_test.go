@@ -0,0 +1,195 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/internal/ocminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+)
+
+// startFakeHub starts an API server knowing the OCM resources, with a ManagedCluster for each of the given
+// names. Its work agents apply every ManifestWork, except the ones of the refusing clusters.
+func startFakeHub(t *testing.T, clusters []string, refusing map[string]bool) (*rest.Config, dynamic.Interface) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, run make setup-envtest")
+	}
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "test", "crd", "ocm")},
+		ErrorIfCRDPathMissing: true,
+	}
+	config, err := testEnv.Start()
+	if err != nil {
+		t.Fatalf("could not start the hub: %v", err)
+	}
+	t.Cleanup(func() { _ = testEnv.Stop() })
+
+	hubClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, cluster := range clusters {
+		managedCluster := &unstructured.Unstructured{}
+		managedCluster.SetAPIVersion(ocminterface.ManagedClusterGVR.GroupVersion().String())
+		managedCluster.SetKind("ManagedCluster")
+		managedCluster.SetName(cluster)
+		if _, err := hubClient.Resource(ocminterface.ManagedClusterGVR).Create(ctx, managedCluster, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		namespace := &unstructured.Unstructured{}
+		namespace.SetAPIVersion("v1")
+		namespace.SetKind("Namespace")
+		namespace.SetName(cluster)
+		if _, err := hubClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}).Create(ctx, namespace, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	agentCtx, cancel := context.WithCancel(ctx)
+	t.Cleanup(cancel)
+	go runFakeWorkAgents(agentCtx, hubClient, refusing)
+	return config, hubClient
+}
+
+// runFakeWorkAgents reports every new generation of the ManifestWorks of the hub as applied, with an
+// "Available" value for every feedback rule, or as not applied in the refusing clusters.
+func runFakeWorkAgents(ctx context.Context, hubClient dynamic.Interface, refusing map[string]bool) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		works, err := hubClient.Resource(ocminterface.ManifestWorkGVR).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return
+		}
+		for _, work := range works.Items {
+			if status, err := ocminterface.GetWorkStatus(&work); err != nil || status.Applied != metav1.ConditionUnknown {
+				continue
+			}
+			condition := map[string]interface{}{
+				"type": ocminterface.WorkApplied, "status": "True", "reason": "AppliedManifestComplete", "message": "Applied",
+				"observedGeneration": work.GetGeneration(), "lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+			}
+			if refusing[work.GetNamespace()] {
+				condition["status"], condition["reason"], condition["message"] = "False", "AppliedManifestFailed", "denied by admission webhook"
+			}
+			values := []interface{}{}
+			rules, _, _ := unstructured.NestedSlice(work.Object, "spec", "manifestConfigs")
+			for _, rule := range rules {
+				feedbackRules, _, _ := unstructured.NestedSlice(rule.(map[string]interface{}), "feedbackRules")
+				for _, feedbackRule := range feedbackRules {
+					jsonPaths, _, _ := unstructured.NestedSlice(feedbackRule.(map[string]interface{}), "jsonPaths")
+					for _, jsonPath := range jsonPaths {
+						values = append(values, map[string]interface{}{
+							"name":       jsonPath.(map[string]interface{})["name"],
+							"fieldValue": map[string]interface{}{"type": "String", "string": "Available"},
+						})
+					}
+				}
+			}
+			work.Object["status"] = map[string]interface{}{
+				"conditions": []interface{}{condition},
+				"resourceStatus": map[string]interface{}{"manifests": []interface{}{
+					map[string]interface{}{"statusFeedback": map[string]interface{}{"values": values}, "conditions": []interface{}{condition}},
+				}},
+			}
+			_, _ = hubClient.Resource(ocminterface.ManifestWorkGVR).Namespace(work.GetNamespace()).UpdateStatus(ctx, &work, metav1.UpdateOptions{})
+		}
+	}, 20*time.Millisecond)
+}
+
+func workExists(t *testing.T, hubClient dynamic.Interface, cluster string, resource l2sminterface.ResourceType, namespace string, name string) bool {
+	workName := ocminterface.ManifestWorkName(l2sminterface.GetGVR(resource), namespace, name)
+	_, err := hubClient.Resource(ocminterface.ManifestWorkGVR).Namespace(cluster).Get(context.Background(), workName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+// TestOCMClient checks that slices and networks are applied as ManifestWorks, that the feedback of the
+// work agents is reported, and that a work refused by an agent is rolled back everywhere.
+func TestOCMClient(t *testing.T) {
+	config, hubClient := startFakeHub(t, []string{"edge-1", "edge-2", "broken"}, map[string]bool{"broken": true})
+	// One cluster at a time, so that edge-1 is applied before the refusing cluster fails
+	ocmcli := &OCMClient{HubClusterConfig: *config, Parallelism: 1, ClusterTimeout: 20 * time.Second, PollInterval: 20 * time.Millisecond}
+	ctx := context.Background()
+	provider := &l2sces.Provider{Name: "idco", Domain: "idco.example.com"}
+
+	slice := &l2sces.Slice{Provider: provider, Clusters: []*l2sces.Cluster{
+		{Name: "edge-1", GatewayNode: &l2sces.Node{Name: "edge-1-gw", IpAddress: "10.0.0.1"}},
+		{Name: "edge-2", GatewayNode: &l2sces.Node{Name: "edge-2-gw", IpAddress: "10.0.0.2"}},
+	}}
+	results, err := ocmcli.CreateSlice(ctx, slice, "l2sm-system")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nedName := newSliceNEDGenerator(provider).NEDName()
+	for _, result := range results {
+		if result.State != ClusterApplied || len(result.Objects) != 1 || result.Objects[0] != "NetworkEdgeDevice/"+nedName {
+			t.Errorf("expected the NED applied in cluster %s, got %+v", result.Cluster, result)
+		}
+		if result.Status["NetworkEdgeDevice/"+nedName+".availability"] != "Available" {
+			t.Errorf("expected the availability of the NED in cluster %s, got %v", result.Cluster, result.Status)
+		}
+		if !workExists(t, hubClient, result.Cluster, l2sminterface.NetworkEdgeDevice, "l2sm-system", nedName) {
+			t.Errorf("expected a ManifestWork for the NED in cluster %s", result.Cluster)
+		}
+	}
+
+	network := &l2sces.L2Network{Name: "inter-net", Provider: provider, Clusters: []*l2sces.Cluster{{Name: "edge-1"}, {Name: "broken"}}}
+	results, err = ocmcli.CreateNetwork(ctx, network, "default")
+	if err == nil {
+		t.Fatalf("expected the network to fail in the refusing cluster")
+	}
+	expected := map[string]ClusterState{"edge-1": ClusterRolledBack, "broken": ClusterRolledBack}
+	for _, result := range results {
+		if result.State != expected[result.Cluster] {
+			t.Errorf("expected cluster %s to be %s, got %+v", result.Cluster, expected[result.Cluster], result)
+		}
+		if workExists(t, hubClient, result.Cluster, l2sminterface.L2Network, "default", "inter-net") {
+			t.Errorf("expected the ManifestWork of the network to be rolled back in cluster %s", result.Cluster)
+		}
+	}
+
+	_, err = ocmcli.CreateNetwork(ctx, &l2sces.L2Network{Name: "inter-net", Provider: provider, Clusters: []*l2sces.Cluster{{Name: "missing"}}}, "default")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a cluster unknown to the hub to be not found, got %v", err)
+	}
+
+	results, err = ocmcli.DeleteSlice(ctx, slice, "l2sm-system")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, result := range results {
+		if result.State != ClusterDeleted || workExists(t, hubClient, result.Cluster, l2sminterface.NetworkEdgeDevice, "l2sm-system", nedName) {
+			t.Errorf("expected the ManifestWork of the NED to be deleted in cluster %s, got %+v", result.Cluster, result)
+		}
+	}
+}
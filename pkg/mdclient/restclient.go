@@ -15,10 +15,11 @@
 package mdclient
 
 import (
+	"errors"
 	"fmt"
-	"io/fs"
-	"os"
-	"path/filepath"
+	"log"
+	"sync"
+	"time"
 
 	"context"
 
@@ -26,274 +27,254 @@ import (
 
 	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
 	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
-	"github.com/Networks-it-uc3m/l2sc-es/pkg/operator"
-	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologygenerator"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/registry"
 	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/dynamic"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 type RestClient struct {
 	ManagerClusterConfig rest.Config
+	// Clusters resolves the member clusters by name. Defaults to the l2sm-cert Secrets of the manager cluster.
+	Clusters ClusterSource
+	// KeepPartial leaves the objects created by a failed apply in place, annotated with PartialAnnotation,
+	// instead of rolling them back.
+	KeepPartial bool
+	// Parallelism is the number of clusters contacted at the same time. Defaults to DefaultParallelism.
+	Parallelism int
+	// ClusterTimeout bounds the calls made to a single cluster. Defaults to DefaultClusterTimeout.
+	ClusterTimeout time.Duration
+
+	clustersOnce sync.Once
+	clustersErr  error
 }
 
-func (restcli *RestClient) CreateNetwork(network *l2sces.L2Network, namespace string) error {
+func (restcli *RestClient) CreateNetwork(ctx context.Context, network *l2sces.L2Network, namespace string) ([]ClusterResult, error) {
+	return createNetwork(ctx, restcli, network, namespace)
+}
 
-	fmt.Printf("Creating network %s", network.GetName())
-	namespace = utils.DefaultIfEmpty(namespace, "default")
+func (restcli *RestClient) DeleteNetwork(ctx context.Context, network *l2sces.L2Network, namespace string) ([]ClusterResult, error) {
+	return deleteNetwork(ctx, restcli, network, namespace)
+}
 
-	l2network, err := l2sminterface.ConstructL2NetworkFromL2smmd(network)
+func (restcli *RestClient) CreateSlice(ctx context.Context, slice *l2sces.Slice, namespace string) ([]ClusterResult, error) {
+	return createSlice(ctx, restcli, slice, namespace)
+}
 
+func (restcli *RestClient) DeleteSlice(ctx context.Context, slice *l2sces.Slice, namespace string) ([]ClusterResult, error) {
+	return deleteSlice(ctx, restcli, slice, namespace)
+}
+
+// newPlan returns a plan applying the objects through the API server of the cluster.
+func (restcli *RestClient) newPlan(ctx context.Context, cluster *l2sces.Cluster) (*clusterPlan, error) {
+	dynClient, err := restcli.newClusterClient(ctx, cluster)
 	if err != nil {
-		return fmt.Errorf("failed to construct l2network: %v", err)
+		return nil, err
 	}
+	return &clusterPlan{cluster: cluster.GetName(), dynClient: dynClient}, nil
+}
 
-	var l2networkArray *l2smv1.L2NetworkList
+func (restcli *RestClient) deleteObject(ctx context.Context, cluster *l2sces.Cluster, resource schema.GroupVersionResource, namespace string, name string) error {
+	dynClient, err := restcli.newClusterClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	return dynClient.Resource(resource).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
 
-	if network.GetPodCidr() != "" {
-		l2networkArray, err = l2sminterface.ApplyCIDRs(network.PodCidr, *l2network, len(network.Clusters))
-		if err != nil {
-			return fmt.Errorf("failed to apply cidr configs to l2network: %v", err)
-		}
+// createNetwork creates the L2Network in every cluster. If it fails in one of them, the networks already
+// created are rolled back, and the returned results tell the state each cluster was left in.
+func createNetwork(ctx context.Context, clients memberClients, network *l2sces.L2Network, namespace string) ([]ClusterResult, error) {
 
-	}
+	log.Printf("Creating network %s", network.GetName())
+	namespace = utils.DefaultIfEmpty(namespace, "default")
 
-	// creates the in-cluster config
+	l2network, err := l2sminterface.ConstructL2NetworkFromL2smmd(network)
 
-	clusterCrts, err := operator.GetClusterCertificates(&restcli.ManagerClusterConfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to construct l2network: %v", ErrInvalidArgument, err)
+	}
 
+	pools, err := PodAddressPools(network)
 	if err != nil {
-		return fmt.Errorf("could not get cluster certificates error: %v", err)
+		return nil, err
 	}
 
+	tx := clients.newTransaction()
 	for index, cluster := range network.Clusters {
 
-		var unstructuredL2network map[string]interface{}
+		clusterNetwork := l2network.DeepCopy()
 		if network.GetPodCidr() != "" {
-			l2network = &l2networkArray.Items[index]
-
+			clusterNetwork.Spec.NetworkCIDR = network.GetPodCidr()
 		}
-
-		if cluster.GetPodAddressPool() != "" {
-			l2network.Spec.PodAddressRange = cluster.GetPodAddressPool()
+		if pools[index] != "" {
+			clusterNetwork.Spec.PodAddressRange = pools[index]
 		}
 
-		unstructuredL2network, err = runtime.DefaultUnstructuredConverter.ToUnstructured(l2network)
-
+		plan, err := clients.newPlan(ctx, cluster)
 		if err != nil {
-			return fmt.Errorf("failed to assign unstructured l2network: %v", err)
+			return nil, err
 		}
+		tx.add(plan)
 
-		unstructuredObj := &unstructured.Unstructured{Object: unstructuredL2network}
-
-		clusterConfig := &rest.Config{Host: cluster.RestConfig.ApiKey, BearerToken: cluster.RestConfig.BearerToken,
-			TLSClientConfig: rest.TLSClientConfig{
-				Insecure: false, // Set to true if self-signed certs are acceptable
-				CAData:   clusterCrts[cluster.Name],
-			},
-		}
-		dynClient, err := dynamic.NewForConfig(clusterConfig)
+		err = plan.add(l2sminterface.GetGVR(l2sminterface.L2Network), utils.DefaultIfEmpty(cluster.GetNamespace(), namespace), clusterNetwork)
 		if err != nil {
-			return fmt.Errorf("error contacting cluster %s: %v", clusterConfig.String(), err)
+			return nil, err
 		}
+	}
 
-		resource := l2sminterface.GetGVR(l2sminterface.L2Network)
-
-		namespace = utils.DefaultIfEmpty(cluster.Namespace, namespace)
+	return tx.apply(ctx)
+}
 
-		_, err = dynClient.Resource(resource).Namespace(namespace).Create(context.Background(), unstructuredObj, metav1.CreateOptions{})
+// PodAddressPools returns the pod address range of each cluster of the network: the pool given for the
+// cluster or, if the network has a pod CIDR, its share of it.
+func PodAddressPools(network *l2sces.L2Network) ([]string, error) {
+	pools := make([]string, len(network.GetClusters()))
+	if network.GetPodCidr() != "" {
+		shares, err := l2sminterface.ApplyCIDRs(network.GetPodCidr(), l2smv1.L2Network{}, len(pools))
 		if err != nil {
-			return fmt.Errorf("error creating resource: %v", err)
+			return nil, fmt.Errorf("%w: failed to apply cidr configs to l2network: %v", ErrInvalidArgument, err)
+		}
+		for index := range pools {
+			pools[index] = shares.Items[index].Spec.PodAddressRange
 		}
-
 	}
-
-	return nil
+	for index, cluster := range network.GetClusters() {
+		if cluster.GetPodAddressPool() != "" {
+			pools[index] = cluster.GetPodAddressPool()
+		}
+	}
+	return pools, nil
 }
 
-func (restcli *RestClient) DeleteNetwork(network *l2sces.L2Network, namespace string) error {
-
-	clusterCrts, err := operator.GetClusterCertificates(&restcli.ManagerClusterConfig)
-
-	if err != nil {
-		return fmt.Errorf("could not get cluster certificates error: %v", err)
-	}
+// deleteNetwork deletes the L2Network from every cluster, concurrently. A failing cluster doesn't stop the
+// deletion in the rest of them.
+func deleteNetwork(ctx context.Context, clients memberClients, network *l2sces.L2Network, namespace string) ([]ClusterResult, error) {
 
-	fmt.Printf("Deleting network %s", network.Name)
+	log.Printf("Deleting network %s", network.Name)
 	namespace = utils.DefaultIfEmpty(namespace, "default")
-	for _, cluster := range network.Clusters {
-		clusterConfig := &rest.Config{Host: cluster.RestConfig.ApiKey, BearerToken: cluster.RestConfig.BearerToken,
-			TLSClientConfig: rest.TLSClientConfig{
-				Insecure: false, // Set to true if self-signed certs are acceptable
-				CAData:   clusterCrts[cluster.Name],
-			},
-		}
-		dynClient, err := dynamic.NewForConfig(clusterConfig)
-		if err != nil {
-			return fmt.Errorf("error contacting cluster %s: %v", clusterConfig.String(), err)
-		}
-		resource := l2sminterface.GetGVR(l2sminterface.L2Network)
+	resource := l2sminterface.GetGVR(l2sminterface.L2Network)
 
-		err = dynClient.Resource(resource).Namespace(namespace).Delete(context.Background(), network.Name, metav1.DeleteOptions{})
+	clusters := network.GetClusters()
+	deleted := make([][]string, len(clusters))
+	errs := clients.fanOut().run(ctx, len(clusters), false, func(ctx context.Context, index int) error {
+		cluster := clusters[index]
+		err := clients.deleteObject(ctx, cluster, resource, utils.DefaultIfEmpty(cluster.GetNamespace(), namespace), network.Name)
 		if err != nil {
-			return fmt.Errorf("error deleting resource: %v", err)
+			return fmt.Errorf("error deleting resource: %w", err)
 		}
+		deleted[index] = []string{l2sminterface.GetKind(l2sminterface.L2Network) + "/" + network.GetName()}
+		return nil
+	})
 
-	}
-
-	return nil
-
+	return deleteResults(clusters, deleted, errs)
 }
 
-func (restcli *RestClient) CreateSlice(slice *l2sces.Slice, namespace string) error {
+// createSlice creates the NetworkEdgeDevice and the Overlay of the slice in every cluster. If it fails in
+// one of them, the objects already created are rolled back, and the returned results tell the state each
+// cluster was left in.
+func createSlice(ctx context.Context, clients memberClients, slice *l2sces.Slice, namespace string) ([]ClusterResult, error) {
 
-	fmt.Printf("Creating slice %s", slice)
+	log.Printf("Creating slice %s", registry.SliceName(slice))
 
 	namespace = utils.DefaultIfEmpty(namespace, "default")
 
-	clusterMaps := make(map[string]l2sminterface.NodeConfig)
 	sliceClusters := slice.GetClusters()
 	isMultiCluster := len(sliceClusters) > 1
-	sliceLinks := slice.GetLinks()
-
-	if isMultiCluster {
-		if len(sliceLinks) == 0 {
-			clusterNames := make([]string, len(sliceClusters))
-			for index, cluster := range sliceClusters {
-				clusterNames[index] = cluster.Name
-			}
-			sliceLinks = topologygenerator.GenerateTopology(clusterNames)
-			// fmt.Println(sliceLinks)
-		}
-		for _, cluster := range sliceClusters {
-			clusterMaps[cluster.GetName()] = l2sminterface.NodeConfig{
-				NodeName:  cluster.GetGatewayNode().GetName(),
-				IPAddress: cluster.GetGatewayNode().GetIpAddress()}
-		}
-	}
-
-	clusterCrts, err := operator.GetClusterCertificates(&restcli.ManagerClusterConfig)
 
+	links, err := sliceLinks(slice)
 	if err != nil {
-		return fmt.Errorf("could not get cluster certificates error: %v", err)
+		return nil, err
 	}
+	clusterNeighbors := l2sminterface.ComputeNeighbors(links, sliceGateways(sliceClusters))
 
-	nedGenerator := l2sminterface.NewNEDGenerator(l2sminterface.SDNController{
-		Name:    slice.GetProvider().GetName(),
-		Domain:  slice.GetProvider().GetDomain(),
-		SDNPort: slice.GetProvider().GetSdnPort(),
-		DNSPort: slice.GetProvider().GetDnsPort(),
-		OFPort:  slice.GetProvider().GetOfPort(),
-	})
+	nedGenerator := newSliceNEDGenerator(slice.GetProvider())
 
+	tx := clients.newTransaction()
 	for _, cluster := range sliceClusters {
 
-		clusterConfig := &rest.Config{
-			Host:        cluster.RestConfig.ApiKey,
-			BearerToken: cluster.RestConfig.BearerToken,
-			TLSClientConfig: rest.TLSClientConfig{
-				Insecure: false, // Set to true if self-signed certs are acceptable
-				CAData:   clusterCrts[cluster.Name],
-			},
-		}
-		dynClient, err := dynamic.NewForConfig(clusterConfig)
+		plan, err := clients.newPlan(ctx, cluster)
 		if err != nil {
-			return fmt.Errorf("error contacting cluster %s: %v", clusterConfig.String(), err)
+			return nil, err
 		}
+		tx.add(plan)
 
 		if isMultiCluster {
+			err = planNED(plan, namespace, nedGenerator, cluster, clusterNeighbors[cluster.GetName()])
+			if err != nil {
+				return nil, err
+			}
+		}
 
-			clusterNeighbors := []l2sminterface.Neighbor{}
-			// fmt.Println(sliceLinks)
-			for _, link := range sliceLinks {
-				switch cluster.GetName() {
-				case link.EndpointA:
-					clusterNeighbors = append(clusterNeighbors, l2sminterface.Neighbor{
-						Node:   link.GetEndpointB(),
-						Domain: clusterMaps[link.EndpointB].IPAddress,
-					})
-				case link.EndpointB:
-					clusterNeighbors = append(clusterNeighbors, l2sminterface.Neighbor{
-						Node:   link.GetEndpointA(),
-						Domain: clusterMaps[link.EndpointA].IPAddress,
-					})
-				}
+		if cluster.GetOverlay() != nil {
+			err = planOverlay(plan, namespace, cluster.GetOverlay())
+			if err != nil {
+				return nil, err
 			}
+		}
+	}
 
-			ned := nedGenerator.ConstructNED(l2sminterface.NEDValues{
-				NodeConfig: l2sminterface.NodeConfig{NodeName: cluster.GetGatewayNode().GetName(), IPAddress: cluster.GetGatewayNode().GetIpAddress()},
-				Neighbors:  clusterNeighbors})
+	return tx.apply(ctx)
+}
 
-			unstructuredNED, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ned)
+// deleteSlice removes the NetworkEdgeDevice and the Overlay of the slice from every cluster. Objects that
+// are already gone are not an error, and a failing cluster doesn't stop the deletion in the rest of them.
+func deleteSlice(ctx context.Context, clients memberClients, slice *l2sces.Slice, namespace string) ([]ClusterResult, error) {
 
-			if err != nil {
-				return fmt.Errorf("failed to assign unstructured l2network: %v", err)
-			}
+	log.Printf("Deleting slice %s", slice.GetProvider().GetName())
 
-			resource := l2sminterface.GetGVR(l2sminterface.NetworkEdgeDevice)
+	namespace = utils.DefaultIfEmpty(namespace, "default")
 
-			// fmt.Println(unstructuredNED)
-			// fmt.Println(resource)
-			_, err = dynClient.Resource(resource).Namespace(namespace).Create(context.Background(), &unstructured.Unstructured{Object: unstructuredNED}, metav1.CreateOptions{})
-			if err != nil {
-				return fmt.Errorf("error creating resource: %v", err)
-			}
-			dynClient.Resource(resource).Namespace(namespace).Get(context.Background(), "overlay-sample", metav1.GetOptions{})
-		}
+	nedName := l2sminterface.NewNEDGenerator(l2sminterface.SDNController{Name: slice.GetProvider().GetName()}).NEDName()
+	overlayName := l2sminterface.DefaultOverlayName
 
-		//////////////////////////////////////7
-		overlay := l2sminterface.ConstructOverlayFromL2smmd(cluster.GetOverlay())
-		unstructuredOverlay, err := runtime.DefaultUnstructuredConverter.ToUnstructured(overlay)
-		if err != nil {
-			return fmt.Errorf("failed to assign unstructured l2network: %v", err)
-		}
+	clusters := slice.GetClusters()
+	deleted := make([][]string, len(clusters))
+	errs := clients.fanOut().run(ctx, len(clusters), false, func(ctx context.Context, index int) error {
+		cluster := clusters[index]
 
-		// fmt.Println(unstructuredOverlay)
-		_, err = dynClient.Resource(l2sminterface.GetGVR(l2sminterface.Overlay)).Namespace(namespace).Create(context.Background(), &unstructured.Unstructured{Object: unstructuredOverlay}, metav1.CreateOptions{})
-		if err != nil {
-			return fmt.Errorf("error creating resource: %v", err)
+		var errs []error
+		err := clients.deleteObject(ctx, cluster, l2sminterface.GetGVR(l2sminterface.NetworkEdgeDevice), namespace, nedName)
+		if err == nil {
+			deleted[index] = append(deleted[index], l2sminterface.GetKind(l2sminterface.NetworkEdgeDevice)+"/"+nedName)
+		} else if !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting network edge device %s: %w", nedName, err))
 		}
 
-	}
-
-	return nil
-}
+		err = clients.deleteObject(ctx, cluster, l2sminterface.GetGVR(l2sminterface.Overlay), namespace, overlayName)
+		if err == nil {
+			deleted[index] = append(deleted[index], l2sminterface.GetKind(l2sminterface.Overlay)+"/"+overlayName)
+		} else if !apierrors.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("error deleting overlay %s: %w", overlayName, err))
+		}
+		return errors.Join(errs...)
+	})
 
-func (restcli *RestClient) DeleteSlice(slice *l2sces.Slice, namespace string) error {
-	return nil
+	return deleteResults(clusters, deleted, errs)
 }
 
-func GetRestConfigs(absKubeconfigDirectory string) ([]rest.Config, error) {
-	kubeFiles, err := os.ReadDir(absKubeconfigDirectory)
-	if err != nil {
-		return []rest.Config{}, fmt.Errorf("couldn't get kube config files in %s: %v", absKubeconfigDirectory, err)
-
-	}
-	clusterConfigs, err := readKubernetesConfigs(absKubeconfigDirectory, kubeFiles)
-	if err != nil {
-		return []rest.Config{}, fmt.Errorf("failed to read configs in %s: %v", absKubeconfigDirectory, err)
+// deleteResults returns the state every cluster was left in by a delete, with the objects deleted from it.
+func deleteResults(clusters []*l2sces.Cluster, deleted [][]string, errs []error) ([]ClusterResult, error) {
+	results := make([]ClusterResult, len(clusters))
+	for index, cluster := range clusters {
+		results[index] = ClusterResult{Cluster: cluster.GetName(), State: ClusterDeleted, Objects: deleted[index]}
+		if errs[index] != nil {
+			results[index].State = ClusterFailed
+			results[index].Err = errs[index]
+		}
 	}
-	return clusterConfigs, nil
+	return results, clusterErrors(clusters, errs)
 }
 
-func readKubernetesConfigs(absKubeconfigDirectory string, configDirectories []fs.DirEntry) ([]rest.Config, error) {
-
-	var clusterConfigs []rest.Config
-
-	for _, configEntry := range configDirectories {
-		fmt.Println(filepath.Join(absKubeconfigDirectory, configEntry.Name()))
-		kubeconfig := filepath.Join(absKubeconfigDirectory, configEntry.Name())
-		config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+// clusterErrors joins the errors of a fan out, prefixed with the name of their cluster.
+func clusterErrors(clusters []*l2sces.Cluster, errs []error) error {
+	var clusterErrs []error
+	for index, err := range errs {
 		if err != nil {
-			return []rest.Config{}, fmt.Errorf("failed to build config %s from flags: %v", configEntry.Name(), err)
+			clusterErrs = append(clusterErrs, fmt.Errorf("cluster %s: %w", clusters[index].GetName(), err))
 		}
-		clusterConfigs = append(clusterConfigs, *config)
 	}
-
-	return clusterConfigs, nil
-
+	return errors.Join(clusterErrs...)
 }
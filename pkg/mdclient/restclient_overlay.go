@@ -0,0 +1,253 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologygenerator"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
+)
+
+func (restcli *RestClient) CreateOverlay(ctx context.Context, overlay *l2sces.Overlay, clusters []*l2sces.Cluster, namespace string) ([]ClusterResult, error) {
+	return createOverlay(ctx, restcli, overlay, clusters, namespace)
+}
+
+func (restcli *RestClient) AddCluster(ctx context.Context, slice *l2sces.Slice, cluster *l2sces.Cluster, links []*l2sces.Link, namespace string) error {
+	return addCluster(ctx, restcli, slice, cluster, links, namespace)
+}
+
+func (restcli *RestClient) RemoveCluster(ctx context.Context, slice *l2sces.Slice, clusterName string, namespace string) error {
+	return removeCluster(ctx, restcli, slice, clusterName, namespace)
+}
+
+func (restcli *RestClient) UpdateSlice(ctx context.Context, current *l2sces.Slice, desired *l2sces.Slice, namespace string) error {
+	return updateSlice(ctx, restcli, current, desired, namespace)
+}
+
+func (restcli *RestClient) DeleteOverlay(ctx context.Context, slice *l2sces.Slice, namespace string) ([]ClusterResult, error) {
+	return deleteSlice(ctx, restcli, slice, namespace)
+}
+
+// createOverlay creates an overlay between the given clusters. The overlay nodes are not used, its
+// links join cluster names.
+func createOverlay(ctx context.Context, clients memberClients, overlay *l2sces.Overlay, clusters []*l2sces.Cluster, namespace string) ([]ClusterResult, error) {
+	return createSlice(ctx, clients, &l2sces.Slice{
+		Provider: overlay.GetProvider(),
+		Clusters: clusters,
+		Links:    overlay.GetLinks(),
+	}, namespace)
+}
+
+// addCluster joins a cluster to a running slice through the given links, or to every cluster if none is
+// given. If the slice has a topology and no links are given, its links are generated again with the new
+// cluster instead.
+func addCluster(ctx context.Context, clients memberClients, slice *l2sces.Slice, cluster *l2sces.Cluster, links []*l2sces.Link, namespace string) error {
+
+	log.Printf("Adding cluster %s to slice %s", cluster.GetName(), slice.GetProvider().GetName())
+
+	for _, sliceCluster := range slice.GetClusters() {
+		if sliceCluster.GetName() == cluster.GetName() {
+			return fmt.Errorf("%w: cluster %s is already part of the slice", ErrAlreadyExists, cluster.GetName())
+		}
+	}
+
+	desired := proto.Clone(slice).(*l2sces.Slice)
+	desired.Clusters = append(desired.Clusters, cluster)
+	if len(links) > 0 || len(slice.GetLinks()) > 0 {
+		currentLinks, err := sliceLinks(slice)
+		if err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			for _, sliceCluster := range slice.GetClusters() {
+				links = append(links, &l2sces.Link{EndpointA: sliceCluster.GetName(), EndpointB: cluster.GetName()})
+			}
+		}
+		desired.Links = append(currentLinks, links...)
+		desired.Topology = nil
+	}
+	return updateSlice(ctx, clients, slice, desired, namespace)
+}
+
+// removeCluster makes a cluster leave a running slice, with its links. If the slice has a topology, its
+// links are generated again without the cluster instead.
+func removeCluster(ctx context.Context, clients memberClients, slice *l2sces.Slice, clusterName string, namespace string) error {
+
+	log.Printf("Removing cluster %s from slice %s", clusterName, slice.GetProvider().GetName())
+
+	desired := proto.Clone(slice).(*l2sces.Slice)
+	desired.Clusters = nil
+	for _, cluster := range slice.GetClusters() {
+		if cluster.GetName() != clusterName {
+			desired.Clusters = append(desired.Clusters, cluster)
+		}
+	}
+	if len(desired.Clusters) == len(slice.GetClusters()) {
+		return fmt.Errorf("%w: cluster %s is not part of the slice", ErrNotFound, clusterName)
+	}
+	desired.Links = nil
+	for _, link := range slice.GetLinks() {
+		if link.GetEndpointA() != clusterName && link.GetEndpointB() != clusterName {
+			desired.Links = append(desired.Links, link)
+		}
+	}
+	return updateSlice(ctx, clients, slice, desired, namespace)
+}
+
+// updateSlice takes a running slice from its current topology to the desired one. Only the clusters in the
+// diff between them are touched: the new ones get their NetworkEdgeDevice, the ones that leave get it
+// deleted, and the ones with other neighbors get them updated. The rest keep their tunnels up.
+func updateSlice(ctx context.Context, clients memberClients, current *l2sces.Slice, desired *l2sces.Slice, namespace string) error {
+
+	namespace = utils.DefaultIfEmpty(namespace, "default")
+
+	currentLinks, err := sliceLinks(current)
+	if err != nil {
+		return err
+	}
+	desiredLinks, err := sliceLinks(desired)
+	if err != nil {
+		return err
+	}
+	diff := topologygenerator.Diff(clusterNames(current.GetClusters()), currentLinks, clusterNames(desired.GetClusters()), desiredLinks)
+	clusterNeighbors := l2sminterface.ComputeNeighbors(desiredLinks, sliceGateways(desired.GetClusters()))
+	nedGenerator := newSliceNEDGenerator(desired.GetProvider())
+
+	tx := clients.newTransaction()
+	for _, cluster := range desired.GetClusters() {
+		if !slices.Contains(diff.Added, cluster.GetName()) {
+			continue
+		}
+		plan, err := clients.newPlan(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		tx.add(plan)
+		err = planNED(plan, namespace, nedGenerator, cluster, clusterNeighbors[cluster.GetName()])
+		if err != nil {
+			return err
+		}
+		if cluster.GetOverlay() != nil {
+			err = planOverlay(plan, namespace, cluster.GetOverlay())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := tx.apply(ctx); err != nil {
+		return err
+	}
+
+	removed := &l2sces.Slice{Provider: current.GetProvider()}
+	for _, cluster := range current.GetClusters() {
+		if slices.Contains(diff.Removed, cluster.GetName()) {
+			removed.Clusters = append(removed.Clusters, cluster)
+		}
+	}
+	if len(removed.Clusters) > 0 {
+		if _, err := deleteSlice(ctx, clients, removed, namespace); err != nil {
+			return err
+		}
+	}
+
+	updated := make(map[string]bool)
+	for _, cluster := range diff.Updated {
+		updated[cluster] = true
+	}
+	return updateNeighbors(ctx, clients, desired.GetClusters(), updated, clusterNeighbors, nedGenerator, namespace)
+}
+
+// updateNeighbors applies the NetworkEdgeDevice with its new neighbors in each of the affected clusters,
+// concurrently. A failing cluster doesn't leave the rest with stale neighbors.
+func updateNeighbors(ctx context.Context, clients memberClients, clusters []*l2sces.Cluster, affected map[string]bool, clusterNeighbors map[string][]l2sminterface.Neighbor,
+	nedGenerator *l2sminterface.NEDGenerator, namespace string) error {
+
+	errs := clients.fanOut().run(ctx, len(clusters), false, func(ctx context.Context, index int) error {
+		cluster := clusters[index]
+		if !affected[cluster.GetName()] {
+			return nil
+		}
+		plan, err := clients.newPlan(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		err = planNED(plan, namespace, nedGenerator, cluster, clusterNeighbors[cluster.GetName()])
+		if err != nil {
+			return err
+		}
+		return plan.apply(ctx)
+	})
+	return clusterErrors(clusters, errs)
+}
+
+// sliceLinks returns the links of a slice, or the ones its topology generates if none is given.
+func sliceLinks(slice *l2sces.Slice) ([]*l2sces.Link, error) {
+	links, err := topologygenerator.SliceLinks(slice)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArgument, err)
+	}
+	return links, nil
+}
+
+// sliceGateways maps every cluster to its gateway node.
+func sliceGateways(clusters []*l2sces.Cluster) map[string]l2sminterface.NodeConfig {
+	gateways := make(map[string]l2sminterface.NodeConfig)
+	for _, cluster := range clusters {
+		gateways[cluster.GetName()] = l2sminterface.NodeConfig{
+			NodeName:  cluster.GetGatewayNode().GetName(),
+			IPAddress: cluster.GetGatewayNode().GetIpAddress(),
+		}
+	}
+	return gateways
+}
+
+func clusterNames(clusters []*l2sces.Cluster) []string {
+	names := make([]string, len(clusters))
+	for index, cluster := range clusters {
+		names[index] = cluster.GetName()
+	}
+	return names
+}
+
+func newSliceNEDGenerator(provider *l2sces.Provider) *l2sminterface.NEDGenerator {
+	return l2sminterface.NewNEDGenerator(l2sminterface.SDNController{
+		Name:        provider.GetName(),
+		Domain:      provider.GetDomain(),
+		SDNPort:     provider.GetSdnPort(),
+		DNSPort:     provider.GetDnsPort(),
+		OFPort:      provider.GetOfPort(),
+		DNSGRPCPort: provider.GetDnsGrpcPort(),
+	})
+}
+
+func planNED(plan *clusterPlan, namespace string, nedGenerator *l2sminterface.NEDGenerator, cluster *l2sces.Cluster, neighbors []l2sminterface.Neighbor) error {
+	ned := nedGenerator.ConstructNED(l2sminterface.NEDValues{
+		NodeConfig: l2sminterface.NodeConfig{NodeName: cluster.GetGatewayNode().GetName(), IPAddress: cluster.GetGatewayNode().GetIpAddress()},
+		Neighbors:  neighbors})
+
+	return plan.add(l2sminterface.GetGVR(l2sminterface.NetworkEdgeDevice), namespace, ned)
+}
+
+func planOverlay(plan *clusterPlan, namespace string, clusterOverlay *l2sces.Overlay) error {
+	return plan.add(l2sminterface.GetGVR(l2sminterface.Overlay), namespace, l2sminterface.ConstructOverlayFromL2smmd(clusterOverlay))
+}
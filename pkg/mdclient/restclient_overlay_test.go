@@ -0,0 +1,129 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+)
+
+// TestSliceLinks checks that explicit links are kept and that the topology, a full mesh by default, is
+// generated otherwise.
+func TestSliceLinks(t *testing.T) {
+	clusters := []*l2sces.Cluster{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+
+	links, err := sliceLinks(&l2sces.Slice{Clusters: clusters})
+	if err != nil || len(links) != 3 {
+		t.Errorf("expected a full mesh of 3 links, got %v (%v)", links, err)
+	}
+
+	explicit := []*l2sces.Link{{EndpointA: "a", EndpointB: "b"}}
+	if links, _ := sliceLinks(&l2sces.Slice{Clusters: clusters, Links: explicit}); !reflect.DeepEqual(links, explicit) {
+		t.Errorf("expected the explicit links, got %v", links)
+	}
+
+	if links, _ := sliceLinks(&l2sces.Slice{Clusters: clusters[:1]}); len(links) != 0 {
+		t.Errorf("expected no links for a single cluster, got %v", links)
+	}
+
+	if links, _ := sliceLinks(&l2sces.Slice{Clusters: clusters, Topology: &l2sces.Topology{Strategy: "Star", Hub: "b"}}); len(links) != 2 {
+		t.Errorf("expected a star of 2 links, got %v", links)
+	}
+
+	if _, err := sliceLinks(&l2sces.Slice{Clusters: clusters, Topology: &l2sces.Topology{Strategy: "Tree"}}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected an unknown strategy to be an invalid argument, got %v", err)
+	}
+}
+
+// fakeMemberClients reaches fake member clusters.
+type fakeMemberClients map[string]*dynamicfake.FakeDynamicClient
+
+func (clients fakeMemberClients) fanOut() fanOut {
+	return fanOut{parallelism: 1, timeout: time.Minute}
+}
+
+func (clients fakeMemberClients) newTransaction() *transaction {
+	return &transaction{fanOut: clients.fanOut()}
+}
+
+func (clients fakeMemberClients) newPlan(ctx context.Context, cluster *l2sces.Cluster) (*clusterPlan, error) {
+	return &clusterPlan{cluster: cluster.GetName(), dynClient: clients[cluster.GetName()]}, nil
+}
+
+func (clients fakeMemberClients) deleteObject(ctx context.Context, cluster *l2sces.Cluster, resource schema.GroupVersionResource, namespace string, name string) error {
+	return clients[cluster.GetName()].Resource(resource).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// TestUpdateSlice checks that only the clusters whose neighbors change are touched when the slice grows or
+// shrinks.
+func TestUpdateSlice(t *testing.T) {
+	clients := fakeMemberClients{}
+	clusters := []*l2sces.Cluster{}
+	for index, name := range []string{"a", "b", "c", "d"} {
+		clients[name] = newFakeDynamicClient()
+		clusters = append(clusters, &l2sces.Cluster{Name: name, GatewayNode: &l2sces.Node{Name: name, IpAddress: fmt.Sprintf("172.20.0.%d", index+3)}})
+	}
+	slice := &l2sces.Slice{Provider: &l2sces.Provider{Name: "test-slice"}, Clusters: clusters[:3],
+		Links: []*l2sces.Link{{EndpointA: "a", EndpointB: "b"}, {EndpointA: "b", EndpointB: "c"}}}
+	ctx := context.Background()
+	if _, err := createSlice(ctx, clients, slice, "default"); err != nil {
+		t.Fatal(err)
+	}
+	touched := func() []string {
+		names := []string{}
+		for _, name := range []string{"a", "b", "c", "d"} {
+			if len(clients[name].Actions()) > 0 {
+				names = append(names, name)
+			}
+			clients[name].ClearActions()
+		}
+		return names
+	}
+	touched()
+
+	if err := addCluster(ctx, clients, slice, clusters[3], []*l2sces.Link{{EndpointA: "c", EndpointB: "d"}}, "default"); err != nil {
+		t.Fatalf("addCluster failed: %v", err)
+	}
+	if names := touched(); !reflect.DeepEqual(names, []string{"c", "d"}) {
+		t.Errorf("expected only c and d to be touched when d joins, got %v", names)
+	}
+	if _, err := clients["d"].Resource(l2sminterface.GetGVR(l2sminterface.NetworkEdgeDevice)).Namespace("default").Get(ctx, "test-slice-ned", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected d to get a network edge device, got %v", err)
+	}
+	clients["d"].ClearActions()
+
+	slice.Clusters = clusters
+	slice.Links = append(slice.Links, &l2sces.Link{EndpointA: "c", EndpointB: "d"})
+	if err := removeCluster(ctx, clients, slice, "a", "default"); err != nil {
+		t.Fatalf("removeCluster failed: %v", err)
+	}
+	if names := touched(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("expected only a and b to be touched when a leaves, got %v", names)
+	}
+	if _, err := clients["a"].Resource(l2sminterface.GetGVR(l2sminterface.NetworkEdgeDevice)).Namespace("default").Get(ctx, "test-slice-ned", metav1.GetOptions{}); err == nil {
+		t.Error("expected the network edge device of a to be deleted")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+)
+
+// createObject creates an object of the given resource in the default namespace of a fake member cluster.
+func createObject(t *testing.T, client *dynamicfake.FakeDynamicClient, resource l2sminterface.ResourceType, name string) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(l2sminterface.GetGVR(resource).GroupVersion().String())
+	obj.SetKind(l2sminterface.GetKind(resource))
+	obj.SetName(name)
+	obj.SetNamespace("default")
+	if err := client.Tracker().Create(l2sminterface.GetGVR(resource), obj, "default"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func objectExists(client *dynamicfake.FakeDynamicClient, resource l2sminterface.ResourceType, name string) bool {
+	_, err := client.Resource(l2sminterface.GetGVR(resource)).Namespace("default").Get(context.Background(), name, metav1.GetOptions{})
+	return err == nil
+}
+
+func failDeletes(client *dynamicfake.FakeDynamicClient) {
+	client.PrependReactor("delete", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("cluster unavailable")
+	})
+}
+
+// TestDeleteSlice checks that only the objects of the slice are deleted, that missing ones are not an
+// error, and that the errors of every failing cluster are reported.
+func TestDeleteSlice(t *testing.T) {
+	clients := fakeMemberClients{"a": newFakeDynamicClient(), "b": newFakeDynamicClient(), "c": newFakeDynamicClient(), "d": newFakeDynamicClient()}
+	createObject(t, clients["a"], l2sminterface.NetworkEdgeDevice, "test-slice-ned")
+	createObject(t, clients["a"], l2sminterface.Overlay, l2sminterface.DefaultOverlayName)
+	createObject(t, clients["a"], l2sminterface.NetworkEdgeDevice, "other-slice-ned")
+	createObject(t, clients["a"], l2sminterface.Overlay, "other-overlay")
+	// b only holds the NetworkEdgeDevice
+	createObject(t, clients["b"], l2sminterface.NetworkEdgeDevice, "test-slice-ned")
+	failDeletes(clients["c"])
+	failDeletes(clients["d"])
+
+	slice := &l2sces.Slice{Provider: &l2sces.Provider{Name: "test-slice"},
+		Clusters: []*l2sces.Cluster{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}}}
+	results, err := deleteSlice(context.Background(), clients, slice, "default")
+	if err == nil || !strings.Contains(err.Error(), "cluster c:") || !strings.Contains(err.Error(), "cluster d:") {
+		t.Errorf("expected the errors of clusters c and d, got %v", err)
+	}
+
+	expected := map[string]struct {
+		state   ClusterState
+		objects int
+	}{"a": {ClusterDeleted, 2}, "b": {ClusterDeleted, 1}, "c": {ClusterFailed, 0}, "d": {ClusterFailed, 0}}
+	for _, result := range results {
+		if result.State != expected[result.Cluster].state || len(result.Objects) != expected[result.Cluster].objects {
+			t.Errorf("expected cluster %s to be %s with %d objects, got %+v", result.Cluster, expected[result.Cluster].state, expected[result.Cluster].objects, result)
+		}
+	}
+
+	if objectExists(clients["a"], l2sminterface.NetworkEdgeDevice, "test-slice-ned") || objectExists(clients["a"], l2sminterface.Overlay, l2sminterface.DefaultOverlayName) {
+		t.Error("expected the objects of the slice to be deleted from cluster a")
+	}
+	if !objectExists(clients["a"], l2sminterface.NetworkEdgeDevice, "other-slice-ned") || !objectExists(clients["a"], l2sminterface.Overlay, "other-overlay") {
+		t.Error("expected the objects of other slices to be kept in cluster a")
+	}
+}
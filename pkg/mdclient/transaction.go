@@ -0,0 +1,278 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// FieldManager owns the fields of every object applied by the RestClient and the OCMClient.
+	FieldManager = "l2sces"
+	// PartialAnnotation marks the objects left behind in a member cluster by a failed apply.
+	PartialAnnotation = "l2sces.l2sm.io/partial-apply"
+	// partialFieldManager owns PartialAnnotation, so that it can be removed without touching the rest.
+	partialFieldManager = "l2sces-partial"
+)
+
+// ClusterState is the state a member cluster is left in by a multi-cluster request.
+type ClusterState string
+
+const (
+	// ClusterApplied means every object planned for the cluster was applied.
+	ClusterApplied ClusterState = "Applied"
+	// ClusterNotApplied means the apply failed before reaching the cluster, which was left untouched.
+	ClusterNotApplied ClusterState = "NotApplied"
+	// ClusterFailed means the apply failed in the cluster before applying any object in it.
+	ClusterFailed ClusterState = "Failed"
+	// ClusterRolledBack means the objects created in the cluster were deleted after the apply failed.
+	// Objects that already existed before the apply are left in place.
+	ClusterRolledBack ClusterState = "RolledBack"
+	// ClusterPartial means objects applied by the failed apply were left in the cluster, annotated
+	// with PartialAnnotation.
+	ClusterPartial ClusterState = "Partial"
+	// ClusterDeleted means the objects of a deleted network or slice were removed from the cluster.
+	ClusterDeleted ClusterState = "Deleted"
+)
+
+// ClusterResult reports the state a member cluster was left in.
+type ClusterResult struct {
+	Cluster string
+	State   ClusterState
+	// Objects are the objects the apply left in the cluster, or the ones a delete removed, as kind/name.
+	Objects []string
+	// Err is the error that made the request fail in this cluster, if any.
+	Err error
+	// Status is the status the cluster reported for the applied objects, by kind/name.field. Only clients
+	// that get status back from the cluster, like the OCMClient, fill it.
+	Status map[string]string
+}
+
+// plannedObject is an object to apply in a member cluster.
+type plannedObject struct {
+	resource schema.GroupVersionResource
+	object   *unstructured.Unstructured
+	// existed tells whether the object was already in the cluster when it was applied.
+	existed bool
+	// manifest is the object applied in the member cluster as kind/name, when object wraps it.
+	manifest string
+}
+
+func (obj plannedObject) String() string {
+	if obj.manifest != "" {
+		return obj.manifest
+	}
+	return obj.object.GetKind() + "/" + obj.object.GetName()
+}
+
+// clusterPlan holds the objects to apply in a member cluster, in order.
+type clusterPlan struct {
+	cluster   string
+	dynClient dynamic.Interface
+	objects   []plannedObject
+	// applied are the objects of the plan applied so far.
+	applied []plannedObject
+	// wrap, if set, turns every object added to the plan into the one applied through dynClient.
+	wrap func(obj plannedObject) plannedObject
+	// confirm, if set, is called once every object is applied, and fails the apply unless they took effect
+	// in the cluster.
+	confirm func(ctx context.Context, plan *clusterPlan) error
+	// status is the status reported by the cluster for the applied objects, by kind/name.field.
+	status map[string]string
+}
+
+func (plan *clusterPlan) add(resource schema.GroupVersionResource, namespace string, obj interface{}) error {
+	unstructuredObj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert object to unstructured: %v", err)
+	}
+	object := &unstructured.Unstructured{Object: unstructuredObj}
+	object.SetNamespace(namespace)
+	planned := plannedObject{resource: resource, object: object}
+	if plan.wrap != nil {
+		planned = plan.wrap(planned)
+	}
+	plan.objects = append(plan.objects, planned)
+	return nil
+}
+
+// transaction applies a set of planned objects across member clusters, concurrently. If the apply fails,
+// the objects it created are deleted again, or annotated as partial when keepPartial is set.
+type transaction struct {
+	plans       []*clusterPlan
+	fanOut      fanOut
+	keepPartial bool
+}
+
+func (restcli *RestClient) newTransaction() *transaction {
+	return &transaction{fanOut: restcli.fanOut(), keepPartial: restcli.KeepPartial}
+}
+
+func (tx *transaction) newPlan(cluster string, dynClient dynamic.Interface) *clusterPlan {
+	plan := &clusterPlan{cluster: cluster, dynClient: dynClient}
+	tx.add(plan)
+	return plan
+}
+
+// add makes a plan part of the transaction.
+func (tx *transaction) add(plan *clusterPlan) {
+	tx.plans = append(tx.plans, plan)
+}
+
+// apply applies every planned object. The first failure stops the clusters still being applied. It
+// returns the state of every cluster of the transaction.
+func (tx *transaction) apply(ctx context.Context) ([]ClusterResult, error) {
+	applyErrs := tx.fanOut.run(ctx, len(tx.plans), true, func(ctx context.Context, index int) error {
+		return tx.plans[index].apply(ctx)
+	})
+
+	results := make([]ClusterResult, len(tx.plans))
+	if errors.Join(applyErrs...) == nil {
+		for index, plan := range tx.plans {
+			results[index] = ClusterResult{Cluster: plan.cluster, State: ClusterApplied, Objects: plan.appliedNames(), Status: plan.status}
+		}
+		return results, nil
+	}
+
+	var errs []error
+	for index, plan := range tx.plans {
+		results[index] = ClusterResult{Cluster: plan.cluster, State: ClusterNotApplied}
+		if err := applyErrs[index]; err != nil && !errors.Is(err, errSkipped) {
+			results[index].State = ClusterFailed
+			results[index].Err = err
+			errs = append(errs, fmt.Errorf("cluster %s: %w", plan.cluster, err))
+		}
+	}
+
+	// The rollback must run even if the apply was stopped because the request was canceled
+	compensateCtx := context.WithoutCancel(ctx)
+	compensateErrs := tx.fanOut.run(compensateCtx, len(tx.plans), false, func(ctx context.Context, index int) error {
+		plan := tx.plans[index]
+		if len(plan.applied) == 0 {
+			return nil
+		}
+		var err error
+		results[index].State, err = plan.compensate(ctx, tx.keepPartial)
+		return err
+	})
+	for index, plan := range tx.plans {
+		if compensateErrs[index] != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %v", plan.cluster, compensateErrs[index]))
+		}
+		results[index].Objects = plan.appliedNames()
+	}
+	return results, errors.Join(errs...)
+}
+
+func (plan *clusterPlan) apply(ctx context.Context) error {
+	for _, obj := range plan.objects {
+		resource := plan.dynClient.Resource(obj.resource).Namespace(obj.object.GetNamespace())
+		_, err := resource.Get(ctx, obj.object.GetName(), metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("error getting %s: %w", obj, err)
+		}
+		obj.existed = err == nil
+
+		if err := applyObject(ctx, plan.dynClient, obj.resource, obj.object); err != nil {
+			return err
+		}
+		plan.applied = append(plan.applied, obj)
+	}
+	if plan.confirm != nil {
+		return plan.confirm(ctx, plan)
+	}
+	return nil
+}
+
+// compensate deletes the objects created in the cluster in reverse order, keeping the ones that existed
+// before. If some can't be deleted, or keepPartial is set, the remaining objects are annotated as partial.
+func (plan *clusterPlan) compensate(ctx context.Context, keepPartial bool) (ClusterState, error) {
+	var errs []error
+	if !keepPartial {
+		remaining := []plannedObject{}
+		failed := false
+		for index := len(plan.applied) - 1; index >= 0; index-- {
+			obj := plan.applied[index]
+			if obj.existed {
+				remaining = append([]plannedObject{obj}, remaining...)
+				continue
+			}
+			err := plan.dynClient.Resource(obj.resource).Namespace(obj.object.GetNamespace()).Delete(ctx, obj.object.GetName(), metav1.DeleteOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("error rolling back %s: %v", obj, err))
+				remaining = append([]plannedObject{obj}, remaining...)
+				failed = true
+			}
+		}
+		plan.applied = remaining
+		if !failed {
+			return ClusterRolledBack, nil
+		}
+	}
+
+	for _, obj := range plan.applied {
+		if err := markPartial(ctx, plan.dynClient, obj.resource, obj.object, true); err != nil {
+			errs = append(errs, fmt.Errorf("error marking %s as partial: %v", obj, err))
+		}
+	}
+	return ClusterPartial, errors.Join(errs...)
+}
+
+func (plan *clusterPlan) appliedNames() []string {
+	names := make([]string, len(plan.applied))
+	for index, obj := range plan.applied {
+		names[index] = obj.String()
+	}
+	return names
+}
+
+// applyObject creates or updates an object through server-side apply, so that applying it again converges
+// instead of failing. A partial mark left by an earlier failed apply is removed.
+func applyObject(ctx context.Context, dynClient dynamic.Interface, resource schema.GroupVersionResource, object *unstructured.Unstructured) error {
+	name := object.GetKind() + "/" + object.GetName()
+	applied, err := dynClient.Resource(resource).Namespace(object.GetNamespace()).Apply(ctx, object.GetName(), object, metav1.ApplyOptions{FieldManager: FieldManager, Force: true})
+	if err != nil {
+		return fmt.Errorf("error applying %s: %w", name, err)
+	}
+	if _, marked := applied.GetAnnotations()[PartialAnnotation]; marked {
+		if err := markPartial(ctx, dynClient, resource, object, false); err != nil {
+			return fmt.Errorf("error clearing partial mark of %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// markPartial sets or removes PartialAnnotation. It is applied by its own field manager, so applying
+// the object without the annotation removes it.
+func markPartial(ctx context.Context, dynClient dynamic.Interface, resource schema.GroupVersionResource, object *unstructured.Unstructured, partial bool) error {
+	mark := &unstructured.Unstructured{}
+	mark.SetAPIVersion(object.GetAPIVersion())
+	mark.SetKind(object.GetKind())
+	mark.SetName(object.GetName())
+	if partial {
+		mark.SetAnnotations(map[string]string{PartialAnnotation: "true"})
+	}
+	_, err := dynClient.Resource(resource).Namespace(object.GetNamespace()).Apply(ctx, object.GetName(), mark, metav1.ApplyOptions{FieldManager: partialFieldManager, Force: true})
+	return err
+}
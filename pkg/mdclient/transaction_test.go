@@ -0,0 +1,226 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mdclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+)
+
+// newFakeDynamicClient returns a fake member cluster. Its tracker only applies to existing objects, so
+// apply is emulated: a missing object is created, and the annotations of an existing one are merged.
+func newFakeDynamicClient() *dynamicfake.FakeDynamicClient {
+	scheme := runtime.NewScheme()
+	_ = l2smv1.AddToScheme(scheme)
+	client := dynamicfake.NewSimpleDynamicClient(scheme)
+	client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patchAction := action.(k8stesting.PatchAction)
+		if patchAction.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		applied := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patchAction.GetPatch(), &applied.Object); err != nil {
+			return true, nil, err
+		}
+		tracker := client.Tracker()
+		existing, err := tracker.Get(action.GetResource(), action.GetNamespace(), patchAction.GetName())
+		if apierrors.IsNotFound(err) {
+			return true, applied, tracker.Create(action.GetResource(), applied, action.GetNamespace())
+		}
+		if err != nil {
+			return true, nil, err
+		}
+		merged := existing.(*unstructured.Unstructured)
+		annotations := merged.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		delete(annotations, PartialAnnotation)
+		for key, value := range applied.GetAnnotations() {
+			annotations[key] = value
+		}
+		merged.SetAnnotations(annotations)
+		if spec, found := applied.Object["spec"]; found {
+			merged.Object["spec"] = spec
+		}
+		return true, merged, tracker.Update(action.GetResource(), merged, action.GetNamespace())
+	})
+	return client
+}
+
+// newNetworkTransaction plans an L2Network in each of the given clusters.
+func newNetworkTransaction(t *testing.T, keepPartial bool, clients map[string]*dynamicfake.FakeDynamicClient, clusters ...string) *transaction {
+	tx := &transaction{fanOut: fanOut{parallelism: 1, timeout: time.Minute}, keepPartial: keepPartial}
+	for _, cluster := range clusters {
+		plan := tx.newPlan(cluster, clients[cluster])
+		network := &l2smv1.L2Network{
+			TypeMeta:   metav1.TypeMeta{Kind: "L2Network", APIVersion: l2smv1.GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{Name: "l2network-sample"},
+		}
+		if err := plan.add(l2sminterface.GetGVR(l2sminterface.L2Network), "default", network); err != nil {
+			t.Fatalf("could not plan network: %v", err)
+		}
+	}
+	return tx
+}
+
+func failApplies(client *dynamicfake.FakeDynamicClient) {
+	client.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("cluster unavailable")
+	})
+}
+
+func networkExists(client *dynamicfake.FakeDynamicClient) (bool, map[string]string) {
+	obj, err := client.Resource(l2sminterface.GetGVR(l2sminterface.L2Network)).Namespace("default").Get(context.Background(), "l2network-sample", metav1.GetOptions{})
+	if err != nil {
+		return false, nil
+	}
+	return true, obj.GetAnnotations()
+}
+
+func TestTransactionApply(t *testing.T) {
+	clients := map[string]*dynamicfake.FakeDynamicClient{"a": newFakeDynamicClient(), "b": newFakeDynamicClient()}
+
+	results, err := newNetworkTransaction(t, false, clients, "a", "b").apply(context.Background())
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	for _, result := range results {
+		if result.State != ClusterApplied || len(result.Objects) != 1 {
+			t.Errorf("expected cluster %s to be applied with one object, got %+v", result.Cluster, result)
+		}
+		if exists, _ := networkExists(clients[result.Cluster]); !exists {
+			t.Errorf("expected the network to exist in cluster %s", result.Cluster)
+		}
+	}
+}
+
+// TestTransactionRollback checks that a failure deletes what was created and reports every cluster state.
+func TestTransactionRollback(t *testing.T) {
+	clients := map[string]*dynamicfake.FakeDynamicClient{"a": newFakeDynamicClient(), "b": newFakeDynamicClient(), "c": newFakeDynamicClient()}
+	failApplies(clients["b"])
+
+	results, err := newNetworkTransaction(t, false, clients, "a", "b", "c").apply(context.Background())
+	if err == nil {
+		t.Fatalf("expected apply to fail")
+	}
+
+	expected := map[string]ClusterState{"a": ClusterRolledBack, "b": ClusterFailed, "c": ClusterNotApplied}
+	for _, result := range results {
+		if result.State != expected[result.Cluster] {
+			t.Errorf("expected cluster %s to be %s, got %s", result.Cluster, expected[result.Cluster], result.State)
+		}
+		if len(result.Objects) != 0 {
+			t.Errorf("expected no objects left in cluster %s, got %v", result.Cluster, result.Objects)
+		}
+		if (result.Err != nil) != (result.Cluster == "b") {
+			t.Errorf("unexpected error for cluster %s: %v", result.Cluster, result.Err)
+		}
+	}
+	if exists, _ := networkExists(clients["a"]); exists {
+		t.Errorf("expected the network to be rolled back in cluster a")
+	}
+}
+
+// TestTransactionKeepPartial checks that created objects are annotated instead of deleted.
+func TestTransactionKeepPartial(t *testing.T) {
+	clients := map[string]*dynamicfake.FakeDynamicClient{"a": newFakeDynamicClient(), "b": newFakeDynamicClient()}
+	failApplies(clients["b"])
+
+	results, err := newNetworkTransaction(t, true, clients, "a", "b").apply(context.Background())
+	if err == nil {
+		t.Fatalf("expected apply to fail")
+	}
+	if results[0].State != ClusterPartial || len(results[0].Objects) != 1 {
+		t.Errorf("expected cluster a to be partial with one object, got %+v", results[0])
+	}
+	exists, annotations := networkExists(clients["a"])
+	if !exists || annotations[PartialAnnotation] != "true" {
+		t.Errorf("expected the network to be kept and annotated in cluster a, got %v", annotations)
+	}
+}
+
+// TestTransactionIdempotent checks that applying twice converges, that a failure keeps the objects that
+// existed before, and that a successful apply clears the partial mark.
+func TestTransactionIdempotent(t *testing.T) {
+	clients := map[string]*dynamicfake.FakeDynamicClient{"a": newFakeDynamicClient(), "b": newFakeDynamicClient()}
+
+	if _, err := newNetworkTransaction(t, true, clients, "a").apply(context.Background()); err != nil {
+		t.Fatalf("first apply failed: %v", err)
+	}
+	if _, err := newNetworkTransaction(t, true, clients, "a").apply(context.Background()); err != nil {
+		t.Fatalf("second apply failed: %v", err)
+	}
+
+	failing := newFakeDynamicClient()
+	failApplies(failing)
+	results, err := newNetworkTransaction(t, false, map[string]*dynamicfake.FakeDynamicClient{"a": clients["a"], "b": failing}, "a", "b").apply(context.Background())
+	if err == nil {
+		t.Fatalf("expected apply to fail")
+	}
+	if results[0].State != ClusterRolledBack || len(results[0].Objects) != 1 {
+		t.Errorf("expected cluster a to keep its existing network, got %+v", results[0])
+	}
+	if exists, _ := networkExists(clients["a"]); !exists {
+		t.Errorf("expected the existing network not to be rolled back")
+	}
+
+	if _, err := newNetworkTransaction(t, true, map[string]*dynamicfake.FakeDynamicClient{"a": clients["a"], "b": failing}, "a", "b").apply(context.Background()); err == nil {
+		t.Fatalf("expected apply to fail")
+	}
+	if _, annotations := networkExists(clients["a"]); annotations[PartialAnnotation] != "true" {
+		t.Errorf("expected the network to be marked as partial, got %v", annotations)
+	}
+	if _, err := newNetworkTransaction(t, true, clients, "a").apply(context.Background()); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	if _, annotations := networkExists(clients["a"]); annotations[PartialAnnotation] != "" {
+		t.Errorf("expected the partial mark to be cleared, got %v", annotations)
+	}
+}
+
+// TestTransactionCanceled checks that nothing is applied once the request is canceled.
+func TestTransactionCanceled(t *testing.T) {
+	clients := map[string]*dynamicfake.FakeDynamicClient{"a": newFakeDynamicClient(), "b": newFakeDynamicClient()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := newNetworkTransaction(t, false, clients, "a", "b").apply(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the apply to be canceled, got %v", err)
+	}
+	for _, result := range results {
+		if len(result.Objects) != 0 {
+			t.Errorf("expected nothing applied in cluster %s, got %v", result.Cluster, result.Objects)
+		}
+		if exists, _ := networkExists(clients[result.Cluster]); exists {
+			t.Errorf("expected no network in cluster %s", result.Cluster)
+		}
+	}
+}
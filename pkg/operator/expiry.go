@@ -0,0 +1,167 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/tools/clientcmd"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// CACertificate is the CA certificate a member cluster is trusted with.
+	CACertificate = "ca"
+	// ClientCertificate is the client certificate used to authenticate in a member cluster.
+	ClientCertificate = "client"
+
+	// DefaultExpiryWarning is how long before a certificate expires it is warned about by default.
+	DefaultExpiryWarning = 30 * 24 * time.Hour
+	// DefaultExpiryCheckInterval is how often the certificates are checked by default.
+	DefaultExpiryCheckInterval = time.Hour
+)
+
+// certificateExpiry exports when the certificates of the member clusters expire, so that alerts can be
+// raised ahead of it.
+var certificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "l2sces_member_certificate_expiry_timestamp_seconds",
+	Help: "Time at which a certificate of a registered member cluster expires, in seconds since the epoch.",
+}, []string{"cluster", "certificate"})
+
+func init() {
+	metrics.Registry.MustRegister(certificateExpiry)
+}
+
+// CertificateExpiry returns when the first of the PEM encoded certificates expires.
+func CertificateExpiry(pemData []byte) (time.Time, error) {
+	var expiry time.Time
+	for block, rest := pem.Decode(pemData); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid certificate: %v", err)
+		}
+		if expiry.IsZero() || certificate.NotAfter.Before(expiry) {
+			expiry = certificate.NotAfter
+		}
+	}
+	if expiry.IsZero() {
+		return time.Time{}, errors.New("no PEM encoded certificate found")
+	}
+	return expiry, nil
+}
+
+// CertificateExpiries returns when the CA and client certificates of the credentials expire, by
+// CACertificate and ClientCertificate. Certificates referenced by file in a kubeconfig are not read.
+func (credentials ClusterCredentials) CertificateExpiries() (map[string]time.Time, error) {
+	caData, certData := credentials.CAData, credentials.CertData
+	if len(credentials.Kubeconfig) > 0 {
+		config, err := clientcmd.RESTConfigFromKubeConfig(credentials.Kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig: %v", err)
+		}
+		caData, certData = config.CAData, config.CertData
+	}
+
+	expiries := map[string]time.Time{}
+	var errs []error
+	for name, data := range map[string][]byte{CACertificate: caData, ClientCertificate: certData} {
+		if len(data) == 0 {
+			continue
+		}
+		expiry, err := CertificateExpiry(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s certificate: %v", name, err))
+			continue
+		}
+		expiries[name] = expiry
+	}
+	return expiries, errors.Join(errs...)
+}
+
+// ExpiryMonitor periodically checks the certificates of the registered member clusters. It exports when they
+// expire as a metric, and warns about the ones expiring soon. It runs as a Runnable of a manager.
+type ExpiryMonitor struct {
+	Store *CertStore
+	// WarnBefore is how long before a certificate expires it is warned about. Defaults to DefaultExpiryWarning.
+	WarnBefore time.Duration
+	// Interval is how often the certificates are checked. Defaults to DefaultExpiryCheckInterval.
+	Interval time.Duration
+}
+
+// Start checks the certificates until ctx is done.
+func (monitor *ExpiryMonitor) Start(ctx context.Context) error {
+	interval := monitor.Interval
+	if interval <= 0 {
+		interval = DefaultExpiryCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		monitor.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check checks the certificates once, and returns the clusters with a certificate expiring soon.
+func (monitor *ExpiryMonitor) Check(ctx context.Context) []string {
+	log := logf.FromContext(ctx).WithName("certificate-expiry")
+	warnBefore := monitor.WarnBefore
+	if warnBefore <= 0 {
+		warnBefore = DefaultExpiryWarning
+	}
+
+	clusters, err := monitor.Store.List(ctx)
+	if err != nil {
+		log.Error(err, "could not check the certificates of the member clusters")
+		return nil
+	}
+
+	// Clusters that were removed must not keep reporting their certificates
+	certificateExpiry.Reset()
+	expiring := []string{}
+	for _, cluster := range clusters {
+		expiries, err := cluster.Credentials.CertificateExpiries()
+		if err != nil {
+			log.Error(err, "could not read the certificates", "cluster", cluster.Name, "secret", cluster.Namespace+"/"+cluster.SecretName)
+		}
+		soon := false
+		for certificate, expiry := range expiries {
+			certificateExpiry.WithLabelValues(cluster.Name, certificate).Set(float64(expiry.Unix()))
+			if remaining := time.Until(expiry); remaining < warnBefore {
+				log.Info("WARNING: certificate of member cluster expires soon", "cluster", cluster.Name,
+					"certificate", certificate, "expiry", expiry, "remaining", remaining.Round(time.Minute))
+				soon = true
+			}
+		}
+		if soon {
+			expiring = append(expiring, cluster.Name)
+		}
+	}
+	return expiring
+}
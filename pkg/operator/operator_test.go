@@ -0,0 +1,238 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newTestCert(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "test-ca"},
+		NotBefore: time.Now().Add(-time.Hour), NotAfter: notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestCertStore(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewSimpleClientset()
+	store := &CertStore{Clientset: clientset, Namespace: "l2sces"}
+
+	created, err := store.Apply(ctx, "cluster-a", ClusterCredentials{Server: "https://a:6443", Token: "first"})
+	if err != nil || !created {
+		t.Fatalf("expected the secret to be created, got %v, %v", created, err)
+	}
+	created, err = store.Apply(ctx, "cluster-a", ClusterCredentials{Server: "https://a:6443", CertData: []byte("cert"), KeyData: []byte("key")})
+	if err != nil || created {
+		t.Fatalf("expected the secret to be updated, got %v, %v", created, err)
+	}
+	credentials, err := store.Get(ctx, "cluster-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.Token != "" || string(credentials.CertData) != "cert" || string(credentials.KeyData) != "key" {
+		t.Errorf("expected the credentials to be replaced, got %+v", credentials)
+	}
+	config, err := credentials.RestConfig()
+	if err != nil {
+		t.Fatalf("expected a client certificate to be enough: %v", err)
+	}
+	if string(config.CertData) != "cert" {
+		t.Errorf("expected the client certificate in the rest config, got %q", config.CertData)
+	}
+
+	// Clusters registered in other namespaces are out of the scope of the store
+	other := &CertStore{Clientset: clientset, Namespace: "other"}
+	if _, err := other.Apply(ctx, "cluster-b", ClusterCredentials{CAData: []byte("ca")}); err != nil {
+		t.Fatal(err)
+	}
+	clusters, err := store.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 || clusters[0].Name != "cluster-a" || clusters[0].SecretName != "cluster-a-cert" {
+		t.Errorf("expected only cluster-a, got %+v", clusters)
+	}
+	all := &CertStore{Clientset: clientset}
+	if clusters, _ := all.List(ctx); len(clusters) != 2 {
+		t.Errorf("expected the clusters of every namespace, got %+v", clusters)
+	}
+	if _, err := all.Apply(ctx, "cluster-c", ClusterCredentials{}); err == nil {
+		t.Error("expected a store without namespace to refuse writing")
+	}
+
+	if err := store.Delete(ctx, "cluster-a"); err != nil {
+		t.Fatal(err)
+	}
+	if credentials, err := store.Get(ctx, "cluster-a"); err != nil || credentials != nil {
+		t.Errorf("expected cluster-a to be removed, got %+v, %v", credentials, err)
+	}
+	if err := store.Delete(ctx, "cluster-a"); err == nil {
+		t.Error("expected deleting an unregistered cluster to fail")
+	}
+}
+
+func TestCertificateExpiry(t *testing.T) {
+	soon := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	later := time.Now().Add(365 * 24 * time.Hour).Truncate(time.Second)
+	bundle := append(newTestCert(t, later), newTestCert(t, soon)...)
+
+	expiry, err := CertificateExpiry(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expiry.Equal(soon) {
+		t.Errorf("expected the first certificate to expire to be reported, got %v", expiry)
+	}
+	if _, err := CertificateExpiry([]byte("not a certificate")); err == nil {
+		t.Error("expected invalid data to fail")
+	}
+}
+
+func TestExpiryMonitor(t *testing.T) {
+	ctx := context.Background()
+	store := &CertStore{Clientset: fake.NewSimpleClientset(), Namespace: "l2sces"}
+	for clusterName, notAfter := range map[string]time.Time{
+		"expiring": time.Now().Add(24 * time.Hour),
+		"valid":    time.Now().Add(365 * 24 * time.Hour),
+	} {
+		if _, err := store.Apply(ctx, clusterName, ClusterCredentials{CAData: newTestCert(t, notAfter)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A client certificate expiring soon is warned about as well
+	if _, err := store.Apply(ctx, "client", ClusterCredentials{
+		CAData: newTestCert(t, time.Now().Add(365*24*time.Hour)), CertData: newTestCert(t, time.Now().Add(time.Hour)), KeyData: []byte("key"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	monitor := &ExpiryMonitor{Store: store, WarnBefore: 7 * 24 * time.Hour}
+	expiring := monitor.Check(ctx)
+	if len(expiring) != 2 || expiring[0] == "valid" || expiring[1] == "valid" {
+		t.Errorf("expected the expiring and client clusters to be warned about, got %v", expiring)
+	}
+	if count := testutil.CollectAndCount(certificateExpiry); count != 4 {
+		t.Errorf("expected an expiry metric for every certificate, got %d", count)
+	}
+}
+
+func TestRegisterCluster(t *testing.T) {
+	ctx := context.Background()
+	caData := newTestCert(t, time.Now().Add(365*24*time.Hour))
+	store := &CertStore{Clientset: fake.NewSimpleClientset(), Namespace: "l2sces"}
+
+	// The CA is discovered from the cluster when the kubeconfig has none
+	member := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: rootCAConfigMap, Namespace: metav1.NamespaceDefault},
+		Data:       map[string]string{rootCAKey: string(caData)},
+	})
+	member.PrependReactor("create", "serviceaccounts", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		tokenRequest := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenRequest)
+		tokenRequest.Status.Token = "minted-token"
+		return true, tokenRequest, nil
+	})
+	config := &rest.Config{Host: "https://cluster-a:6443", BearerToken: "admin-token"}
+	registration, err := registerCluster(ctx, store, "cluster-a", config, member, RegistrationOptions{CreateServiceAccount: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !registration.Created || registration.ServiceAccount != "l2sm-system/l2sces" {
+		t.Errorf("expected the service account to be created, got %+v", registration)
+	}
+	credentials, err := store.Get(ctx, "cluster-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.Server != config.Host || credentials.Token != "minted-token" || string(credentials.CAData) != string(caData) {
+		t.Errorf("expected the server, discovered CA and minted token to be stored, got %+v", credentials)
+	}
+	binding, err := member.RbacV1().ClusterRoleBindings().Get(ctx, "l2sces-member-l2sces", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binding.RoleRef.Name != MemberRoleName || binding.Subjects[0].Namespace != "l2sm-system" {
+		t.Errorf("expected the service account to be bound to %s, got %+v", MemberRoleName, binding)
+	}
+
+	// Without a service account, the credentials of the kubeconfig are stored
+	config = &rest.Config{Host: "https://cluster-b:6443", BearerToken: "admin-token", TLSClientConfig: rest.TLSClientConfig{CAData: caData}}
+	if _, err := registerCluster(ctx, store, "cluster-b", config, fake.NewSimpleClientset(), RegistrationOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if credentials, _ := store.Get(ctx, "cluster-b"); credentials == nil || credentials.Token != "admin-token" {
+		t.Errorf("expected the token of the kubeconfig to be stored, got %+v", credentials)
+	}
+	config.BearerToken = ""
+	if _, err := registerCluster(ctx, store, "cluster-c", config, fake.NewSimpleClientset(), RegistrationOptions{}); !errors.Is(err, ErrInvalidKubeconfig) {
+		t.Errorf("expected a kubeconfig without credentials to be refused, got %v", err)
+	}
+}
+
+func TestRegisterClusterUntrusted(t *testing.T) {
+	kubeconfig := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: cluster-a
+  cluster:
+    server: https://cluster-a:6443
+users:
+- name: admin
+  user:
+    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token
+contexts:
+- name: cluster-a
+  context:
+    cluster: cluster-a
+    user: admin
+current-context: cluster-a
+`)
+	store := &CertStore{Clientset: fake.NewSimpleClientset(), Namespace: "l2sces"}
+	_, err := RegisterCluster(context.Background(), store, "cluster-a", kubeconfig, RegistrationOptions{Untrusted: true})
+	if !errors.Is(err, ErrInvalidKubeconfig) {
+		t.Errorf("expected a kubeconfig referencing files to be refused, got %v", err)
+	}
+}
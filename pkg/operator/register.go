@@ -0,0 +1,287 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/l2sminterface"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
+)
+
+const (
+	// MemberRoleName is the ClusterRole created in the member clusters with the access l2sces needs in them.
+	MemberRoleName = "l2sces-member"
+	// DefaultServiceAccountNamespace is the namespace the ServiceAccount of l2sces is created in by default.
+	DefaultServiceAccountNamespace = "l2sm-system"
+	// DefaultServiceAccountName is the name of the ServiceAccount of l2sces by default.
+	DefaultServiceAccountName = "l2sces"
+	// DefaultTokenDuration is how long the token minted for the ServiceAccount is valid by default.
+	DefaultTokenDuration = 365 * 24 * time.Hour
+
+	// rootCAConfigMap is published in every namespace by Kubernetes, with the CA of the API server.
+	rootCAConfigMap = "kube-root-ca.crt"
+	rootCAKey       = "ca.crt"
+)
+
+// ErrInvalidKubeconfig is wrapped by the errors of RegisterCluster caused by the kubeconfig it was given.
+var ErrInvalidKubeconfig = errors.New("invalid kubeconfig")
+
+// RegistrationOptions tell how a member cluster is registered.
+type RegistrationOptions struct {
+	// Context is the context of the kubeconfig used to reach the cluster, its current one if empty.
+	Context string
+	// CreateServiceAccount creates a ServiceAccount in the cluster, bound to MemberRoleName, and stores a token
+	// minted for it instead of the credentials of the kubeconfig.
+	CreateServiceAccount bool
+	// ServiceAccountNamespace defaults to DefaultServiceAccountNamespace.
+	ServiceAccountNamespace string
+	// ServiceAccountName defaults to DefaultServiceAccountName.
+	ServiceAccountName string
+	// TokenDuration defaults to DefaultTokenDuration. The cluster may mint a token valid for less.
+	TokenDuration time.Duration
+	// Untrusted refuses kubeconfigs that reference files or run auth plugins, as those would be read or run
+	// by whoever registers the cluster. It must be set for kubeconfigs sent by remote callers.
+	Untrusted bool
+}
+
+// Registration reports how a member cluster was registered.
+type Registration struct {
+	Server string
+	// ServiceAccount is the ServiceAccount created in the cluster as namespace/name, if any.
+	ServiceAccount string
+	// TokenExpiry is when the token minted for the ServiceAccount expires.
+	TokenExpiry time.Time
+	// Created tells whether the cluster was registered for the first time, instead of updated.
+	Created bool
+}
+
+// RegisterCluster stores the credentials of a member cluster in the store, taken from a kubeconfig that can
+// reach it. The server and CA come from the kubeconfig; a kubeconfig without CA has it discovered from the
+// kube-root-ca.crt ConfigMap of the cluster. Unless a ServiceAccount is created, the kubeconfig must hold
+// its token or client certificate inline, as exec plugins and files can't be used by l2sces.
+func RegisterCluster(ctx context.Context, store *CertStore, clusterName string, kubeconfig []byte, options RegistrationOptions) (*Registration, error) {
+	kubeconfigData, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKubeconfig, err)
+	}
+	config, err := clientcmd.NewNonInteractiveClientConfig(*kubeconfigData, options.Context, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKubeconfig, err)
+	}
+	if options.Untrusted {
+		if err := checkSelfContained(config); err != nil {
+			return nil, err
+		}
+	}
+	member, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("could not create member cluster client: %v", err)
+	}
+	return registerCluster(ctx, store, clusterName, config, member, options)
+}
+
+func registerCluster(ctx context.Context, store *CertStore, clusterName string, config *rest.Config, member kubernetes.Interface, options RegistrationOptions) (*Registration, error) {
+	caData, err := discoverCA(ctx, config, member)
+	if err != nil {
+		return nil, err
+	}
+	registration := &Registration{Server: config.Host}
+	credentials := ClusterCredentials{Server: config.Host, CAData: caData}
+
+	if options.CreateServiceAccount {
+		credentials.Token, err = createServiceAccount(ctx, member, options, registration)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if credentials.Token, err = readInline(config.BearerToken, config.BearerTokenFile); err != nil {
+			return nil, err
+		}
+		if credentials.CertData, err = readInlineBytes(config.CertData, config.CertFile); err != nil {
+			return nil, err
+		}
+		if credentials.KeyData, err = readInlineBytes(config.KeyData, config.KeyFile); err != nil {
+			return nil, err
+		}
+		if credentials.Token == "" && (len(credentials.CertData) == 0 || len(credentials.KeyData) == 0) {
+			return nil, fmt.Errorf("%w: it has no token or client certificate to register, create a service account instead", ErrInvalidKubeconfig)
+		}
+	}
+
+	registration.Created, err = store.Apply(ctx, clusterName, credentials)
+	if err != nil {
+		return nil, err
+	}
+	return registration, nil
+}
+
+// discoverCA returns the CA of the cluster from the kubeconfig or, if it has none, from the cluster itself.
+func discoverCA(ctx context.Context, config *rest.Config, member kubernetes.Interface) ([]byte, error) {
+	caData, err := readInlineBytes(config.CAData, config.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(caData) == 0 {
+		configMap, err := member.CoreV1().ConfigMaps(metav1.NamespaceDefault).Get(ctx, rootCAConfigMap, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("the kubeconfig has no CA and it could not be discovered: %v", err)
+		}
+		caData = []byte(configMap.Data[rootCAKey])
+	}
+	if _, err := CertificateExpiry(caData); err != nil {
+		return nil, fmt.Errorf("invalid CA of the cluster: %v", err)
+	}
+	return caData, nil
+}
+
+// createServiceAccount creates the ServiceAccount of l2sces in the member cluster, bound to MemberRoleName,
+// and returns a token minted for it.
+func createServiceAccount(ctx context.Context, member kubernetes.Interface, options RegistrationOptions, registration *Registration) (string, error) {
+	namespace := utils.DefaultIfEmpty(options.ServiceAccountNamespace, DefaultServiceAccountNamespace)
+	name := utils.DefaultIfEmpty(options.ServiceAccountName, DefaultServiceAccountName)
+	duration := options.TokenDuration
+	if duration <= 0 {
+		duration = DefaultTokenDuration
+	}
+
+	_, err := member.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("could not create namespace %s: %v", namespace, err)
+	}
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	_, err = member.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccount, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("could not create service account %s/%s: %v", namespace, name, err)
+	}
+	if err := applyMemberRole(ctx, member); err != nil {
+		return "", err
+	}
+	if err := applyMemberRoleBinding(ctx, member, namespace, name); err != nil {
+		return "", err
+	}
+
+	expirationSeconds := int64(duration.Seconds())
+	tokenRequest, err := member.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("could not mint a token for service account %s/%s: %v", namespace, name, err)
+	}
+	if tokenRequest.Status.Token == "" {
+		return "", fmt.Errorf("no token was minted for service account %s/%s", namespace, name)
+	}
+	registration.ServiceAccount = namespace + "/" + name
+	registration.TokenExpiry = tokenRequest.Status.ExpirationTimestamp.Time
+	return tokenRequest.Status.Token, nil
+}
+
+// memberRules are the permissions l2sces needs in a member cluster: managing the L2S-M objects of the
+// networks and slices.
+func memberRules() []rbacv1.PolicyRule {
+	resources := []string{}
+	for _, resource := range []l2sminterface.ResourceType{l2sminterface.L2Network, l2sminterface.NetworkEdgeDevice, l2sminterface.Overlay} {
+		resources = append(resources, l2sminterface.GetGVR(resource).Resource)
+	}
+	return []rbacv1.PolicyRule{{
+		APIGroups: []string{l2sminterface.GetGVR(l2sminterface.L2Network).Group},
+		Resources: resources,
+		Verbs:     []string{"get", "list", "watch", "create", "update", "patch", "delete"},
+	}}
+}
+
+func applyMemberRole(ctx context.Context, member kubernetes.Interface) error {
+	roles := member.RbacV1().ClusterRoles()
+	role, err := roles.Get(ctx, MemberRoleName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		role = &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: MemberRoleName}, Rules: memberRules()}
+		_, err = roles.Create(ctx, role, metav1.CreateOptions{})
+	} else if err == nil {
+		role.Rules = memberRules()
+		_, err = roles.Update(ctx, role, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("could not apply cluster role %s: %v", MemberRoleName, err)
+	}
+	return nil
+}
+
+func applyMemberRoleBinding(ctx context.Context, member kubernetes.Interface, namespace string, name string) error {
+	bindingName := MemberRoleName + "-" + name
+	subject := rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Namespace: namespace, Name: name}
+	bindings := member.RbacV1().ClusterRoleBindings()
+	binding, err := bindings.Get(ctx, bindingName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		binding = &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: bindingName},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: MemberRoleName},
+			Subjects:   []rbacv1.Subject{subject},
+		}
+		_, err = bindings.Create(ctx, binding, metav1.CreateOptions{})
+	} else if err == nil {
+		if binding.RoleRef.Name != MemberRoleName {
+			return fmt.Errorf("cluster role binding %s already binds cluster role %s", bindingName, binding.RoleRef.Name)
+		}
+		binding.Subjects = []rbacv1.Subject{subject}
+		_, err = bindings.Update(ctx, binding, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("could not apply cluster role binding %s: %v", bindingName, err)
+	}
+	return nil
+}
+
+// checkSelfContained fails if the config references files or auth plugins.
+func checkSelfContained(config *rest.Config) error {
+	for _, file := range []string{config.CAFile, config.CertFile, config.KeyFile, config.BearerTokenFile} {
+		if file != "" {
+			return fmt.Errorf("%w: it references the file %s, its content must be inline", ErrInvalidKubeconfig, file)
+		}
+	}
+	if config.ExecProvider != nil || config.AuthProvider != nil {
+		return fmt.Errorf("%w: it uses an auth plugin, it must hold a token or client certificate", ErrInvalidKubeconfig)
+	}
+	return nil
+}
+
+// readInline returns a value of the kubeconfig, read from its file if it is referenced by path.
+func readInline(value string, file string) (string, error) {
+	data, err := readInlineBytes([]byte(value), file)
+	return string(data), err
+}
+
+func readInlineBytes(data []byte, file string) ([]byte, error) {
+	if len(data) > 0 || file == "" {
+		return data, nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s referenced by the kubeconfig: %v", file, err)
+	}
+	return data, nil
+}
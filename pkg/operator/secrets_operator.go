@@ -19,59 +19,266 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
-func GetClusterCertificates(clusterConfig *rest.Config) (map[string][]byte, error) {
+const (
+	// CertLabel is the label that marks a member cluster Secret. Its value is the cluster name.
+	CertLabel = "l2sm-cert"
 
-	clusterList := make(map[string][]byte)
+	certValueKey  = "cert-value"
+	serverKey     = "server"
+	tokenKey      = "token"
+	kubeconfigKey = "kubeconfig"
+	clientCertKey = "client-cert"
+	clientKeyKey  = "client-key"
+)
 
-	// creates the clientset
-	clientset, err := kubernetes.NewForConfig(clusterConfig)
+// ClusterCredentials holds what is needed to reach the API server of a member cluster: either a
+// kubeconfig, or the server URL, the CA certificate and a bearer token or a client certificate and key.
+type ClusterCredentials struct {
+	Server     string
+	Token      string
+	CAData     []byte
+	Kubeconfig []byte
+	// CertData and KeyData are the PEM encoded client certificate and key.
+	CertData []byte
+	KeyData  []byte
+}
+
+// RestConfig builds the rest config of the cluster. The kubeconfig, if any, takes precedence.
+func (credentials ClusterCredentials) RestConfig() (*rest.Config, error) {
+	if len(credentials.Kubeconfig) > 0 {
+		return clientcmd.RESTConfigFromKubeConfig(credentials.Kubeconfig)
+	}
+	hasClientCert := len(credentials.CertData) > 0 && len(credentials.KeyData) > 0
+	if credentials.Server == "" || (credentials.Token == "" && !hasClientCert) {
+		return nil, fmt.Errorf("missing the %q key, or both the %q key and the %q and %q keys", serverKey, tokenKey, clientCertKey, clientKeyKey)
+	}
+	return &rest.Config{
+		Host:        credentials.Server,
+		BearerToken: credentials.Token,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData:   credentials.CAData,
+			CertData: credentials.CertData,
+			KeyData:  credentials.KeyData,
+		},
+	}, nil
+}
 
+// RegisteredCluster is a member cluster registered in the management cluster, with the Secret holding its
+// credentials.
+type RegisteredCluster struct {
+	Name        string
+	Namespace   string
+	SecretName  string
+	Credentials ClusterCredentials
+}
+
+// CertStore manages the Secrets holding the credentials of the member clusters, labeled with CertLabel.
+type CertStore struct {
+	Clientset kubernetes.Interface
+	// Namespace scopes the Secrets looked up, every namespace if empty. Secrets can only be written when
+	// it is set.
+	Namespace string
+}
+
+// NewCertStore returns a CertStore of the Secrets of the given cluster, scoped to the namespace.
+func NewCertStore(clusterConfig *rest.Config, namespace string) (*CertStore, error) {
+	clientset, err := kubernetes.NewForConfig(clusterConfig)
 	if err != nil {
-		return map[string][]byte{}, err
+		return nil, fmt.Errorf("could not create new cluster client: %v", err)
 	}
+	return &CertStore{Clientset: clientset, Namespace: namespace}, nil
+}
 
-	secrets, err := clientset.CoreV1().Secrets("").List(context.TODO(), metav1.ListOptions{LabelSelector: "l2sm-cert"})
+// Get returns the credentials of a member cluster, or nil if it is not registered.
+func (store *CertStore) Get(ctx context.Context, clusterName string) (*ClusterCredentials, error) {
+	secrets, err := store.secrets(ctx, clusterName)
 	if err != nil {
-		return map[string][]byte{}, err
+		return nil, err
 	}
-	for _, secret := range secrets.Items {
-		clusterList[secret.Labels["l2sm-cert"]] = secret.Data["cert-value"]
+	if len(secrets) == 0 {
+		return nil, nil
 	}
+	credentials := credentialsFromSecret(&secrets[0])
+	return &credentials, nil
+}
 
-	return clusterList, nil
+// List returns the member clusters registered in the namespace of the store.
+func (store *CertStore) List(ctx context.Context) ([]RegisteredCluster, error) {
+	secrets, err := store.secrets(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	clusters := make([]RegisteredCluster, 0, len(secrets))
+	for index := range secrets {
+		secret := &secrets[index]
+		clusters = append(clusters, RegisteredCluster{
+			Name:        secret.Labels[CertLabel],
+			Namespace:   secret.Namespace,
+			SecretName:  secret.Name,
+			Credentials: credentialsFromSecret(secret),
+		})
+	}
+	return clusters, nil
 }
-func CreateCertificateSecrets(clusterConfig *rest.Config, namespace string, clusterName string, certificateData []byte) error {
 
-	clientset, err := kubernetes.NewForConfig(clusterConfig)
+// Apply stores the credentials of a member cluster, replacing the ones it had. The Secret of the cluster
+// is named <cluster>-cert when it is created. It tells whether the Secret was created.
+func (store *CertStore) Apply(ctx context.Context, clusterName string, credentials ClusterCredentials) (bool, error) {
+	if store.Namespace == "" {
+		return false, fmt.Errorf("no namespace to store the credentials of cluster %s in", clusterName)
+	}
+	existing, err := store.secrets(ctx, clusterName)
 	if err != nil {
-		return fmt.Errorf("could not create new cluster client: %v", err)
+		return false, err
+	}
+
+	secrets := store.Clientset.CoreV1().Secrets(store.Namespace)
+	if len(existing) > 0 {
+		secret := existing[0].DeepCopy()
+		secret.Data = credentials.secretData()
+		if _, err := secrets.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return false, fmt.Errorf("failed to update secret %s: %v", secret.Name, err)
+		}
+		return false, nil
 	}
 
-	// Define the secret
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fmt.Sprintf("%s-cert", clusterName),
 			Labels: map[string]string{
-				"l2sm-cert": clusterName,
+				CertLabel: clusterName,
 			},
 		},
-		Data: map[string][]byte{
-			"cert-value": certificateData,
-		},
+		Data: credentials.secretData(),
 		Type: corev1.SecretTypeOpaque,
 	}
+	if _, err := secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return false, fmt.Errorf("failed to create secret: %v", err)
+	}
+	return true, nil
+}
 
-	// Create the secret
-	_, err = clientset.CoreV1().Secrets(namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+// Delete removes the credentials of a member cluster. It fails if the cluster is not registered.
+func (store *CertStore) Delete(ctx context.Context, clusterName string) error {
+	secrets, err := store.secrets(ctx, clusterName)
 	if err != nil {
-		return fmt.Errorf("failed to create secret: %v", err)
+		return err
+	}
+	if len(secrets) == 0 {
+		return fmt.Errorf("cluster %s is not registered", clusterName)
+	}
+	for _, secret := range secrets {
+		err := store.Clientset.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete secret %s/%s: %v", secret.Namespace, secret.Name, err)
+		}
 	}
-
 	return nil
 }
+
+// secrets lists the Secrets of a member cluster, or of every cluster if clusterName is empty.
+func (store *CertStore) secrets(ctx context.Context, clusterName string) ([]corev1.Secret, error) {
+	selector := CertLabel
+	if clusterName != "" {
+		selector = CertLabel + "=" + clusterName
+	}
+	secrets, err := store.Clientset.CoreV1().Secrets(store.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("could not list the secrets of the member clusters: %v", err)
+	}
+	return secrets.Items, nil
+}
+
+// GetClusterCertificates returns the CA certificates of the member clusters registered in the management
+// cluster, by cluster name.
+func GetClusterCertificates(clusterConfig *rest.Config) (map[string][]byte, error) {
+	store, err := NewCertStore(clusterConfig, "")
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := store.List(context.TODO())
+	if err != nil {
+		return nil, err
+	}
+	certificates := make(map[string][]byte, len(clusters))
+	for _, cluster := range clusters {
+		certificates[cluster.Name] = cluster.Credentials.CAData
+	}
+	return certificates, nil
+}
+
+// GetClusterCredentials returns the credentials of a member cluster registered in the management cluster,
+// or nil if it is not registered. Clusters registered with only a CA certificate need the rest from the request.
+func GetClusterCredentials(ctx context.Context, clusterConfig *rest.Config, clusterName string) (*ClusterCredentials, error) {
+	store, err := NewCertStore(clusterConfig, "")
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(ctx, clusterName)
+}
+
+func credentialsFromSecret(secret *corev1.Secret) ClusterCredentials {
+	return ClusterCredentials{
+		Server:     string(secret.Data[serverKey]),
+		Token:      string(secret.Data[tokenKey]),
+		CAData:     secret.Data[certValueKey],
+		Kubeconfig: secret.Data[kubeconfigKey],
+		CertData:   secret.Data[clientCertKey],
+		KeyData:    secret.Data[clientKeyKey],
+	}
+}
+
+func (credentials ClusterCredentials) secretData() map[string][]byte {
+	data := map[string][]byte{
+		certValueKey: credentials.CAData,
+	}
+	if credentials.Server != "" {
+		data[serverKey] = []byte(credentials.Server)
+	}
+	if credentials.Token != "" {
+		data[tokenKey] = []byte(credentials.Token)
+	}
+	if len(credentials.Kubeconfig) > 0 {
+		data[kubeconfigKey] = credentials.Kubeconfig
+	}
+	if len(credentials.CertData) > 0 {
+		data[clientCertKey] = credentials.CertData
+	}
+	if len(credentials.KeyData) > 0 {
+		data[clientKeyKey] = credentials.KeyData
+	}
+	return data
+}
+
+func CreateCertificateSecrets(clusterConfig *rest.Config, namespace string, clusterName string, certificateData []byte) error {
+	return CreateClusterSecret(clusterConfig, namespace, clusterName, ClusterCredentials{CAData: certificateData})
+}
+
+// CreateClusterSecret stores the credentials of a member cluster in a Secret labeled with CertLabel, replacing
+// the ones it had. Server and Token, or Kubeconfig, let requests and the controllers reference the cluster by
+// name only.
+func CreateClusterSecret(clusterConfig *rest.Config, namespace string, clusterName string, credentials ClusterCredentials) error {
+	store, err := NewCertStore(clusterConfig, namespace)
+	if err != nil {
+		return err
+	}
+	_, err = store.Apply(context.TODO(), clusterName, credentials)
+	return err
+}
+
+// ClusterConfigFromSecret builds the rest config of a member cluster from its CertLabel Secret.
+func ClusterConfigFromSecret(secret *corev1.Secret) (*rest.Config, error) {
+	clusterConfig, err := credentialsFromSecret(secret).RestConfig()
+	if err != nil {
+		return nil, fmt.Errorf("secret %s/%s: %v", secret.Namespace, secret.Name, err)
+	}
+	return clusterConfig, nil
+}
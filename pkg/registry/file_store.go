@@ -0,0 +1,190 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// FileStore keeps every record in a JSON file under a local directory, meant for running the
+// server outside of a cluster.
+type FileStore struct {
+	Directory string
+	mutex     sync.Mutex
+}
+
+func NewFileStore(directory string) (*FileStore, error) {
+	for _, kind := range []string{networkKind, sliceKind} {
+		if err := os.MkdirAll(filepath.Join(directory, kind+"s"), 0700); err != nil {
+			return nil, fmt.Errorf("could not create registry directory: %v", err)
+		}
+	}
+	return &FileStore{Directory: directory}, nil
+}
+
+func (store *FileStore) PutNetwork(ctx context.Context, record *l2sces.NetworkRecord) error {
+	if err := CheckNetworkNamespace(ctx, store, record.GetNetwork().GetName(), record.GetNamespace()); err != nil {
+		return err
+	}
+	return store.put(networkKind, record.GetNetwork().GetName(), record)
+}
+
+func (store *FileStore) GetNetwork(ctx context.Context, name string) (*l2sces.NetworkRecord, error) {
+	record := &l2sces.NetworkRecord{}
+	return record, store.get(networkKind, name, record)
+}
+
+func (store *FileStore) ListNetworks(ctx context.Context) ([]*l2sces.NetworkRecord, error) {
+	records := []*l2sces.NetworkRecord{}
+	err := store.list(networkKind, func(name string) error {
+		record := &l2sces.NetworkRecord{}
+		records = append(records, record)
+		return store.get(networkKind, name, record)
+	})
+	return records, err
+}
+
+func (store *FileStore) DeleteNetwork(ctx context.Context, name string) error {
+	return store.delete(networkKind, name)
+}
+
+func (store *FileStore) PutSlice(ctx context.Context, record *l2sces.SliceRecord) error {
+	if err := CheckSliceNamespace(ctx, store, SliceName(record.GetSlice()), record.GetNamespace()); err != nil {
+		return err
+	}
+	return store.put(sliceKind, SliceName(record.GetSlice()), record)
+}
+
+func (store *FileStore) GetSlice(ctx context.Context, name string) (*l2sces.SliceRecord, error) {
+	record := &l2sces.SliceRecord{}
+	return record, store.get(sliceKind, name, record)
+}
+
+func (store *FileStore) ListSlices(ctx context.Context) ([]*l2sces.SliceRecord, error) {
+	records := []*l2sces.SliceRecord{}
+	err := store.list(sliceKind, func(name string) error {
+		record := &l2sces.SliceRecord{}
+		records = append(records, record)
+		return store.get(sliceKind, name, record)
+	})
+	return records, err
+}
+
+func (store *FileStore) DeleteSlice(ctx context.Context, name string) error {
+	return store.delete(sliceKind, name)
+}
+
+func (store *FileStore) path(kind string, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid %s name %q", kind, name)
+	}
+	return filepath.Join(store.Directory, kind+"s", name+".json"), nil
+}
+
+func (store *FileStore) put(kind string, name string, record proto.Message) error {
+	path, err := store.path(kind, name)
+	if err != nil {
+		return err
+	}
+	data, err := protojson.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("could not encode %s %s: %v", kind, name, err)
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	// Write to a temporary file first so that a crash never leaves a half written record
+	temporary := path + ".tmp"
+	if err := os.WriteFile(temporary, data, 0600); err != nil {
+		return fmt.Errorf("could not store %s %s: %v", kind, name, err)
+	}
+	if err := os.Rename(temporary, path); err != nil {
+		return fmt.Errorf("could not store %s %s: %v", kind, name, err)
+	}
+	return nil
+}
+
+func (store *FileStore) get(kind string, name string, record proto.Message) error {
+	path, err := store.path(kind, name)
+	if err != nil {
+		return err
+	}
+
+	store.mutex.Lock()
+	data, err := os.ReadFile(path)
+	store.mutex.Unlock()
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s %s: %w", kind, name, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get %s %s: %v", kind, name, err)
+	}
+	return protojson.Unmarshal(data, record)
+}
+
+func (store *FileStore) list(kind string, read func(name string) error) error {
+	store.mutex.Lock()
+	entries, err := os.ReadDir(filepath.Join(store.Directory, kind+"s"))
+	store.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("could not list %ss: %v", kind, err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := read(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *FileStore) delete(kind string, name string) error {
+	path, err := store.path(kind, name)
+	if err != nil {
+		return err
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("could not delete %s %s: %v", kind, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+const (
+	// recordLabel marks the Secrets holding registry records. Its value is the kind of record.
+	recordLabel = "l2sces.l2sm.io/record"
+	recordKey   = "record"
+)
+
+// KubernetesStore keeps every record in a Secret of the management cluster. Secrets are used
+// instead of ConfigMaps because the records hold the bearer tokens of the member clusters.
+type KubernetesStore struct {
+	Clientset kubernetes.Interface
+	Namespace string
+}
+
+func NewKubernetesStore(clientset kubernetes.Interface, namespace string) *KubernetesStore {
+	return &KubernetesStore{Clientset: clientset, Namespace: namespace}
+}
+
+func (store *KubernetesStore) PutNetwork(ctx context.Context, record *l2sces.NetworkRecord) error {
+	if err := CheckNetworkNamespace(ctx, store, record.GetNetwork().GetName(), record.GetNamespace()); err != nil {
+		return err
+	}
+	return store.put(ctx, networkKind, record.GetNetwork().GetName(), record)
+}
+
+func (store *KubernetesStore) GetNetwork(ctx context.Context, name string) (*l2sces.NetworkRecord, error) {
+	record := &l2sces.NetworkRecord{}
+	return record, store.get(ctx, networkKind, name, record)
+}
+
+func (store *KubernetesStore) ListNetworks(ctx context.Context) ([]*l2sces.NetworkRecord, error) {
+	records := []*l2sces.NetworkRecord{}
+	err := store.list(ctx, networkKind, func(data []byte) error {
+		record := &l2sces.NetworkRecord{}
+		records = append(records, record)
+		return protojson.Unmarshal(data, record)
+	})
+	return records, err
+}
+
+func (store *KubernetesStore) DeleteNetwork(ctx context.Context, name string) error {
+	return store.delete(ctx, networkKind, name)
+}
+
+func (store *KubernetesStore) PutSlice(ctx context.Context, record *l2sces.SliceRecord) error {
+	if err := CheckSliceNamespace(ctx, store, SliceName(record.GetSlice()), record.GetNamespace()); err != nil {
+		return err
+	}
+	return store.put(ctx, sliceKind, SliceName(record.GetSlice()), record)
+}
+
+func (store *KubernetesStore) GetSlice(ctx context.Context, name string) (*l2sces.SliceRecord, error) {
+	record := &l2sces.SliceRecord{}
+	return record, store.get(ctx, sliceKind, name, record)
+}
+
+func (store *KubernetesStore) ListSlices(ctx context.Context) ([]*l2sces.SliceRecord, error) {
+	records := []*l2sces.SliceRecord{}
+	err := store.list(ctx, sliceKind, func(data []byte) error {
+		record := &l2sces.SliceRecord{}
+		records = append(records, record)
+		return protojson.Unmarshal(data, record)
+	})
+	return records, err
+}
+
+func (store *KubernetesStore) DeleteSlice(ctx context.Context, name string) error {
+	return store.delete(ctx, sliceKind, name)
+}
+
+func secretName(kind string, name string) string {
+	return fmt.Sprintf("l2sces-%s-%s", kind, name)
+}
+
+func (store *KubernetesStore) put(ctx context.Context, kind string, name string, record proto.Message) error {
+	data, err := protojson.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("could not encode %s %s: %v", kind, name, err)
+	}
+
+	secrets := store.Clientset.CoreV1().Secrets(store.Namespace)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   secretName(kind, name),
+			Labels: map[string]string{recordLabel: kind},
+		},
+		Data: map[string][]byte{recordKey: data},
+		Type: corev1.SecretTypeOpaque,
+	}
+
+	_, err = secrets.Create(ctx, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		existing, getErr := secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("could not get %s %s: %v", kind, name, getErr)
+		}
+		existing.Data = secret.Data
+		_, err = secrets.Update(ctx, existing, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("could not store %s %s: %v", kind, name, err)
+	}
+	return nil
+}
+
+func (store *KubernetesStore) get(ctx context.Context, kind string, name string, record proto.Message) error {
+	secret, err := store.Clientset.CoreV1().Secrets(store.Namespace).Get(ctx, secretName(kind, name), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("%s %s: %w", kind, name, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get %s %s: %v", kind, name, err)
+	}
+	return protojson.Unmarshal(secret.Data[recordKey], record)
+}
+
+func (store *KubernetesStore) list(ctx context.Context, kind string, decode func([]byte) error) error {
+	secrets, err := store.Clientset.CoreV1().Secrets(store.Namespace).List(ctx, metav1.ListOptions{LabelSelector: recordLabel + "=" + kind})
+	if err != nil {
+		return fmt.Errorf("could not list %ss: %v", kind, err)
+	}
+	for _, secret := range secrets.Items {
+		if err := decode(secret.Data[recordKey]); err != nil {
+			return fmt.Errorf("could not decode %s: %v", secret.Name, err)
+		}
+	}
+	return nil
+}
+
+func (store *KubernetesStore) delete(ctx context.Context, kind string, name string) error {
+	err := store.Clientset.CoreV1().Secrets(store.Namespace).Delete(ctx, secretName(kind, name), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("could not delete %s %s: %v", kind, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/utils"
+)
+
+type StoreType string
+
+const (
+	KubernetesType StoreType = "kubernetes"
+	FileType       StoreType = "file"
+)
+
+// ErrNotFound is returned when a network or slice is not registered.
+var ErrNotFound = errors.New("not registered")
+
+// ErrNamespaceConflict is returned when a network or slice is registered in another namespace. Records are
+// named after their network or slice only, so one namespace can't replace the record of another.
+var ErrNamespaceConflict = errors.New("registered in another namespace")
+
+// Store keeps the networks and slices created through the gRPC server, so that they can be
+// listed, and updated or deleted by name only.
+type Store interface {
+	PutNetwork(ctx context.Context, record *l2sces.NetworkRecord) error
+	GetNetwork(ctx context.Context, name string) (*l2sces.NetworkRecord, error)
+	ListNetworks(ctx context.Context) ([]*l2sces.NetworkRecord, error)
+	DeleteNetwork(ctx context.Context, name string) error
+	PutSlice(ctx context.Context, record *l2sces.SliceRecord) error
+	GetSlice(ctx context.Context, name string) (*l2sces.SliceRecord, error)
+	ListSlices(ctx context.Context) ([]*l2sces.SliceRecord, error)
+	DeleteSlice(ctx context.Context, name string) error
+}
+
+// SliceName returns the name a slice is registered with, which defaults to its provider name.
+func SliceName(slice *l2sces.Slice) string {
+	return utils.DefaultIfEmpty(slice.GetName(), slice.GetProvider().GetName())
+}
+
+// CheckNetworkNamespace fails with ErrNamespaceConflict if the network is registered in a namespace
+// other than the given one.
+func CheckNetworkNamespace(ctx context.Context, store Store, name string, namespace string) error {
+	record, err := store.GetNetwork(ctx, name)
+	return checkNamespace(networkKind, name, record, err, namespace)
+}
+
+// CheckSliceNamespace fails with ErrNamespaceConflict if the slice is registered in a namespace other
+// than the given one.
+func CheckSliceNamespace(ctx context.Context, store Store, name string, namespace string) error {
+	record, err := store.GetSlice(ctx, name)
+	return checkNamespace(sliceKind, name, record, err, namespace)
+}
+
+func checkNamespace(kind string, name string, record interface{ GetNamespace() string }, err error, namespace string) error {
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	registered := utils.DefaultIfEmpty(record.GetNamespace(), "default")
+	if registered != utils.DefaultIfEmpty(namespace, "default") {
+		return fmt.Errorf("%s %s: %w %s", kind, name, ErrNamespaceConflict, registered)
+	}
+	return nil
+}
+
+const (
+	networkKind = "network"
+	sliceKind   = "slice"
+)
@@ -0,0 +1,136 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+func TestStores(t *testing.T) {
+	fileStore, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	stores := map[string]Store{
+		"file":       fileStore,
+		"kubernetes": NewKubernetesStore(fake.NewSimpleClientset(), "l2sm-system"),
+	}
+
+	for storeName, store := range stores {
+		t.Run(storeName, func(t *testing.T) {
+			testStore(t, store)
+		})
+	}
+}
+
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	network := &l2sces.NetworkRecord{
+		Namespace: "default",
+		Network: &l2sces.L2Network{
+			Name: "l2network-sample",
+			Type: "vnet",
+			Clusters: []*l2sces.Cluster{
+				{Name: "cluster-1", RestConfig: &l2sces.RestConfig{ApiKey: "https://cluster-1:6443", BearerToken: "token"}},
+			},
+		},
+	}
+	slice := &l2sces.SliceRecord{
+		Slice: &l2sces.Slice{
+			Provider: &l2sces.Provider{Name: "test-slice"},
+			Clusters: []*l2sces.Cluster{{Name: "cluster-1"}, {Name: "cluster-2"}},
+		},
+	}
+
+	if _, err := store.GetNetwork(ctx, "l2network-sample"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before creating the network, got %v", err)
+	}
+
+	if err := store.PutNetwork(ctx, network); err != nil {
+		t.Fatalf("PutNetwork failed: %v", err)
+	}
+	if err := store.PutSlice(ctx, slice); err != nil {
+		t.Fatalf("PutSlice failed: %v", err)
+	}
+
+	got, err := store.GetNetwork(ctx, "l2network-sample")
+	if err != nil {
+		t.Fatalf("GetNetwork failed: %v", err)
+	}
+	if !proto.Equal(got, network) {
+		t.Errorf("GetNetwork returned %v, expected %v", got, network)
+	}
+
+	// Slices without a name are registered with their provider name
+	gotSlice, err := store.GetSlice(ctx, "test-slice")
+	if err != nil {
+		t.Fatalf("GetSlice failed: %v", err)
+	}
+	if !proto.Equal(gotSlice, slice) {
+		t.Errorf("GetSlice returned %v, expected %v", gotSlice, slice)
+	}
+
+	// Putting an existing record replaces it
+	slice.Slice.Clusters = slice.Slice.Clusters[:1]
+	if err := store.PutSlice(ctx, slice); err != nil {
+		t.Fatalf("PutSlice of an existing slice failed: %v", err)
+	}
+
+	// Another namespace can't replace it
+	other := proto.Clone(slice).(*l2sces.SliceRecord)
+	other.Namespace = "tenant-b"
+	if err := store.PutSlice(ctx, other); !errors.Is(err, ErrNamespaceConflict) {
+		t.Errorf("expected ErrNamespaceConflict putting the slice in another namespace, got %v", err)
+	}
+	otherNetwork := proto.Clone(network).(*l2sces.NetworkRecord)
+	otherNetwork.Namespace = "tenant-b"
+	if err := store.PutNetwork(ctx, otherNetwork); !errors.Is(err, ErrNamespaceConflict) {
+		t.Errorf("expected ErrNamespaceConflict putting the network in another namespace, got %v", err)
+	}
+
+	slices, err := store.ListSlices(ctx)
+	if err != nil {
+		t.Fatalf("ListSlices failed: %v", err)
+	}
+	if len(slices) != 1 || len(slices[0].GetSlice().GetClusters()) != 1 {
+		t.Errorf("ListSlices returned %v, expected the updated slice", slices)
+	}
+
+	networks, err := store.ListNetworks(ctx)
+	if err != nil {
+		t.Fatalf("ListNetworks failed: %v", err)
+	}
+	if len(networks) != 1 {
+		t.Errorf("ListNetworks returned %d networks, expected 1", len(networks))
+	}
+
+	if err := store.DeleteNetwork(ctx, "l2network-sample"); err != nil {
+		t.Fatalf("DeleteNetwork failed: %v", err)
+	}
+	if err := store.DeleteNetwork(ctx, "l2network-sample"); err != nil {
+		t.Errorf("deleting a missing network should succeed, got %v", err)
+	}
+	if _, err := store.GetNetwork(ctx, "l2network-sample"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after deleting the network, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package topologyanalysis checks the graph the links of a slice form between its clusters: whether it is
+// connected, how many hops it takes to cross it, and which clusters and links it can't lose.
+package topologyanalysis
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// Analysis describes the graph of a slice. Clusters and links are sorted by name.
+type Analysis struct {
+	// Components are the groups of clusters connected to each other, largest first.
+	Components [][]string
+	// Diameter is the number of hops between the two farthest connected clusters.
+	Diameter int
+	// ArticulationPoints are the clusters that split the slice if they fail.
+	ArticulationPoints []string
+	// Bridges are the links that split the slice if they fail.
+	Bridges []*l2sces.Link
+	// Isolated are the clusters without neighbors.
+	Isolated []string
+}
+
+// graph is an undirected graph between the indexes of the clusters, sorted by name.
+type graph struct {
+	names     []string
+	neighbors [][]int
+}
+
+// Analyze analyzes the graph the links form between the clusters. Self-loops, repeated links and links to
+// other clusters are ignored.
+func Analyze(clusters []string, links []*l2sces.Link) *Analysis {
+	g := newGraph(clusters, links)
+	analysis := &Analysis{ArticulationPoints: []string{}, Bridges: []*l2sces.Link{}, Isolated: []string{}}
+	for node, neighbors := range g.neighbors {
+		if len(neighbors) == 0 && len(g.names) > 1 {
+			analysis.Isolated = append(analysis.Isolated, g.names[node])
+		}
+	}
+	analysis.Components = g.components()
+	for node := range g.names {
+		for _, distance := range g.distances(node) {
+			analysis.Diameter = max(analysis.Diameter, distance)
+		}
+	}
+	g.cuts(analysis)
+	return analysis
+}
+
+// Connected tells whether every cluster can reach every other one.
+func (analysis *Analysis) Connected() bool {
+	return len(analysis.Components) <= 1
+}
+
+// Err returns an error describing the disconnected groups of clusters, if any.
+func (analysis *Analysis) Err() error {
+	if analysis.Connected() {
+		return nil
+	}
+	groups := make([]string, len(analysis.Components))
+	for index, component := range analysis.Components {
+		groups[index] = "[" + strings.Join(component, " ") + "]"
+	}
+	return fmt.Errorf("the clusters are split in %d disconnected groups: %s", len(analysis.Components), strings.Join(groups, ", "))
+}
+
+// Warnings describes the single points of failure of the slice.
+func (analysis *Analysis) Warnings() []string {
+	warnings := []string{}
+	for _, cluster := range analysis.ArticulationPoints {
+		warnings = append(warnings, fmt.Sprintf("cluster %s is a single point of failure", cluster))
+	}
+	for _, link := range analysis.Bridges {
+		warnings = append(warnings, fmt.Sprintf("link %s-%s is a single point of failure", link.GetEndpointA(), link.GetEndpointB()))
+	}
+	return warnings
+}
+
+func newGraph(clusters []string, links []*l2sces.Link) *graph {
+	g := &graph{names: append([]string{}, clusters...)}
+	sort.Strings(g.names)
+	g.names = slices.Compact(g.names)
+	indexes := make(map[string]int, len(g.names))
+	for index, name := range g.names {
+		indexes[name] = index
+	}
+
+	linked := map[[2]int]bool{}
+	g.neighbors = make([][]int, len(g.names))
+	for _, link := range links {
+		a, foundA := indexes[link.GetEndpointA()]
+		b, foundB := indexes[link.GetEndpointB()]
+		if !foundA || !foundB || a == b || linked[[2]int{min(a, b), max(a, b)}] {
+			continue
+		}
+		linked[[2]int{min(a, b), max(a, b)}] = true
+		g.neighbors[a] = append(g.neighbors[a], b)
+		g.neighbors[b] = append(g.neighbors[b], a)
+	}
+	for _, neighbors := range g.neighbors {
+		sort.Ints(neighbors)
+	}
+	return g
+}
+
+// distances returns the number of hops from the node to each node it reaches.
+func (g *graph) distances(from int) map[int]int {
+	distances := map[int]int{from: 0}
+	queue := []int{from}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range g.neighbors[node] {
+			if _, seen := distances[next]; !seen {
+				distances[next] = distances[node] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return distances
+}
+
+func (g *graph) components() [][]string {
+	components := [][]string{}
+	assigned := make([]bool, len(g.names))
+	for node := range g.names {
+		if assigned[node] {
+			continue
+		}
+		component := []string{}
+		for reached := range g.distances(node) {
+			assigned[reached] = true
+			component = append(component, g.names[reached])
+		}
+		sort.Strings(component)
+		components = append(components, component)
+	}
+	sort.SliceStable(components, func(i, j int) bool { return len(components[i]) > len(components[j]) })
+	return components
+}
+
+// cuts finds the articulation points and bridges with Tarjan's algorithm: a node is an articulation point,
+// and the link to it a bridge, when its subtree in the depth-first search can't reach above it otherwise.
+func (g *graph) cuts(analysis *Analysis) {
+	order := make([]int, len(g.names))
+	low := make([]int, len(g.names))
+	articulation := make([]bool, len(g.names))
+	visited := 0
+
+	var visit func(node int, parent int)
+	visit = func(node int, parent int) {
+		visited++
+		order[node], low[node] = visited, visited
+		children := 0
+		for _, next := range g.neighbors[node] {
+			switch {
+			case next == parent:
+			case order[next] != 0:
+				low[node] = min(low[node], order[next])
+			default:
+				children++
+				visit(next, node)
+				low[node] = min(low[node], low[next])
+				if parent != -1 && low[next] >= order[node] {
+					articulation[node] = true
+				}
+				if low[next] > order[node] {
+					a, b := min(node, next), max(node, next)
+					analysis.Bridges = append(analysis.Bridges, &l2sces.Link{EndpointA: g.names[a], EndpointB: g.names[b]})
+				}
+			}
+		}
+		if parent == -1 && children > 1 {
+			articulation[node] = true
+		}
+	}
+	for node := range g.names {
+		if order[node] == 0 {
+			visit(node, -1)
+		}
+	}
+
+	for node, isArticulation := range articulation {
+		if isArticulation {
+			analysis.ArticulationPoints = append(analysis.ArticulationPoints, g.names[node])
+		}
+	}
+	sort.Slice(analysis.Bridges, func(i, j int) bool {
+		if analysis.Bridges[i].EndpointA != analysis.Bridges[j].EndpointA {
+			return analysis.Bridges[i].EndpointA < analysis.Bridges[j].EndpointA
+		}
+		return analysis.Bridges[i].EndpointB < analysis.Bridges[j].EndpointB
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topologyanalysis
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// testLinks returns the links given as a-b.
+func testLinks(pairs ...string) []*l2sces.Link {
+	links := make([]*l2sces.Link, len(pairs))
+	for index, pair := range pairs {
+		a, b, _ := strings.Cut(pair, "-")
+		links[index] = &l2sces.Link{EndpointA: a, EndpointB: b}
+	}
+	return links
+}
+
+func linkNames(links []*l2sces.Link) []string {
+	names := make([]string, len(links))
+	for index, link := range links {
+		names[index] = link.GetEndpointA() + "-" + link.GetEndpointB()
+	}
+	return names
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name         string
+		clusters     []string
+		links        []*l2sces.Link
+		components   [][]string
+		diameter     int
+		articulation []string
+		bridges      []string
+		isolated     []string
+	}{
+		{
+			name:         "ring",
+			clusters:     []string{"a", "b", "c", "d"},
+			links:        testLinks("a-b", "b-c", "c-d", "d-a"),
+			components:   [][]string{{"a", "b", "c", "d"}},
+			diameter:     2,
+			articulation: []string{},
+			bridges:      []string{},
+			isolated:     []string{},
+		},
+		{
+			// Two triangles joined through the c-d link
+			name:         "bowtie",
+			clusters:     []string{"f", "e", "d", "c", "b", "a"},
+			links:        testLinks("a-b", "b-c", "c-a", "d-c", "d-e", "e-f", "f-d"),
+			components:   [][]string{{"a", "b", "c", "d", "e", "f"}},
+			diameter:     3,
+			articulation: []string{"c", "d"},
+			bridges:      []string{"c-d"},
+			isolated:     []string{},
+		},
+		{
+			name:         "disconnected",
+			clusters:     []string{"a", "b", "c", "d", "e"},
+			links:        testLinks("a-b", "b-c", "a-a", "b-a"),
+			components:   [][]string{{"a", "b", "c"}, {"d"}, {"e"}},
+			diameter:     2,
+			articulation: []string{"b"},
+			bridges:      []string{"a-b", "b-c"},
+			isolated:     []string{"d", "e"},
+		},
+		{
+			name:         "single cluster",
+			clusters:     []string{"a"},
+			components:   [][]string{{"a"}},
+			articulation: []string{},
+			bridges:      []string{},
+			isolated:     []string{},
+		},
+	}
+
+	for _, test := range tests {
+		analysis := Analyze(test.clusters, test.links)
+		if !reflect.DeepEqual(analysis.Components, test.components) {
+			t.Errorf("%s: expected the components %v, got %v", test.name, test.components, analysis.Components)
+		}
+		if analysis.Diameter != test.diameter {
+			t.Errorf("%s: expected a diameter of %d, got %d", test.name, test.diameter, analysis.Diameter)
+		}
+		if !reflect.DeepEqual(analysis.ArticulationPoints, test.articulation) {
+			t.Errorf("%s: expected the articulation points %v, got %v", test.name, test.articulation, analysis.ArticulationPoints)
+		}
+		if got := linkNames(analysis.Bridges); !reflect.DeepEqual(got, test.bridges) {
+			t.Errorf("%s: expected the bridges %v, got %v", test.name, test.bridges, got)
+		}
+		if !reflect.DeepEqual(analysis.Isolated, test.isolated) {
+			t.Errorf("%s: expected the isolated clusters %v, got %v", test.name, test.isolated, analysis.Isolated)
+		}
+	}
+}
+
+func TestAnalysisErrors(t *testing.T) {
+	analysis := Analyze([]string{"a", "b", "c"}, testLinks("a-b"))
+	if err := analysis.Err(); err == nil || !strings.Contains(err.Error(), "[a b], [c]") {
+		t.Errorf("expected the groups to be described, got %v", err)
+	}
+
+	analysis = Analyze([]string{"a", "b", "c"}, testLinks("a-b", "b-c"))
+	if err := analysis.Err(); err != nil {
+		t.Errorf("expected a connected slice, got %v", err)
+	}
+	expected := []string{"cluster b is a single point of failure", "link a-b is a single point of failure", "link b-c is a single point of failure"}
+	if warnings := analysis.Warnings(); !reflect.DeepEqual(warnings, expected) {
+		t.Errorf("expected %v, got %v", expected, warnings)
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topologygenerator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// DefaultRedundancy is the number of link-disjoint paths MinimumCost keeps between every two nodes, so that
+// they stay connected after any link fails.
+const DefaultRedundancy = 2
+
+// MinimumCost links the nodes through their cheapest candidate links: a minimum spanning tree, plus the
+// cheapest links that give Redundancy link-disjoint paths between every two nodes. Nodes without a cost
+// between them are never linked directly.
+type MinimumCost struct {
+	Costs []*l2sces.LinkCost
+	// Redundancy defaults to DefaultRedundancy, and is lowered to one less than the number of nodes, as
+	// two nodes can only be linked once.
+	Redundancy int
+}
+
+// candidateLink is a link between the nodes of two indexes, the first one the lowest.
+type candidateLink struct {
+	a, b int
+	cost float64
+}
+
+func (minimum MinimumCost) Links(nodes []Node) ([]*l2sces.Link, error) {
+	count := len(nodes)
+	redundancy := minimum.Redundancy
+	switch {
+	case redundancy < 0:
+		return nil, fmt.Errorf("the redundancy of a minimum cost topology can't be negative, got %d", redundancy)
+	case redundancy == 0:
+		redundancy = DefaultRedundancy
+	}
+	if redundancy > count-1 {
+		redundancy = count - 1
+	}
+	if count < 2 {
+		return []*l2sces.Link{}, nil
+	}
+
+	candidates := candidateLinks(nodes, minimum.Costs)
+	selected := []candidateLink{}
+	var rest []candidateLink
+
+	// Kruskal's algorithm: the cheapest link joining two components is always part of the tree
+	components := make([]int, count)
+	for index := range components {
+		components[index] = index
+	}
+	var find func(int) int
+	find = func(index int) int {
+		if components[index] != index {
+			components[index] = find(components[index])
+		}
+		return components[index]
+	}
+	for _, candidate := range candidates {
+		if a, b := find(candidate.a), find(candidate.b); a != b {
+			components[a] = b
+			selected = append(selected, candidate)
+		} else {
+			rest = append(rest, candidate)
+		}
+	}
+	if len(selected) < count-1 {
+		return nil, errors.New("the link costs don't connect every cluster")
+	}
+
+	// Then the cheapest links that add disjoint paths, until every node has enough of them
+	connectivity := pathCount(count, selected, redundancy)
+	for _, candidate := range rest {
+		if connectivity == (count-1)*redundancy {
+			break
+		}
+		if added := pathCount(count, append(selected, candidate), redundancy); added > connectivity {
+			selected, connectivity = append(selected, candidate), added
+		}
+	}
+	if connectivity < (count-1)*redundancy {
+		return nil, fmt.Errorf("the link costs can't give %d link-disjoint paths between every two clusters", redundancy)
+	}
+
+	// A link added early may no longer be needed once the later ones are in, so the most expensive ones
+	// are dropped while the paths are kept
+	sort.Slice(selected, func(i, j int) bool { return cheaper(selected[i], selected[j]) })
+	for index := len(selected) - 1; index >= 0 && redundancy > 1; index-- {
+		without := append(append([]candidateLink{}, selected[:index]...), selected[index+1:]...)
+		if pathCount(count, without, redundancy) == connectivity {
+			selected = without
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool {
+		if selected[i].a != selected[j].a {
+			return selected[i].a < selected[j].a
+		}
+		return selected[i].b < selected[j].b
+	})
+	links := make([]*l2sces.Link, len(selected))
+	for index, link := range selected {
+		links[index] = &l2sces.Link{EndpointA: nodes[link.a].Name, EndpointB: nodes[link.b].Name}
+	}
+	return links, nil
+}
+
+// candidateLinks returns the links with a cost between the nodes, cheapest first. The last cost given for
+// the same two nodes is the one kept.
+func candidateLinks(nodes []Node, costs []*l2sces.LinkCost) []candidateLink {
+	indexes := make(map[string]int, len(nodes))
+	for index, node := range nodes {
+		indexes[node.Name] = index
+	}
+	byPair := map[[2]int]float64{}
+	for _, cost := range costs {
+		a, foundA := indexes[cost.GetEndpointA()]
+		b, foundB := indexes[cost.GetEndpointB()]
+		if !foundA || !foundB || a == b {
+			continue
+		}
+		if a > b {
+			a, b = b, a
+		}
+		byPair[[2]int{a, b}] = cost.GetCost()
+	}
+
+	candidates := make([]candidateLink, 0, len(byPair))
+	for pair, cost := range byPair {
+		candidates = append(candidates, candidateLink{a: pair[0], b: pair[1], cost: cost})
+	}
+	sort.Slice(candidates, func(i, j int) bool { return cheaper(candidates[i], candidates[j]) })
+	return candidates
+}
+
+// cheaper orders links by cost, and then by their nodes.
+func cheaper(x candidateLink, y candidateLink) bool {
+	switch {
+	case x.cost != y.cost:
+		return x.cost < y.cost
+	case x.a != y.a:
+		return x.a < y.a
+	default:
+		return x.b < y.b
+	}
+}
+
+// pathCount adds, for every node but the first, the number of link-disjoint paths between it and the first
+// node, up to limit. The links give limit paths between every two nodes when it reaches (count-1)*limit,
+// as the paths between two nodes can be joined through the first one.
+func pathCount(count int, links []candidateLink, limit int) int {
+	total := 0
+	for target := 1; target < count; target++ {
+		capacity := make([][]int, count)
+		for index := range capacity {
+			capacity[index] = make([]int, count)
+		}
+		for _, link := range links {
+			capacity[link.a][link.b]++
+			capacity[link.b][link.a]++
+		}
+
+		// Every augmenting path found by a breadth-first search is one more disjoint path
+		paths := 0
+		for paths < limit {
+			previous := make([]int, count)
+			for index := range previous {
+				previous[index] = -1
+			}
+			previous[0] = 0
+			queue := []int{0}
+			for len(queue) > 0 && previous[target] == -1 {
+				node := queue[0]
+				queue = queue[1:]
+				for next := 0; next < count; next++ {
+					if capacity[node][next] > 0 && previous[next] == -1 {
+						previous[next] = node
+						queue = append(queue, next)
+					}
+				}
+			}
+			if previous[target] == -1 {
+				break
+			}
+			for node := target; node != 0; node = previous[node] {
+				capacity[previous[node]][node]--
+				capacity[node][previous[node]]++
+			}
+			paths++
+		}
+		total += paths
+	}
+	return total
+}
+
+// Cost returns the sum of the costs of the links, skipping the ones without a known cost.
+func Cost(links []*l2sces.Link, costs []*l2sces.LinkCost) float64 {
+	byPair := map[[2]string]float64{}
+	for _, cost := range costs {
+		byPair[pairOf(cost.GetEndpointA(), cost.GetEndpointB())] = cost.GetCost()
+	}
+	total := 0.0
+	for _, link := range links {
+		total += byPair[pairOf(link.GetEndpointA(), link.GetEndpointB())]
+	}
+	return total
+}
+
+// MergeCosts returns the costs of base, replaced by the ones of overrides between the same clusters.
+func MergeCosts(base []*l2sces.LinkCost, overrides []*l2sces.LinkCost) []*l2sces.LinkCost {
+	overridden := map[[2]string]bool{}
+	for _, cost := range overrides {
+		overridden[pairOf(cost.GetEndpointA(), cost.GetEndpointB())] = true
+	}
+	merged := []*l2sces.LinkCost{}
+	for _, cost := range base {
+		if !overridden[pairOf(cost.GetEndpointA(), cost.GetEndpointB())] {
+			merged = append(merged, cost)
+		}
+	}
+	return append(merged, overrides...)
+}
+
+// costFile is the format of the files read by LoadLinkCosts.
+type costFile struct {
+	Costs []struct {
+		EndpointA string  `yaml:"endpointA"`
+		EndpointB string  `yaml:"endpointB"`
+		Cost      float64 `yaml:"cost"`
+	} `yaml:"costs"`
+}
+
+// LoadLinkCosts reads the link costs of a YAML file, listed under costs with their endpointA, endpointB and
+// cost.
+func LoadLinkCosts(file string) ([]*l2sces.LinkCost, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading link costs: %v", err)
+	}
+	parsed := costFile{}
+	if err := yaml.UnmarshalStrict(data, &parsed); err != nil {
+		return nil, fmt.Errorf("error parsing link costs: %v", err)
+	}
+	costs := make([]*l2sces.LinkCost, len(parsed.Costs))
+	for index, cost := range parsed.Costs {
+		if cost.EndpointA == "" || cost.EndpointB == "" || cost.Cost < 0 {
+			return nil, fmt.Errorf("cost %d of %s needs two endpoints and can't be negative", index, file)
+		}
+		costs[index] = &l2sces.LinkCost{EndpointA: cost.EndpointA, EndpointB: cost.EndpointB, Cost: cost.Cost}
+	}
+	return costs, nil
+}
+
+func pairOf(a string, b string) [2]string {
+	if a > b {
+		return [2]string{b, a}
+	}
+	return [2]string{a, b}
+}
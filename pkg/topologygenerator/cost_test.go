@@ -0,0 +1,101 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topologygenerator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// testCosts returns the costs of a square a-b-c-d with a cheap a-c diagonal and an expensive b-d one.
+func testCosts() []*l2sces.LinkCost {
+	return []*l2sces.LinkCost{
+		{EndpointA: "a", EndpointB: "b", Cost: 1},
+		{EndpointA: "b", EndpointB: "c", Cost: 2},
+		{EndpointA: "c", EndpointB: "d", Cost: 3},
+		{EndpointA: "d", EndpointB: "a", Cost: 10},
+		{EndpointA: "c", EndpointB: "a", Cost: 4},
+		{EndpointA: "b", EndpointB: "d", Cost: 20},
+	}
+}
+
+func TestMinimumCost(t *testing.T) {
+	tests := []struct {
+		name       string
+		redundancy int
+		costs      []*l2sces.LinkCost
+		nodes      []Node
+		expected   []string
+	}{
+		{"spanning tree", 1, testCosts(), testNodes("d", "c", "b", "a"), []string{"a-b", "b-c", "c-d"}},
+		// a-c doesn't help d, which is only reached through c-d, so d-a is the cheapest useful link
+		{"redundant", 0, testCosts(), testNodes("a", "b", "c", "d"), []string{"a-b", "a-d", "b-c", "c-d"}},
+		{"every link", 3, testCosts(), testNodes("a", "b", "c", "d"), []string{"a-b", "a-c", "a-d", "b-c", "b-d", "c-d"}},
+		{"two clusters", 0, testCosts(), testNodes("a", "b"), []string{"a-b"}},
+		{"single cluster", 0, nil, testNodes("a"), []string{}},
+	}
+
+	for _, test := range tests {
+		links, err := Generate(MinimumCost{Costs: test.costs, Redundancy: test.redundancy}, test.nodes)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := linkNames(links); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+
+	if _, err := Generate(MinimumCost{Costs: testCosts()[:3]}, testNodes("a", "b", "c", "d")); err == nil {
+		t.Error("expected a line of costs not to give two paths between every two clusters")
+	}
+	if _, err := Generate(MinimumCost{Costs: testCosts()[:1]}, testNodes("a", "b", "c")); err == nil {
+		t.Error("expected clusters without costs to be rejected")
+	}
+}
+
+func TestCost(t *testing.T) {
+	links := []*l2sces.Link{{EndpointA: "b", EndpointB: "a"}, {EndpointA: "c", EndpointB: "d"}, {EndpointA: "a", EndpointB: "e"}}
+	if cost := Cost(links, testCosts()); cost != 4 {
+		t.Errorf("expected a cost of 4, got %v", cost)
+	}
+
+	merged := MergeCosts(testCosts(), []*l2sces.LinkCost{{EndpointA: "b", EndpointB: "a", Cost: 5}})
+	if cost := Cost(links, merged); cost != 8 || len(merged) != 6 {
+		t.Errorf("expected the override to replace the a-b cost, got %v", merged)
+	}
+}
+
+func TestLoadLinkCosts(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "costs.yaml")
+	if err := os.WriteFile(file, []byte("costs:\n- endpointA: a\n  endpointB: b\n  cost: 12.5\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	costs, err := LoadLinkCosts(file)
+	if err != nil || len(costs) != 1 || costs[0].GetCost() != 12.5 {
+		t.Errorf("expected the a-b cost, got %v (%v)", costs, err)
+	}
+
+	if err := os.WriteFile(file, []byte("costs:\n- endpointA: a\n  cost: 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLinkCosts(file); err == nil {
+		t.Error("expected a cost without endpointB to be rejected")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topologygenerator
+
+import (
+	"maps"
+	"sort"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// TopologyDiff is what changes in the clusters of a slice when its topology changes. Clusters are sorted by
+// name.
+type TopologyDiff struct {
+	// Added are the clusters that join the slice, and need a NetworkEdgeDevice.
+	Added []string
+	// Removed are the clusters that leave the slice, and need their NetworkEdgeDevice deleted.
+	Removed []string
+	// Updated are the clusters that stay in the slice with other neighbors.
+	Updated []string
+}
+
+// Diff compares the topology of the current clusters and links with the desired one. Clusters that keep
+// their neighbors are left out, so their tunnels are not touched.
+func Diff(currentClusters []string, currentLinks []*l2sces.Link, desiredClusters []string, desiredLinks []*l2sces.Link) TopologyDiff {
+	current := neighborSets(currentClusters, currentLinks)
+	desired := neighborSets(desiredClusters, desiredLinks)
+
+	diff := TopologyDiff{Added: []string{}, Removed: []string{}, Updated: []string{}}
+	for cluster, neighbors := range desired {
+		currentNeighbors, exists := current[cluster]
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, cluster)
+		case !maps.Equal(neighbors, currentNeighbors):
+			diff.Updated = append(diff.Updated, cluster)
+		}
+	}
+	for cluster := range current {
+		if _, exists := desired[cluster]; !exists {
+			diff.Removed = append(diff.Removed, cluster)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Updated)
+	return diff
+}
+
+// Empty tells whether no cluster changes.
+func (diff TopologyDiff) Empty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Updated) == 0
+}
+
+// neighborSets maps every cluster to the clusters it is linked to. Links to other clusters are ignored.
+func neighborSets(clusters []string, links []*l2sces.Link) map[string]map[string]bool {
+	neighbors := make(map[string]map[string]bool, len(clusters))
+	for _, cluster := range clusters {
+		neighbors[cluster] = map[string]bool{}
+	}
+	for _, link := range links {
+		a, b := link.GetEndpointA(), link.GetEndpointB()
+		if neighbors[a] == nil || neighbors[b] == nil || a == b {
+			continue
+		}
+		neighbors[a][b] = true
+		neighbors[b][a] = true
+	}
+	return neighbors
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topologygenerator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+func TestDiff(t *testing.T) {
+	ring, _ := Generate(Ring{}, testNodes("a", "b", "c", "d"))
+	grown, _ := Generate(Ring{}, testNodes("a", "b", "c", "d", "e"))
+	diff := Diff([]string{"a", "b", "c", "d"}, ring, []string{"a", "b", "c", "d", "e"}, grown)
+	expected := TopologyDiff{Added: []string{"e"}, Removed: []string{}, Updated: []string{"a", "d"}}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("expected %+v when the ring grows, got %+v", expected, diff)
+	}
+
+	// The same links in another order and direction change nothing
+	reversed := []*l2sces.Link{{EndpointA: "b", EndpointB: "a"}, {EndpointA: "a", EndpointB: "d"}, {EndpointA: "d", EndpointB: "c"}, {EndpointA: "c", EndpointB: "b"}}
+	if diff := Diff([]string{"a", "b", "c", "d"}, ring, []string{"d", "c", "b", "a"}, reversed); !diff.Empty() {
+		t.Errorf("expected no changes, got %+v", diff)
+	}
+
+	diff = Diff([]string{"a", "b", "c", "d"}, ring, []string{"a", "b", "c"}, ring)
+	expected = TopologyDiff{Added: []string{}, Removed: []string{"d"}, Updated: []string{"a", "c"}}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("expected %+v when d leaves, got %+v", expected, diff)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topologygenerator
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// Formats a slice topology can be rendered in.
+const (
+	DOTFormat     = "dot"
+	MermaidFormat = "mermaid"
+	JSONFormat    = "json"
+)
+
+// Formats are the formats a slice topology can be rendered in.
+var Formats = []string{DOTFormat, MermaidFormat, JSONFormat}
+
+// LinkStatus is the live status of a link. It is empty when unknown.
+type LinkStatus string
+
+const (
+	LinkUp   LinkStatus = "up"
+	LinkDown LinkStatus = "down"
+)
+
+// Graph is the topology of a slice, as rendered.
+type Graph struct {
+	Name     string         `json:"name"`
+	Clusters []GraphCluster `json:"clusters"`
+	Links    []GraphLink    `json:"links"`
+}
+
+// GraphCluster is a cluster of the slice with the IP its NetworkEdgeDevice is reached at.
+type GraphCluster struct {
+	Name    string `json:"name"`
+	Gateway string `json:"gateway,omitempty"`
+	Region  string `json:"region,omitempty"`
+}
+
+// GraphLink is a link between two clusters of the slice.
+type GraphLink struct {
+	EndpointA string     `json:"endpointA"`
+	EndpointB string     `json:"endpointB"`
+	Status    LinkStatus `json:"status,omitempty"`
+}
+
+// NewGraph returns the clusters of the slice and the links between them, as given or generated by its
+// topology.
+func NewGraph(name string, slice *l2sces.Slice) (*Graph, error) {
+	links, err := SliceLinks(slice)
+	if err != nil {
+		return nil, err
+	}
+	graph := &Graph{Name: name, Clusters: []GraphCluster{}, Links: []GraphLink{}}
+	for _, cluster := range slice.GetClusters() {
+		graph.Clusters = append(graph.Clusters, GraphCluster{Name: cluster.GetName(), Gateway: cluster.GetGatewayNode().GetIpAddress(), Region: cluster.GetRegion()})
+	}
+	for _, link := range links {
+		graph.Links = append(graph.Links, GraphLink{EndpointA: link.GetEndpointA(), EndpointB: link.GetEndpointB()})
+	}
+	return graph, nil
+}
+
+// SetLinkStatus overlays the live status of the links, given the neighbors each cluster is connected to.
+// A link is up if both of its clusters are connected to each other, down if either is not, and unknown if
+// the neighbors of either are.
+func (graph *Graph) SetLinkStatus(connected map[string][]string) {
+	for index, link := range graph.Links {
+		neighborsA, knownA := connected[link.EndpointA]
+		neighborsB, knownB := connected[link.EndpointB]
+		switch {
+		case !knownA || !knownB:
+			graph.Links[index].Status = ""
+		case slices.Contains(neighborsA, link.EndpointB) && slices.Contains(neighborsB, link.EndpointA):
+			graph.Links[index].Status = LinkUp
+		default:
+			graph.Links[index].Status = LinkDown
+		}
+	}
+}
+
+// Render renders the graph in the given format.
+func Render(graph *Graph, format string) ([]byte, error) {
+	switch format {
+	case DOTFormat:
+		return renderDOT(graph), nil
+	case MermaidFormat:
+		return renderMermaid(graph), nil
+	case JSONFormat:
+		return json.MarshalIndent(graph, "", "  ")
+	}
+	return nil, fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+// renderDOT renders the graph for Graphviz. Links that are up are green, and the ones that are down red
+// and dashed.
+func renderDOT(graph *Graph) []byte {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "graph %q {\n", graph.Name)
+	for _, cluster := range graph.Clusters {
+		fmt.Fprintf(&builder, "  %q [label=%q];\n", cluster.Name, clusterLabel(cluster, "\n"))
+	}
+	for _, link := range graph.Links {
+		attributes := ""
+		switch link.Status {
+		case LinkUp:
+			attributes = ` [color="green", label="up"]`
+		case LinkDown:
+			attributes = ` [color="red", style="dashed", label="down"]`
+		}
+		fmt.Fprintf(&builder, "  %q -- %q%s;\n", link.EndpointA, link.EndpointB, attributes)
+	}
+	builder.WriteString("}\n")
+	return []byte(builder.String())
+}
+
+// renderMermaid renders the graph as a Mermaid flowchart. Clusters get generated ids, as their names may
+// not be valid ones, and links that are down are dotted.
+func renderMermaid(graph *Graph) []byte {
+	ids := make(map[string]string, len(graph.Clusters))
+	var builder strings.Builder
+	builder.WriteString("graph LR\n")
+	for index, cluster := range graph.Clusters {
+		ids[cluster.Name] = fmt.Sprintf("c%d", index)
+		fmt.Fprintf(&builder, "  %s[\"%s\"]\n", ids[cluster.Name], strings.ReplaceAll(clusterLabel(cluster, "<br/>"), `"`, "#quot;"))
+	}
+	for _, link := range graph.Links {
+		switch link.Status {
+		case LinkUp:
+			fmt.Fprintf(&builder, "  %s ---|up| %s\n", ids[link.EndpointA], ids[link.EndpointB])
+		case LinkDown:
+			fmt.Fprintf(&builder, "  %s -.-|down| %s\n", ids[link.EndpointA], ids[link.EndpointB])
+		default:
+			fmt.Fprintf(&builder, "  %s --- %s\n", ids[link.EndpointA], ids[link.EndpointB])
+		}
+	}
+	return []byte(builder.String())
+}
+
+// clusterLabel returns the name of the cluster and, on the next line, its gateway.
+func clusterLabel(cluster GraphCluster, newline string) string {
+	if cluster.Gateway == "" {
+		return cluster.Name
+	}
+	return cluster.Name + newline + cluster.Gateway
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topologygenerator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+func testGraph(t *testing.T) *Graph {
+	graph, err := NewGraph("test-slice", &l2sces.Slice{
+		Clusters: []*l2sces.Cluster{
+			{Name: "cluster-1", GatewayNode: &l2sces.Node{IpAddress: "172.20.0.3"}},
+			{Name: "cluster-2"},
+			{Name: "cluster-3"},
+		},
+		Topology: &l2sces.Topology{Strategy: LineStrategy},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return graph
+}
+
+func TestSetLinkStatus(t *testing.T) {
+	graph := testGraph(t)
+	graph.SetLinkStatus(map[string][]string{"cluster-1": {"cluster-2"}, "cluster-2": {"cluster-1"}})
+	if graph.Links[0].Status != LinkUp || graph.Links[1].Status != "" {
+		t.Errorf("expected the first link up and the second unknown, got %+v", graph.Links)
+	}
+
+	graph.SetLinkStatus(map[string][]string{"cluster-1": {"cluster-2"}, "cluster-2": {}, "cluster-3": {"cluster-2"}})
+	if graph.Links[0].Status != LinkDown || graph.Links[1].Status != LinkDown {
+		t.Errorf("expected both links down, got %+v", graph.Links)
+	}
+}
+
+func TestRender(t *testing.T) {
+	graph := testGraph(t)
+	graph.SetLinkStatus(map[string][]string{"cluster-1": {"cluster-2"}, "cluster-2": {"cluster-1"}, "cluster-3": {}})
+
+	dot, err := Render(graph, DOTFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{`graph "test-slice" {`, `"cluster-1" [label="cluster-1\n172.20.0.3"];`,
+		`"cluster-1" -- "cluster-2" [color="green", label="up"];`, `"cluster-2" -- "cluster-3" [color="red", style="dashed", label="down"];`} {
+		if !strings.Contains(string(dot), expected) {
+			t.Errorf("expected %s in the DOT topology:\n%s", expected, dot)
+		}
+	}
+
+	mermaid, err := Render(graph, MermaidFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"graph LR", `c0["cluster-1<br/>172.20.0.3"]`, "c0 ---|up| c1", "c1 -.-|down| c2"} {
+		if !strings.Contains(string(mermaid), expected) {
+			t.Errorf("expected %s in the Mermaid topology:\n%s", expected, mermaid)
+		}
+	}
+
+	data, err := Render(graph, JSONFormat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered := &Graph{}
+	if err := json.Unmarshal(data, rendered); err != nil {
+		t.Fatalf("expected a JSON topology, got %v", err)
+	}
+	if rendered.Name != "test-slice" || len(rendered.Clusters) != 3 || len(rendered.Links) != 2 || rendered.Links[1].Status != LinkDown {
+		t.Errorf("unexpected JSON topology %+v", rendered)
+	}
+
+	if _, err := Render(graph, "svg"); err == nil {
+		t.Error("expected an unknown format to fail")
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topologygenerator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+// Names of the built-in topology strategies.
+const (
+	FullMeshStrategy     = "FullMesh"
+	StarStrategy         = "Star"
+	RingStrategy         = "Ring"
+	LineStrategy         = "Line"
+	KRegularStrategy     = "KRegular"
+	RegionalMeshStrategy = "RegionalMesh"
+	MinimumCostStrategy  = "MinimumCost"
+)
+
+// Strategies are the names of the built-in topology strategies.
+var Strategies = []string{FullMeshStrategy, StarStrategy, RingStrategy, LineStrategy, KRegularStrategy, RegionalMeshStrategy, MinimumCostStrategy}
+
+// Node is a cluster to link.
+type Node struct {
+	Name string
+	// Region groups the nodes meshed together by RegionalMesh.
+	Region string
+}
+
+// TopologyStrategy generates the links between a set of nodes. Generate gives them sorted by name and without
+// duplicates, so that the same nodes always get the same links.
+type TopologyStrategy interface {
+	Links(nodes []Node) ([]*l2sces.Link, error)
+}
+
+// NewStrategy returns the built-in strategy of the topology. A topology without strategy is a full mesh.
+func NewStrategy(topology *l2sces.Topology) (TopologyStrategy, error) {
+	switch topology.GetStrategy() {
+	case "", FullMeshStrategy:
+		return FullMesh{}, nil
+	case StarStrategy:
+		return Star{Hub: topology.GetHub()}, nil
+	case RingStrategy:
+		return Ring{}, nil
+	case LineStrategy:
+		return Line{}, nil
+	case KRegularStrategy:
+		return KRegular{Degree: int(topology.GetDegree())}, nil
+	case RegionalMeshStrategy:
+		return RegionalMesh{Hub: topology.GetHub()}, nil
+	case MinimumCostStrategy:
+		return MinimumCost{Costs: topology.GetCosts(), Redundancy: int(topology.GetRedundancy())}, nil
+	}
+	return nil, fmt.Errorf("unknown topology strategy %q", topology.GetStrategy())
+}
+
+// Generate returns the links the strategy generates between the nodes, taken in name order.
+func Generate(strategy TopologyStrategy, nodes []Node) ([]*l2sces.Link, error) {
+	sorted := []Node{}
+	seen := map[string]bool{}
+	for _, node := range nodes {
+		if !seen[node.Name] {
+			seen[node.Name] = true
+			sorted = append(sorted, node)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return strategy.Links(sorted)
+}
+
+// SliceLinks returns the links of a slice: the ones it lists or, if none, the ones its topology generates
+// between its clusters. Slices without topology are a full mesh in the order of their clusters.
+func SliceLinks(slice *l2sces.Slice) ([]*l2sces.Link, error) {
+	if len(slice.GetLinks()) > 0 {
+		return slice.GetLinks(), nil
+	}
+	topology := slice.GetTopology()
+	if topology == nil {
+		names := make([]string, len(slice.GetClusters()))
+		for index, cluster := range slice.GetClusters() {
+			names[index] = cluster.GetName()
+		}
+		return GenerateTopology(names), nil
+	}
+
+	strategy, err := NewStrategy(topology)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]Node, len(slice.GetClusters()))
+	for index, cluster := range slice.GetClusters() {
+		nodes[index] = Node{Name: cluster.GetName(), Region: cluster.GetRegion()}
+	}
+	return Generate(strategy, nodes)
+}
+
+// FullMesh links every node to every other one.
+type FullMesh struct{}
+
+func (FullMesh) Links(nodes []Node) ([]*l2sces.Link, error) {
+	return GenerateTopology(nodeNames(nodes)), nil
+}
+
+// Star links every node to a hub, the first node if none is designated.
+type Star struct {
+	Hub string
+}
+
+func (star Star) Links(nodes []Node) ([]*l2sces.Link, error) {
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	hub, err := hubOf(nodes, star.Hub)
+	if err != nil {
+		return nil, err
+	}
+	links := make([]*l2sces.Link, 0, len(nodes)-1)
+	for _, node := range nodes {
+		if node.Name != hub {
+			links = append(links, &l2sces.Link{EndpointA: hub, EndpointB: node.Name})
+		}
+	}
+	return links, nil
+}
+
+// Ring links every node to the next one, and the last one to the first.
+type Ring struct{}
+
+func (Ring) Links(nodes []Node) ([]*l2sces.Link, error) {
+	links, _ := Line{}.Links(nodes)
+	// With two nodes, closing the ring would repeat their link
+	if len(nodes) > 2 {
+		links = append(links, &l2sces.Link{EndpointA: nodes[len(nodes)-1].Name, EndpointB: nodes[0].Name})
+	}
+	return links, nil
+}
+
+// Line links every node to the next one.
+type Line struct{}
+
+func (Line) Links(nodes []Node) ([]*l2sces.Link, error) {
+	links := []*l2sces.Link{}
+	for index := 1; index < len(nodes); index++ {
+		links = append(links, &l2sces.Link{EndpointA: nodes[index-1].Name, EndpointB: nodes[index].Name})
+	}
+	return links, nil
+}
+
+// KRegular links every node to Degree others: the Degree/2 next ones around a ring and, for an odd degree,
+// the opposite one. It needs fewer nodes than its degree, and an even number of nodes for an odd degree.
+type KRegular struct {
+	Degree int
+}
+
+func (regular KRegular) Links(nodes []Node) ([]*l2sces.Link, error) {
+	count := len(nodes)
+	switch {
+	case regular.Degree < 1:
+		return nil, fmt.Errorf("the degree of a k-regular topology must be at least 1, got %d", regular.Degree)
+	case regular.Degree >= count:
+		return nil, fmt.Errorf("a k-regular topology of degree %d needs more than %d clusters", regular.Degree, count)
+	case regular.Degree%2 == 1 && count%2 == 1:
+		return nil, fmt.Errorf("a k-regular topology of odd degree %d needs an even number of clusters, got %d", regular.Degree, count)
+	}
+
+	links := []*l2sces.Link{}
+	for index := range nodes {
+		for distance := 1; distance <= regular.Degree/2; distance++ {
+			links = append(links, &l2sces.Link{EndpointA: nodes[index].Name, EndpointB: nodes[(index+distance)%count].Name})
+		}
+	}
+	if regular.Degree%2 == 1 {
+		for index := 0; index < count/2; index++ {
+			links = append(links, &l2sces.Link{EndpointA: nodes[index].Name, EndpointB: nodes[index+count/2].Name})
+		}
+	}
+	return links, nil
+}
+
+// RegionalMesh links the nodes of each region in a full mesh, and the hubs of the regions in another. The hub
+// of a region is the designated one if it belongs to the region, or its first node otherwise.
+type RegionalMesh struct {
+	Hub string
+}
+
+func (regional RegionalMesh) Links(nodes []Node) ([]*l2sces.Link, error) {
+	regions := []string{}
+	regionNodes := map[string][]Node{}
+	for _, node := range nodes {
+		if _, exists := regionNodes[node.Region]; !exists {
+			regions = append(regions, node.Region)
+		}
+		regionNodes[node.Region] = append(regionNodes[node.Region], node)
+	}
+	sort.Strings(regions)
+
+	links := []*l2sces.Link{}
+	hubs := []string{}
+	for _, region := range regions {
+		links = append(links, GenerateTopology(nodeNames(regionNodes[region]))...)
+		hub, err := hubOf(regionNodes[region], regional.Hub)
+		if err != nil {
+			hub = regionNodes[region][0].Name
+		}
+		hubs = append(hubs, hub)
+	}
+	return append(links, GenerateTopology(hubs)...), nil
+}
+
+// hubOf returns the designated hub, which must be one of the nodes, or the first node if none is designated.
+func hubOf(nodes []Node, hub string) (string, error) {
+	if hub == "" {
+		return nodes[0].Name, nil
+	}
+	for _, node := range nodes {
+		if node.Name == hub {
+			return hub, nil
+		}
+	}
+	return "", fmt.Errorf("hub %s is not one of the clusters", hub)
+}
+
+func nodeNames(nodes []Node) []string {
+	names := make([]string, len(nodes))
+	for index, node := range nodes {
+		names[index] = node.Name
+	}
+	return names
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topologygenerator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+func testNodes(names ...string) []Node {
+	nodes := make([]Node, len(names))
+	for index, name := range names {
+		region, _, _ := strings.Cut(name, "-")
+		nodes[index] = Node{Name: name, Region: region}
+	}
+	return nodes
+}
+
+// linkNames returns the links as a-b, in order.
+func linkNames(links []*l2sces.Link) []string {
+	names := make([]string, len(links))
+	for index, link := range links {
+		names[index] = link.GetEndpointA() + "-" + link.GetEndpointB()
+	}
+	return names
+}
+
+func TestStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy TopologyStrategy
+		nodes    []Node
+		expected []string
+	}{
+		{"full mesh", FullMesh{}, testNodes("c", "a", "b"), []string{"a-b", "a-c", "b-c"}},
+		{"star", Star{}, testNodes("c", "a", "b"), []string{"a-b", "a-c"}},
+		{"star with hub", Star{Hub: "b"}, testNodes("c", "a", "b"), []string{"b-a", "b-c"}},
+		{"ring", Ring{}, testNodes("d", "c", "a", "b"), []string{"a-b", "b-c", "c-d", "d-a"}},
+		{"ring of two", Ring{}, testNodes("b", "a"), []string{"a-b"}},
+		{"line", Line{}, testNodes("c", "a", "b"), []string{"a-b", "b-c"}},
+		{"even degree", KRegular{Degree: 2}, testNodes("a", "b", "c", "d", "e"), []string{"a-b", "b-c", "c-d", "d-e", "e-a"}},
+		{"odd degree", KRegular{Degree: 3}, testNodes("a", "b", "c", "d"), []string{"a-b", "b-c", "c-d", "d-a", "a-c", "b-d"}},
+		{"regional mesh", RegionalMesh{}, testNodes("eu-b", "us-a", "eu-a", "us-b", "eu-c"),
+			[]string{"eu-a-eu-b", "eu-a-eu-c", "eu-b-eu-c", "us-a-us-b", "eu-a-us-a"}},
+		{"regional mesh with hub", RegionalMesh{Hub: "us-b"}, testNodes("eu-b", "us-a", "eu-a", "us-b"),
+			[]string{"eu-a-eu-b", "us-a-us-b", "eu-a-us-b"}},
+		{"duplicates", Line{}, testNodes("b", "a", "b"), []string{"a-b"}},
+	}
+
+	for _, test := range tests {
+		links, err := Generate(test.strategy, test.nodes)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := linkNames(links); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestStrategyErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy TopologyStrategy
+		nodes    []Node
+	}{
+		{"unknown hub", Star{Hub: "z"}, testNodes("a", "b")},
+		{"no degree", KRegular{}, testNodes("a", "b")},
+		{"degree too high", KRegular{Degree: 2}, testNodes("a", "b")},
+		{"odd degree and clusters", KRegular{Degree: 3}, testNodes("a", "b", "c", "d", "e")},
+	}
+
+	for _, test := range tests {
+		if links, err := Generate(test.strategy, test.nodes); err == nil {
+			t.Errorf("%s: expected an error, got %v", test.name, linkNames(links))
+		}
+	}
+	if _, err := NewStrategy(&l2sces.Topology{Strategy: "Tree"}); err == nil {
+		t.Error("expected an unknown strategy to be rejected")
+	}
+}
+
+// TestSliceLinks checks that slices without topology keep the full mesh in the order of their clusters.
+func TestSliceLinks(t *testing.T) {
+	clusters := []*l2sces.Cluster{{Name: "c"}, {Name: "a"}, {Name: "b"}}
+	links, err := SliceLinks(&l2sces.Slice{Clusters: clusters})
+	if err != nil || !reflect.DeepEqual(linkNames(links), []string{"c-a", "c-b", "a-b"}) {
+		t.Errorf("expected the full mesh in cluster order, got %v (%v)", linkNames(links), err)
+	}
+
+	links, err = SliceLinks(&l2sces.Slice{Clusters: clusters, Topology: &l2sces.Topology{Strategy: RingStrategy}})
+	if err != nil || !reflect.DeepEqual(linkNames(links), []string{"a-b", "b-c", "c-a"}) {
+		t.Errorf("expected a ring in name order, got %v (%v)", linkNames(links), err)
+	}
+}
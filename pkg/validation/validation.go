@@ -0,0 +1,391 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validation checks the messages of the gRPC API before they reach the member clusters. Every
+// function returns all the violations found, each with the path of the offending field.
+package validation
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+
+	l2smv1 "github.com/Networks-it-uc3m/L2S-M/api/v1"
+	apivalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologyanalysis"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/topologygenerator"
+)
+
+// NetworkTypes are the L2Network types accepted by L2S-M.
+var NetworkTypes = []string{string(l2smv1.NetworkTypeVnet), string(l2smv1.NetworkTypeExtVnet), string(l2smv1.NetworkTypeVlink)}
+
+// ValidateL2Network checks a network to create. Gateways are not needed, as networks run over existing slices.
+func ValidateL2Network(network *l2sces.L2Network, path *field.Path) field.ErrorList {
+	if network == nil {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	allErrs := validateName(network.GetName(), path.Child("name"), true)
+
+	if network.GetType() != "" && !contains(NetworkTypes, network.GetType()) {
+		allErrs = append(allErrs, field.NotSupported(path.Child("type"), network.GetType(), NetworkTypes))
+	}
+	if network.GetPodCidr() != "" {
+		allErrs = append(allErrs, validateCIDR(network.GetPodCidr(), path.Child("pod_cidr"))...)
+	}
+	allErrs = append(allErrs, ValidateProvider(network.GetProvider(), path.Child("provider"))...)
+	allErrs = append(allErrs, validateClusters(network.GetClusters(), path.Child("clusters"), false)...)
+	return allErrs
+}
+
+// ValidateL2NetworkName checks a network given only by name, as in delete requests resolved through the registry.
+func ValidateL2NetworkName(network *l2sces.L2Network, path *field.Path) field.ErrorList {
+	if len(network.GetClusters()) > 0 {
+		return ValidateL2Network(network, path)
+	}
+	return validateName(network.GetName(), path.Child("name"), true)
+}
+
+// ValidateSlice checks a slice to create. Its links must join clusters of the slice, or its topology generate
+// links between them, and connect them all. Every cluster needs a gateway node when the slice spans more
+// than one.
+func ValidateSlice(slice *l2sces.Slice, path *field.Path) field.ErrorList {
+	if slice == nil {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	allErrs := validateName(slice.GetName(), path.Child("name"), false)
+	allErrs = append(allErrs, ValidateProvider(slice.GetProvider(), path.Child("provider"))...)
+	allErrs = append(allErrs, validateClusters(slice.GetClusters(), path.Child("clusters"), len(slice.GetClusters()) > 1)...)
+	linkErrs := ValidateLinks(slice.GetLinks(), clusterNames(slice.GetClusters()), path.Child("links"))
+	linkErrs = append(linkErrs, ValidateTopology(slice, path.Child("topology"))...)
+	if len(linkErrs) == 0 {
+		// The topology is valid, so it generates the links
+		links, _ := topologygenerator.SliceLinks(slice)
+		linksPath := path.Child("links")
+		if len(slice.GetLinks()) == 0 {
+			linksPath = path.Child("topology")
+		}
+		linkErrs = ValidateConnected(clusterNames(slice.GetClusters()), links, linksPath)
+	}
+	return append(allErrs, linkErrs...)
+}
+
+// ValidateConnected checks that the links connect every cluster to every other one.
+func ValidateConnected(clusters []string, links []*l2sces.Link, path *field.Path) field.ErrorList {
+	if err := topologyanalysis.Analyze(clusters, links).Err(); err != nil {
+		return field.ErrorList{field.Invalid(path, len(links), err.Error())}
+	}
+	return nil
+}
+
+// ValidateTopology checks the topology of a slice, if given. It replaces the links, so they can't be given too,
+// and it must be able to link the clusters of the slice.
+func ValidateTopology(slice *l2sces.Slice, path *field.Path) field.ErrorList {
+	topology := slice.GetTopology()
+	if topology == nil {
+		return nil
+	}
+	if len(slice.GetLinks()) > 0 {
+		return field.ErrorList{field.Forbidden(path, "links and a topology can't both be given")}
+	}
+	if topology.GetStrategy() != "" && !contains(topologygenerator.Strategies, topology.GetStrategy()) {
+		return field.ErrorList{field.NotSupported(path.Child("strategy"), topology.GetStrategy(), topologygenerator.Strategies)}
+	}
+	allErrs := field.ErrorList{}
+	names := clusterNames(slice.GetClusters())
+	if topology.GetHub() != "" && !contains(names, topology.GetHub()) {
+		allErrs = append(allErrs, field.NotFound(path.Child("hub"), topology.GetHub()))
+	}
+	for index, cost := range topology.GetCosts() {
+		for _, endpoint := range []struct{ name, field string }{{cost.GetEndpointA(), "endpointA"}, {cost.GetEndpointB(), "endpointB"}} {
+			if !contains(names, endpoint.name) {
+				allErrs = append(allErrs, field.NotFound(path.Child("costs").Index(index).Child(endpoint.field), endpoint.name))
+			}
+		}
+		if cost.GetCost() < 0 {
+			allErrs = append(allErrs, field.Invalid(path.Child("costs").Index(index).Child("cost"), cost.GetCost(), "must not be negative"))
+		}
+	}
+	if topology.GetRedundancy() < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("redundancy"), topology.GetRedundancy(), "must not be negative"))
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	if _, err := topologygenerator.SliceLinks(slice); err != nil {
+		switch topology.GetStrategy() {
+		case topologygenerator.KRegularStrategy:
+			return field.ErrorList{field.Invalid(path.Child("degree"), topology.GetDegree(), err.Error())}
+		case topologygenerator.MinimumCostStrategy:
+			return field.ErrorList{field.Invalid(path.Child("costs"), len(topology.GetCosts()), err.Error())}
+		}
+		return field.ErrorList{field.Invalid(path, topology.GetStrategy(), err.Error())}
+	}
+	return nil
+}
+
+// ValidateSliceReference checks the slice of a request that updates or deletes it. A slice without clusters
+// is looked up in the registry, so it only needs a name, given by name or taken from the slice itself.
+func ValidateSliceReference(slice *l2sces.Slice, name string, path *field.Path, namePath *field.Path) field.ErrorList {
+	if len(slice.GetClusters()) > 0 {
+		allErrs := validateClusters(slice.GetClusters(), path.Child("clusters"), len(slice.GetClusters()) > 1)
+		allErrs = append(allErrs, ValidateLinks(slice.GetLinks(), clusterNames(slice.GetClusters()), path.Child("links"))...)
+		return append(allErrs, ValidateTopology(slice, path.Child("topology"))...)
+	}
+	if name != "" {
+		return validateName(name, namePath, true)
+	}
+	if slice.GetName() == "" && slice.GetProvider().GetName() == "" {
+		return field.ErrorList{field.Required(namePath, "a slice without clusters is looked up by name")}
+	}
+	return nil
+}
+
+// ValidateClusterJoin checks a cluster joining a slice. Each of its links must join it to a cluster of the slice.
+func ValidateClusterJoin(slice *l2sces.Slice, cluster *l2sces.Cluster, links []*l2sces.Link, clusterPath *field.Path, linksPath *field.Path) field.ErrorList {
+	allErrs := ValidateCluster(cluster, clusterPath, len(slice.GetClusters()) > 0)
+	if cluster == nil {
+		return allErrs
+	}
+	allErrs = append(allErrs, ValidateLinks(links, append(clusterNames(slice.GetClusters()), cluster.GetName()), linksPath)...)
+	for index, link := range links {
+		if link.GetEndpointA() != cluster.GetName() && link.GetEndpointB() != cluster.GetName() {
+			allErrs = append(allErrs, field.Invalid(linksPath.Index(index), link.GetEndpointA()+"-"+link.GetEndpointB(), "must join the new cluster"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateNamespace checks the namespace of a request, if given.
+func ValidateNamespace(namespace string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if namespace != "" {
+		for _, msg := range apivalidation.IsDNS1123Label(namespace) {
+			allErrs = append(allErrs, field.Invalid(path, namespace, msg))
+		}
+	}
+	return allErrs
+}
+
+// ValidateOverlay checks an overlay to create between the given clusters. Its links join cluster names.
+func ValidateOverlay(overlay *l2sces.Overlay, clusters []*l2sces.Cluster, path *field.Path, clustersPath *field.Path) field.ErrorList {
+	if overlay == nil {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	allErrs := ValidateProvider(overlay.GetProvider(), path.Child("provider"))
+	allErrs = append(allErrs, validateClusters(clusters, clustersPath, len(clusters) > 1)...)
+	linkErrs := ValidateLinks(overlay.GetLinks(), clusterNames(clusters), path.Child("links"))
+	if len(linkErrs) == 0 && len(overlay.GetLinks()) > 0 {
+		linkErrs = ValidateConnected(clusterNames(clusters), overlay.GetLinks(), path.Child("links"))
+	}
+	return append(allErrs, linkErrs...)
+}
+
+// ValidateCluster checks a cluster. The gateway node is only required for clusters joined by a slice.
+func ValidateCluster(cluster *l2sces.Cluster, path *field.Path, requireGateway bool) field.ErrorList {
+	if cluster == nil {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	allErrs := validateName(cluster.GetName(), path.Child("name"), true)
+
+	// Registered clusters need no credentials, so they are only checked if given
+	restConfigPath := path.Child("rest_config")
+	if server := cluster.GetRestConfig().GetServer(); server != "" {
+		allErrs = append(allErrs, validateAPIServer(server, restConfigPath.Child("server"))...)
+	}
+	if apiKey := cluster.GetRestConfig().GetApiKey(); apiKey != "" {
+		allErrs = append(allErrs, validateAPIServer(apiKey, restConfigPath.Child("api_key"))...)
+	}
+
+	allErrs = append(allErrs, ValidateNamespace(cluster.GetNamespace(), path.Child("namespace"))...)
+	if cluster.GetPodAddressPool() != "" {
+		allErrs = append(allErrs, validateCIDR(cluster.GetPodAddressPool(), path.Child("pod_address_pool"))...)
+	}
+
+	gatewayPath := path.Child("gateway_node")
+	if cluster.GetGatewayNode() == nil {
+		if requireGateway {
+			allErrs = append(allErrs, field.Required(gatewayPath, "the gateway node connects the cluster to the rest of the slice"))
+		}
+	} else {
+		allErrs = append(allErrs, validateNode(cluster.GetGatewayNode(), gatewayPath)...)
+	}
+
+	if cluster.GetOverlay() != nil {
+		for index, node := range cluster.GetOverlay().GetNodes() {
+			if node == "" {
+				allErrs = append(allErrs, field.Required(path.Child("overlay", "nodes").Index(index), ""))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateClusterRegistration checks a request registering a member cluster.
+func ValidateClusterRegistration(req *l2sces.RegisterClusterRequest) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// The cluster name is the value of a label, and names its Secret
+	namePath := field.NewPath("cluster_name")
+	if req.GetClusterName() == "" {
+		allErrs = append(allErrs, field.Required(namePath, ""))
+	} else {
+		for _, msg := range apivalidation.IsDNS1123Label(req.GetClusterName()) {
+			allErrs = append(allErrs, field.Invalid(namePath, req.GetClusterName(), msg))
+		}
+	}
+	if len(req.GetKubeconfig()) == 0 {
+		allErrs = append(allErrs, field.Required(field.NewPath("kubeconfig"), ""))
+	}
+	allErrs = append(allErrs, ValidateNamespace(req.GetServiceAccountNamespace(), field.NewPath("service_account_namespace"))...)
+	allErrs = append(allErrs, validateName(req.GetServiceAccountName(), field.NewPath("service_account_name"), false)...)
+	// Kubernetes mints no token valid for less than 10 minutes
+	if seconds := req.GetTokenExpirationSeconds(); seconds != 0 && seconds < 600 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("token_expiration_seconds"), seconds, "must be at least 600"))
+	}
+	return allErrs
+}
+
+// ValidateProvider checks the provider of a network or slice: the SDN controller its clusters connect to.
+func ValidateProvider(provider *l2sces.Provider, path *field.Path) field.ErrorList {
+	if provider == nil {
+		return field.ErrorList{field.Required(path, "")}
+	}
+	allErrs := validateName(provider.GetName(), path.Child("name"), true)
+	if provider.GetDomain() == "" {
+		allErrs = append(allErrs, field.Required(path.Child("domain"), ""))
+	}
+
+	ports := map[string]string{
+		"dns_port":      provider.GetDnsPort(),
+		"sdn_port":      provider.GetSdnPort(),
+		"of_port":       provider.GetOfPort(),
+		"dns_grpc_port": provider.GetDnsGrpcPort(),
+	}
+	for _, name := range []string{"dns_port", "sdn_port", "of_port", "dns_grpc_port"} {
+		if port := ports[name]; port != "" {
+			if number, err := strconv.Atoi(port); err != nil || apivalidation.IsValidPortNum(number) != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child(name), port, "must be a port number between 1 and 65535"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateLinks checks that links join two different clusters among the given ones, once each.
+func ValidateLinks(links []*l2sces.Link, clusters []string, path *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	seen := map[[2]string]bool{}
+	for index, link := range links {
+		linkPath := path.Index(index)
+		for _, endpoint := range []struct{ name, value string }{{"endpointA", link.GetEndpointA()}, {"endpointB", link.GetEndpointB()}} {
+			switch {
+			case endpoint.value == "":
+				allErrs = append(allErrs, field.Required(linkPath.Child(endpoint.name), ""))
+			case !contains(clusters, endpoint.value):
+				allErrs = append(allErrs, field.NotFound(linkPath.Child(endpoint.name), endpoint.value))
+			}
+		}
+		if link.GetEndpointA() != "" && link.GetEndpointA() == link.GetEndpointB() {
+			allErrs = append(allErrs, field.Invalid(linkPath, link.GetEndpointA(), "a link must join two different clusters"))
+		}
+
+		key := [2]string{link.GetEndpointA(), link.GetEndpointB()}
+		if key[0] > key[1] {
+			key[0], key[1] = key[1], key[0]
+		}
+		if seen[key] {
+			allErrs = append(allErrs, field.Duplicate(linkPath, key[0]+"-"+key[1]))
+		}
+		seen[key] = true
+	}
+	return allErrs
+}
+
+func validateClusters(clusters []*l2sces.Cluster, path *field.Path, requireGateway bool) field.ErrorList {
+	if len(clusters) == 0 {
+		return field.ErrorList{field.Required(path, "at least one cluster is needed")}
+	}
+	allErrs := field.ErrorList{}
+	seen := map[string]bool{}
+	for index, cluster := range clusters {
+		allErrs = append(allErrs, ValidateCluster(cluster, path.Index(index), requireGateway)...)
+		if name := cluster.GetName(); name != "" {
+			if seen[name] {
+				allErrs = append(allErrs, field.Duplicate(path.Index(index).Child("name"), name))
+			}
+			seen[name] = true
+		}
+	}
+	return allErrs
+}
+
+// validateName checks that a name can be used as the name of a Kubernetes object.
+func validateName(name string, path *field.Path, required bool) field.ErrorList {
+	if name == "" {
+		if required {
+			return field.ErrorList{field.Required(path, "")}
+		}
+		return nil
+	}
+	allErrs := field.ErrorList{}
+	for _, msg := range apivalidation.IsDNS1123Subdomain(name) {
+		allErrs = append(allErrs, field.Invalid(path, name, msg))
+	}
+	return allErrs
+}
+
+func validateCIDR(cidr string, path *field.Path) field.ErrorList {
+	if _, _, err := net.ParseCIDR(cidr); err != nil {
+		return field.ErrorList{field.Invalid(path, cidr, "must be a valid CIDR, e.g. 10.1.0.0/16")}
+	}
+	return nil
+}
+
+func validateAPIServer(apiServer string, path *field.Path) field.ErrorList {
+	parsed, err := url.Parse(apiServer)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return field.ErrorList{field.Invalid(path, apiServer, "must be the https URL of the API server, e.g. https://10.0.0.1:6443")}
+	}
+	return nil
+}
+
+func validateNode(node *l2sces.Node, path *field.Path) field.ErrorList {
+	allErrs := validateName(node.GetName(), path.Child("name"), true)
+	if node.GetIpAddress() == "" {
+		allErrs = append(allErrs, field.Required(path.Child("ip_address"), ""))
+	} else if net.ParseIP(node.GetIpAddress()) == nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("ip_address"), node.GetIpAddress(), "must be a valid IP address"))
+	}
+	return allErrs
+}
+
+func clusterNames(clusters []*l2sces.Cluster) []string {
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		names = append(names, cluster.GetName())
+	}
+	return names
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+// Copyright 2024 Universidad Carlos III de Madrid
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+)
+
+func testCluster(name string, ip string) *l2sces.Cluster {
+	return &l2sces.Cluster{
+		Name:        name,
+		RestConfig:  &l2sces.RestConfig{ApiKey: "https://" + ip + ":6443", BearerToken: "token"},
+		GatewayNode: &l2sces.Node{Name: name + "-control-plane", IpAddress: ip},
+	}
+}
+
+func testProvider() *l2sces.Provider {
+	return &l2sces.Provider{Name: "test-slice", Domain: "10.0.0.1", DnsPort: "30053", SdnPort: "30808", OfPort: "30663", DnsGrpcPort: "30818"}
+}
+
+// fields returns the sorted paths and types of the violations, as path:type.
+func fields(errs field.ErrorList) string {
+	paths := make([]string, len(errs))
+	for index, err := range errs {
+		paths[index] = err.Field + ":" + string(err.Type)
+	}
+	sort.Strings(paths)
+	return strings.Join(paths, ",")
+}
+
+func TestValidateL2Network(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  *l2sces.L2Network
+		expected string
+	}{
+		{
+			name:    "valid",
+			network: &l2sces.L2Network{Name: "l2network-sample", Type: "vnet", PodCidr: "10.1.0.0/16", Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3")}},
+		},
+		{
+			name:     "missing",
+			network:  nil,
+			expected: "network:FieldValueRequired",
+		},
+		{
+			name:     "no provider",
+			network:  &l2sces.L2Network{Name: "l2network-sample", Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3")}},
+			expected: "network.provider:FieldValueRequired",
+		},
+		{
+			name:    "registered cluster",
+			network: &l2sces.L2Network{Name: "l2network-sample", Provider: testProvider(), Clusters: []*l2sces.Cluster{{Name: "cluster-1"}}},
+		},
+		{
+			name: "every violation",
+			network: &l2sces.L2Network{Name: "L2Network", Type: "bridge", PodCidr: "10.1.0.0", Provider: &l2sces.Provider{Name: "test-slice", Domain: "10.0.0.1", SdnPort: "http"},
+				Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-1", "172.20.0.4")}},
+			expected: "network.clusters[1].name:FieldValueDuplicate,network.name:FieldValueInvalid,network.pod_cidr:FieldValueInvalid," +
+				"network.provider.sdn_port:FieldValueInvalid,network.type:FieldValueNotSupported",
+		},
+	}
+
+	for _, test := range tests {
+		if got := fields(ValidateL2Network(test.network, field.NewPath("network"))); got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestValidateSlice(t *testing.T) {
+	clusterWithoutGateway := testCluster("cluster-3", "172.20.0.5")
+	clusterWithoutGateway.GatewayNode = nil
+	clusterWithBadGateway := testCluster("cluster-4", "172.20.0.6")
+	clusterWithBadGateway.GatewayNode.IpAddress = "172.20.0"
+	clusterWithBadAPIServer := testCluster("cluster-5", "172.20.0.7")
+	clusterWithBadAPIServer.RestConfig.ApiKey = "172.20.0.7:6443"
+
+	tests := []struct {
+		name     string
+		slice    *l2sces.Slice
+		expected string
+	}{
+		{
+			name: "valid",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4")},
+				Links: []*l2sces.Link{{EndpointA: "cluster-1", EndpointB: "cluster-2"}}},
+		},
+		{
+			name:     "no clusters",
+			slice:    &l2sces.Slice{Provider: testProvider()},
+			expected: "slice.clusters:FieldValueRequired",
+		},
+		{
+			name: "bad clusters",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{
+				testCluster("cluster-1", "172.20.0.3"), clusterWithoutGateway, clusterWithBadGateway, clusterWithBadAPIServer,
+			}},
+			expected: "slice.clusters[1].gateway_node:FieldValueRequired,slice.clusters[2].gateway_node.ip_address:FieldValueInvalid," +
+				"slice.clusters[3].rest_config.api_key:FieldValueInvalid",
+		},
+		{
+			name: "bad links",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4")},
+				Links: []*l2sces.Link{
+					{EndpointA: "cluster-1", EndpointB: "cluster-2"},
+					{EndpointA: "cluster-2", EndpointB: "cluster-1"},
+					{EndpointA: "cluster-1", EndpointB: "cluster-9"},
+					{EndpointA: "cluster-1", EndpointB: "cluster-1"},
+					{EndpointA: "cluster-1"},
+				}},
+			expected: "slice.links[1]:FieldValueDuplicate,slice.links[2].endpointB:FieldValueNotFound,slice.links[3]:FieldValueInvalid," +
+				"slice.links[4].endpointB:FieldValueRequired",
+		},
+		{
+			name: "disconnected links",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4"),
+				testCluster("cluster-3", "172.20.0.5"), testCluster("cluster-4", "172.20.0.6")},
+				Links: []*l2sces.Link{{EndpointA: "cluster-1", EndpointB: "cluster-2"}, {EndpointA: "cluster-3", EndpointB: "cluster-4"}}},
+			expected: "slice.links:FieldValueInvalid",
+		},
+		{
+			name: "disconnected topology",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4"),
+				testCluster("cluster-3", "172.20.0.5"), testCluster("cluster-4", "172.20.0.6")},
+				Topology: &l2sces.Topology{Strategy: "KRegular", Degree: 1}},
+			expected: "slice.topology:FieldValueInvalid",
+		},
+		{
+			name: "topology",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4")},
+				Topology: &l2sces.Topology{Strategy: "Star", Hub: "cluster-2"}},
+		},
+		{
+			name: "links and topology",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4")},
+				Links: []*l2sces.Link{{EndpointA: "cluster-1", EndpointB: "cluster-2"}}, Topology: &l2sces.Topology{Strategy: "Ring"}},
+			expected: "slice.topology:FieldValueForbidden",
+		},
+		{
+			name: "unknown strategy",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3")},
+				Topology: &l2sces.Topology{Strategy: "Tree"}},
+			expected: "slice.topology.strategy:FieldValueNotSupported",
+		},
+		{
+			name: "unknown hub",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3")},
+				Topology: &l2sces.Topology{Strategy: "Star", Hub: "cluster-9"}},
+			expected: "slice.topology.hub:FieldValueNotFound",
+		},
+		{
+			name: "degree too high",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4")},
+				Topology: &l2sces.Topology{Strategy: "KRegular", Degree: 2}},
+			expected: "slice.topology.degree:FieldValueInvalid",
+		},
+		{
+			name: "bad costs",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4")},
+				Topology: &l2sces.Topology{Strategy: "MinimumCost", Redundancy: -1, Costs: []*l2sces.LinkCost{
+					{EndpointA: "cluster-1", EndpointB: "cluster-9", Cost: -1},
+				}}},
+			expected: "slice.topology.costs[0].cost:FieldValueInvalid,slice.topology.costs[0].endpointB:FieldValueNotFound,slice.topology.redundancy:FieldValueInvalid",
+		},
+		{
+			name: "costs not connecting",
+			slice: &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4")},
+				Topology: &l2sces.Topology{Strategy: "MinimumCost"}},
+			expected: "slice.topology.costs:FieldValueInvalid",
+		},
+	}
+
+	for _, test := range tests {
+		if got := fields(ValidateSlice(test.slice, field.NewPath("slice"))); got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestValidateClusterJoin(t *testing.T) {
+	slice := &l2sces.Slice{Provider: testProvider(), Clusters: []*l2sces.Cluster{testCluster("cluster-1", "172.20.0.3"), testCluster("cluster-2", "172.20.0.4")}}
+	links := []*l2sces.Link{
+		{EndpointA: "cluster-3", EndpointB: "cluster-1"},
+		{EndpointA: "cluster-1", EndpointB: "cluster-2"},
+	}
+
+	errs := ValidateClusterJoin(slice, testCluster("cluster-3", "172.20.0.5"), links, field.NewPath("cluster"), field.NewPath("links"))
+	if got, expected := fields(errs), "links[1]:FieldValueInvalid"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestValidateClusterRegistration(t *testing.T) {
+	errs := ValidateClusterRegistration(&l2sces.RegisterClusterRequest{ClusterName: "Cluster.A", ServiceAccountName: "l2sces", TokenExpirationSeconds: 60})
+	if got, expected := fields(errs), "cluster_name:FieldValueInvalid,kubeconfig:FieldValueRequired,token_expiration_seconds:FieldValueInvalid"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if errs := ValidateClusterRegistration(&l2sces.RegisterClusterRequest{ClusterName: "cluster-a", Kubeconfig: []byte("kubeconfig")}); len(errs) > 0 {
+		t.Errorf("expected the registration to be valid, got %v", errs)
+	}
+}
+
+func TestValidateSliceReference(t *testing.T) {
+	if errs := ValidateSliceReference(nil, "", field.NewPath("slice"), field.NewPath("overlay_name")); fields(errs) != "overlay_name:FieldValueRequired" {
+		t.Errorf("expected the name to be required, got %q", fields(errs))
+	}
+	if errs := ValidateSliceReference(nil, "test-slice", field.NewPath("slice"), field.NewPath("overlay_name")); len(errs) > 0 {
+		t.Errorf("expected a named slice to be valid, got %v", errs)
+	}
+}
@@ -32,10 +32,12 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	// Adjust import to point to where you keep your proto-generated code
 	"github.com/Networks-it-uc3m/l2sc-es/api/v1/l2sces"
+	"github.com/Networks-it-uc3m/l2sc-es/pkg/grpctls"
 )
 
 // main is the entry point for this client application
@@ -45,6 +47,7 @@ func main() {
 	testNetworkDelete := flag.Bool("test-network-delete", false, "Simulate deleting a network resource")
 	testSliceCreate := flag.Bool("test-slice-create", false, "Simulate creating a slice resource")
 	testSliceDelete := flag.Bool("test-slice-delete", false, "Simulate deleting a slice resource")
+	testList := flag.Bool("test-list", false, "List the networks and slices registered in the server")
 
 	configPath := flag.String("config", "./test/config.yaml", "Path to YAML config file")
 	namespace := flag.String("namespace", "l2sm-system", "Kubernetes namespace to place resources in")
@@ -58,8 +61,20 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Create a gRPC connection
-	conn, err := grpc.NewClient(cfg.ServerAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Create a gRPC connection, over TLS unless the config says otherwise
+	creds := insecure.NewCredentials()
+	if !cfg.TLS.Insecure {
+		tlsConfig, err := grpctls.ClientConfig(cfg.TLS.CAFile, cfg.TLS.CertFile, cfg.TLS.KeyFile, cfg.TLS.ServerName)
+		if err != nil {
+			log.Fatalf("Failed to configure TLS: %v", err)
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if cfg.TLS.Token != "" {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(bearerToken(cfg.TLS.Token)))
+	}
+	conn, err := grpc.NewClient(cfg.ServerAddress, dialOptions...)
 	if err != nil {
 		log.Fatalf("Failed to connect to server at %s: %v", cfg.ServerAddress, err)
 	}
@@ -89,9 +104,10 @@ func main() {
 
 		clusters = append(clusters, &l2sces.Cluster{
 			Name: c.Name,
+			// Both can be left empty for clusters registered in the management cluster
 			RestConfig: &l2sces.RestConfig{
 				BearerToken: c.BearerToken,
-				ApiKey:      c.ApiKey,
+				Server:      c.ApiKey,
 			},
 			Overlay: &l2sces.Overlay{
 				// If you have pre-defined links, set them here. Otherwise they get generated automatically.
@@ -126,6 +142,9 @@ func main() {
 			log.Fatalf("Failed to create slice: %v", err)
 		}
 		fmt.Printf("CreateSlice response: %s\n", resp.GetMessage())
+		for _, result := range resp.GetClusters() {
+			fmt.Printf("Cluster %s: %s %v\n", result.GetCluster(), result.GetState(), result.GetObjects())
+		}
 	}
 
 	// 2) Test Slice Delete
@@ -147,6 +166,9 @@ func main() {
 			log.Fatalf("Failed to delete slice: %v", err)
 		}
 		fmt.Printf("DeleteSlice response: %s\n", resp.GetMessage())
+		for _, result := range resp.GetClusters() {
+			fmt.Printf("Cluster %s: %s %v\n", result.GetCluster(), result.GetState(), result.GetObjects())
+		}
 	}
 
 	// 3) Test Network Create
@@ -170,6 +192,9 @@ func main() {
 			log.Fatalf("Failed to create network: %v", err)
 		}
 		fmt.Printf("CreateNetwork response: %s\n", res.GetMessage())
+		for _, result := range res.GetClusters() {
+			fmt.Printf("Cluster %s: %s %v\n", result.GetCluster(), result.GetState(), result.GetObjects())
+		}
 		fmt.Printf("Please append the following fields to your workload: %s\n", res.GetPatches())
 	}
 
@@ -193,5 +218,38 @@ func main() {
 			log.Fatalf("Failed to delete network: %v", err)
 		}
 		fmt.Printf("DeleteNetwork response: %s\n", res.GetMessage())
+		for _, result := range res.GetClusters() {
+			fmt.Printf("Cluster %s: %s %v\n", result.GetCluster(), result.GetState(), result.GetObjects())
+		}
+	}
+
+	// 5) Test List
+	if *testList {
+		networks, err := client.ListNetworks(ctx, &l2sces.ListNetworksRequest{})
+		if err != nil {
+			log.Fatalf("Failed to list networks: %v", err)
+		}
+		for _, record := range networks.GetNetworks() {
+			fmt.Printf("Network %s in namespace %s: %d clusters\n", record.GetNetwork().GetName(), record.GetNamespace(), len(record.GetNetwork().GetClusters()))
+		}
+
+		slices, err := client.ListSlices(ctx, &l2sces.ListSlicesRequest{})
+		if err != nil {
+			log.Fatalf("Failed to list slices: %v", err)
+		}
+		for _, record := range slices.GetSlices() {
+			fmt.Printf("Slice %s in namespace %s: %d clusters\n", record.GetSlice().GetName(), record.GetNamespace(), len(record.GetSlice().GetClusters()))
+		}
 	}
 }
+
+// bearerToken sends a token in the authorization metadata of every call. It is never sent in plain text.
+type bearerToken string
+
+func (token bearerToken) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(token)}, nil
+}
+
+func (token bearerToken) RequireTransportSecurity() bool {
+	return true
+}
@@ -23,6 +23,7 @@ import (
 // Config holds all your configuration parameters.
 type Config struct {
 	ServerAddress string          `yaml:"serverAddress"`
+	TLS           TLSConfig       `yaml:"tls"`
 	NetworkName   string          `yaml:"networkName"`
 	Provider      ProviderConfig  `yaml:"provider"`
 	Clusters      []ClusterConfig `yaml:"clusters"`
@@ -30,6 +31,16 @@ type Config struct {
 	Namespace     string          `yaml:"namespace"`
 }
 
+type TLSConfig struct {
+	CAFile     string `yaml:"caFile"`
+	CertFile   string `yaml:"certFile"`
+	KeyFile    string `yaml:"keyFile"`
+	ServerName string `yaml:"serverName"`
+	Insecure   bool   `yaml:"insecure"`
+	// Token is sent as bearer token to authenticate with the server, instead of a client certificate.
+	Token string `yaml:"token"`
+}
+
 type ProviderConfig struct {
 	Name        string `yaml:"name"`
 	Domain      string `yaml:"domain"`